@@ -23,11 +23,72 @@ import (
 var (
 	CTDescriptionLabel                    = "clustertemplates.openshift.io/description"
 	ClusterProviderExperimentalAnnotation = "clustertemplate.openshift.io/experimental-provider"
+	// ClusterProviderAnnotation was used to configure the generic cluster provider, it is rejected on
+	// instances since the provider is configured only by the clusterProvider of the template
+	ClusterProviderAnnotation = "clustertemplate.openshift.io/cluster-provider"
 )
 
+// ClusterProviderConfig describes the resource which represents the cluster among the resources of
+// the day1 application and how the readiness and credentials of the cluster are read from it
+type ClusterProviderConfig struct {
+	// +optional
+	// Group of the resource, empty for the core group
+	Group string `json:"group,omitempty"`
+
+	//+kubebuilder:validation:MinLength=1
+	// Version of the resource
+	Version string `json:"version"`
+
+	//+kubebuilder:validation:MinLength=1
+	// Kind of the resource
+	Kind string `json:"kind"`
+
+	// +optional
+	// Type of the condition in status.conditions of the resource which is True once the cluster is ready
+	ReadyCondition string `json:"readyCondition,omitempty"`
+
+	// +optional
+	// JSONPath expression (ie '{.status.ready}') evaluated against the resource. The cluster is ready
+	// once the result equals readyValue
+	ReadyJSONPath string `json:"readyJSONPath,omitempty"`
+
+	// +optional
+	// Expected result of readyJSONPath, 'true' if not set
+	ReadyValue string `json:"readyValue,omitempty"`
+
+	// Secret which contains the kubeconfig of the cluster
+	KubeconfigSecretRef ClusterProviderSecretRef `json:"kubeconfigSecretRef"`
+
+	// +optional
+	// Secret which contains the admin credentials of the cluster
+	AdminCredentialsSecretRef *ClusterProviderSecretRef `json:"adminCredentialsSecretRef,omitempty"`
+}
+
+// ClusterProviderSecretRef references a secret of the cluster in the namespace of the resource of
+// the cluster. Name is a JSONPath template evaluated against the resource (ie '{.metadata.name}-kubeconfig')
+type ClusterProviderSecretRef struct {
+	//+kubebuilder:validation:MinLength=1
+	// Name of the secret
+	Name string `json:"name"`
+
+	// +optional
+	// Key of the kubeconfig ('kubeconfig' if not set) or of the admin password ('password' if not set)
+	Key string `json:"key,omitempty"`
+
+	// +optional
+	// Key of the admin username, 'kubeadmin' is used as the username if not set
+	UsernameKey string `json:"usernameKey,omitempty"`
+}
+
 type ClusterTemplateSpec struct {
-	// ArgoCD applicationset name which is used for installation of the cluster
-	ClusterDefinition string `json:"clusterDefinition"`
+	// +optional
+	// Name of the base ClusterTemplate. Fields which are not set are inherited from the base template,
+	// clusterSetup is appended to the clusterSetup of the base template
+	BaseTemplate string `json:"baseTemplate,omitempty"`
+
+	// +optional
+	// ArgoCD applicationset name which is used for installation of the cluster. Required unless it is inherited from the base template
+	ClusterDefinition string `json:"clusterDefinition,omitempty"`
 
 	// Skip the registration of the cluster to the hub cluster
 	SkipClusterRegistration bool `json:"skipClusterRegistration,omitempty"`
@@ -40,6 +101,77 @@ type ClusterTemplateSpec struct {
 	//+kubebuilder:validation:Minimum=0
 	// Cost of the cluster, used for quotas
 	Cost *int `json:"cost,omitempty"`
+
+	// +optional
+	//+kubebuilder:validation:Minimum=0
+	// Cost of every started hour of the cluster, accumulated by quotas with a billing period
+	CostPerHour *int `json:"costPerHour,omitempty"`
+
+	// +optional
+	// Generic cluster provider which reads the status and credentials of the cluster from a resource
+	// of the cluster definition. Needed only for clusters which are not created by HyperShift, Hive
+	// or Cluster API
+	ClusterProvider *ClusterProviderConfig `json:"clusterProvider,omitempty"`
+
+	// +optional
+	// Instances of the template are not provisioned until they are approved by a
+	// ClusterTemplateInstanceApproval
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+
+	// +optional
+	// Versions of the template. An instance can pin one of the versions, otherwise clusterDefinition
+	// and clusterSetup of the template are used
+	Versions []ClusterTemplateVersion `json:"versions,omitempty"`
+
+	// +optional
+	// Default values of helm chart params. Parameters of the instance take precedence
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+
+	// +optional
+	// Releases to which the clusters of the template can be upgraded via spec.upgrade of the instance
+	Upgrades *ClusterTemplateUpgrades `json:"upgrades,omitempty"`
+}
+
+type ClusterTemplateUpgrades struct {
+	// Name of the helm chart param of the cluster definition which is set to the release image of
+	// the target release
+	ReleaseImageParameter string `json:"releaseImageParameter"`
+	// Releases to which the clusters can be upgraded
+	Versions []ClusterTemplateUpgradeVersion `json:"versions"`
+}
+
+type ClusterTemplateUpgradeVersion struct {
+	// Version of the release, e.g. 4.12.1
+	Version string `json:"version"`
+	// Release image of the version, e.g. quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64
+	ReleaseImage string `json:"releaseImage"`
+}
+
+type ClusterTemplateParameter struct {
+	// Name of a helm chart param
+	Name string `json:"name"`
+	// Value of a helm chart param
+	Value string `json:"value"`
+	// +optional
+	// Name of the cluster setup applicationset to which the param is applied. Empty refers to the cluster definition
+	ApplicationSet string `json:"clusterSetup,omitempty"`
+}
+
+type ClusterTemplateVersion struct {
+	// Name of the version
+	Name string `json:"name"`
+
+	// +optional
+	// ArgoCD applicationset name which is used for installation of the cluster. Defaults to clusterDefinition of the template
+	ClusterDefinition string `json:"clusterDefinition,omitempty"`
+
+	// +optional
+	// Source revision of the cluster definition, overrides targetRevision of the applicationset
+	Revision string `json:"revision,omitempty"`
+
+	// +optional
+	// Array of ArgoCD applicationset names which are used for post installation setup of the cluster
+	ClusterSetup []string `json:"clusterSetup,omitempty"`
 }
 
 type ClusterTemplateParams struct {
@@ -69,6 +201,21 @@ type ClusterTemplateStatus struct {
 	// Describes helm chart properties and schema for every cluster setup step
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	ClusterSetup []ClusterSetupSchema `json:"clusterSetup,omitempty"`
+	// Describes helm chart properties and schema of every version of the template
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Versions []ClusterTemplateVersionStatus `json:"versions,omitempty"`
+	// Spec of the template merged with the specs of its base templates
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	EffectiveSpec *ClusterTemplateSpec `json:"effectiveSpec,omitempty"`
+}
+
+type ClusterTemplateVersionStatus struct {
+	// Name of the version
+	Name string `json:"name"`
+	// Describes helm chart properties and their schema
+	ClusterDefinition ClusterDefinitionSchema `json:"clusterDefinition,omitempty"`
+	// Describes helm chart properties and schema for every cluster setup step
+	ClusterSetup []ClusterSetupSchema `json:"clusterSetup,omitempty"`
 }
 
 //+kubebuilder:object:root=true
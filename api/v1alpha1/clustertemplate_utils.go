@@ -0,0 +1,192 @@
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/exp/slices"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetEffectiveSpec returns the spec of the template merged with the specs of its base templates.
+// Falls back to the spec when the template has no base template or the effective spec was not
+// resolved yet.
+func (t *ClusterTemplate) GetEffectiveSpec() *ClusterTemplateSpec {
+	if t.Spec.BaseTemplate == "" || t.Status.EffectiveSpec == nil {
+		return &t.Spec
+	}
+	return t.Status.EffectiveSpec
+}
+
+// ResolveEffectiveSpec walks the chain of base templates and merges their specs, the template
+// takes precedence over its base templates.
+func ResolveEffectiveSpec(
+	ctx context.Context,
+	k8sClient client.Client,
+	template *ClusterTemplate,
+) (*ClusterTemplateSpec, error) {
+	spec := template.Spec.DeepCopy()
+	visited := map[string]bool{template.Name: true}
+	for spec.BaseTemplate != "" {
+		if visited[spec.BaseTemplate] {
+			return nil, fmt.Errorf(
+				"cycle detected in base templates of cluster template '%s'",
+				template.Name,
+			)
+		}
+		visited[spec.BaseTemplate] = true
+
+		base := &ClusterTemplate{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: spec.BaseTemplate}, base); err != nil {
+			return nil, fmt.Errorf(
+				"failed to get base template '%s' - %q",
+				spec.BaseTemplate,
+				err,
+			)
+		}
+		spec = mergeTemplateSpecs(&base.Spec, spec)
+	}
+
+	if spec.ClusterDefinition == "" {
+		return nil, fmt.Errorf(
+			"neither cluster template '%s' nor its base templates define clusterDefinition",
+			template.Name,
+		)
+	}
+	return spec, nil
+}
+
+// mergeTemplateSpecs returns the spec of the base template overridden by the spec of the template.
+// Cluster setup of the template is appended to the cluster setup of the base template.
+func mergeTemplateSpecs(base *ClusterTemplateSpec, spec *ClusterTemplateSpec) *ClusterTemplateSpec {
+	merged := base.DeepCopy()
+	if spec.ClusterDefinition != "" {
+		merged.ClusterDefinition = spec.ClusterDefinition
+	}
+	merged.SkipClusterRegistration = base.SkipClusterRegistration || spec.SkipClusterRegistration
+	merged.RequiresApproval = base.RequiresApproval || spec.RequiresApproval
+	for _, setup := range spec.ClusterSetup {
+		if !slices.Contains(merged.ClusterSetup, setup) {
+			merged.ClusterSetup = append(merged.ClusterSetup, setup)
+		}
+	}
+	if spec.Cost != nil {
+		cost := *spec.Cost
+		merged.Cost = &cost
+	}
+	if spec.CostPerHour != nil {
+		costPerHour := *spec.CostPerHour
+		merged.CostPerHour = &costPerHour
+	}
+	if len(spec.Versions) > 0 {
+		merged.Versions = make([]ClusterTemplateVersion, len(spec.Versions))
+		for index := range spec.Versions {
+			spec.Versions[index].DeepCopyInto(&merged.Versions[index])
+		}
+	}
+	if spec.Upgrades != nil {
+		merged.Upgrades = spec.Upgrades.DeepCopy()
+	}
+	if spec.ClusterProvider != nil {
+		merged.ClusterProvider = spec.ClusterProvider.DeepCopy()
+	}
+	for _, param := range spec.Parameters {
+		found := false
+		for index, baseParam := range merged.Parameters {
+			if baseParam.Name == param.Name && baseParam.ApplicationSet == param.ApplicationSet {
+				merged.Parameters[index].Value = param.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Parameters = append(merged.Parameters, param)
+		}
+	}
+	return merged
+}
+
+// GetVersion returns the version of the template with the defaults applied. Empty name refers to
+// clusterDefinition and clusterSetup of the template.
+func (t *ClusterTemplate) GetVersion(name string) (*ClusterTemplateVersion, error) {
+	spec := t.GetEffectiveSpec()
+	if name == "" {
+		return &ClusterTemplateVersion{
+			ClusterDefinition: spec.ClusterDefinition,
+			ClusterSetup:      spec.ClusterSetup,
+		}, nil
+	}
+	for _, templateVersion := range spec.Versions {
+		if templateVersion.Name == name {
+			version := templateVersion.DeepCopy()
+			if version.ClusterDefinition == "" {
+				version.ClusterDefinition = spec.ClusterDefinition
+			}
+			return version, nil
+		}
+	}
+	return nil, fmt.Errorf("version '%s' of cluster template '%s' not found", name, t.Name)
+}
+
+// ResolveUpgrade returns the release of the allowed upgrades which matches the version and the
+// release image of the target
+func (t *ClusterTemplate) ResolveUpgrade(target ClusterUpgradeTarget) (*ClusterUpgradeStatus, error) {
+	if target.Version == "" && target.ReleaseImage == "" {
+		return nil, fmt.Errorf("either version or release image of the upgrade is required")
+	}
+	upgrades := t.GetEffectiveSpec().Upgrades
+	if upgrades == nil {
+		return nil, fmt.Errorf("cluster template '%s' does not allow upgrades", t.Name)
+	}
+	for _, version := range upgrades.Versions {
+		if (target.Version == "" || target.Version == version.Version) &&
+			(target.ReleaseImage == "" || target.ReleaseImage == version.ReleaseImage) {
+			return &ClusterUpgradeStatus{
+				Version:      version.Version,
+				ReleaseImage: version.ReleaseImage,
+				Parameter:    upgrades.ReleaseImageParameter,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("upgrade is not allowed by cluster template '%s'", t.Name)
+}
+
+// GetVersionStatus returns helm chart properties and schemas of the version, nil if they were
+// not computed yet. Empty name refers to clusterDefinition and clusterSetup of the template.
+func (t *ClusterTemplate) GetVersionStatus(name string) *ClusterTemplateVersionStatus {
+	if name == "" {
+		return &ClusterTemplateVersionStatus{
+			ClusterDefinition: t.Status.ClusterDefinition,
+			ClusterSetup:      t.Status.ClusterSetup,
+		}
+	}
+	for index := range t.Status.Versions {
+		if t.Status.Versions[index].Name == name {
+			return &t.Status.Versions[index]
+		}
+	}
+	return nil
+}
+
+// Validate checks the parts of the generic cluster provider config which are not validated by the CRD
+func (c *ClusterProviderConfig) Validate() error {
+	if (c.ReadyCondition == "") == (c.ReadyJSONPath == "") {
+		return fmt.Errorf(
+			"cluster provider config requires exactly one of readyCondition and readyJSONPath",
+		)
+	}
+	templates := []string{c.ReadyJSONPath, c.KubeconfigSecretRef.Name}
+	if c.AdminCredentialsSecretRef != nil {
+		templates = append(templates, c.AdminCredentialsSecretRef.Name)
+	}
+	for _, template := range templates {
+		if template == "" {
+			continue
+		}
+		if err := jsonpath.New("").Parse(template); err != nil {
+			return fmt.Errorf("invalid JSONPath %q of cluster provider config - %q", template, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplate utils", func() {
+	ct := ClusterTemplate{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: ClusterTemplateSpec{
+			ClusterDefinition: "foo-appset",
+			ClusterSetup:      []string{"setup"},
+			Versions: []ClusterTemplateVersion{
+				{
+					Name:     "v1",
+					Revision: "0.1.0",
+				},
+				{
+					Name:              "v2",
+					ClusterDefinition: "bar-appset",
+					ClusterSetup:      []string{"setup", "setup2"},
+				},
+			},
+		},
+		Status: ClusterTemplateStatus{
+			Versions: []ClusterTemplateVersionStatus{
+				{
+					Name: "v1",
+					ClusterDefinition: ClusterDefinitionSchema{
+						Values: "foo: bar",
+					},
+				},
+			},
+		},
+	}
+
+	It("GetVersion", func() {
+		version, err := ct.GetVersion("")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(version.ClusterDefinition).Should(Equal("foo-appset"))
+		Expect(version.ClusterSetup).Should(Equal([]string{"setup"}))
+
+		version, err = ct.GetVersion("v1")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(version.ClusterDefinition).Should(Equal("foo-appset"))
+		Expect(version.Revision).Should(Equal("0.1.0"))
+		Expect(version.ClusterSetup).Should(BeEmpty())
+
+		version, err = ct.GetVersion("v2")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(version.ClusterDefinition).Should(Equal("bar-appset"))
+		Expect(version.ClusterSetup).Should(Equal([]string{"setup", "setup2"}))
+
+		_, err = ct.GetVersion("v3")
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("ResolveUpgrade", func() {
+		_, err := ct.ResolveUpgrade(ClusterUpgradeTarget{Version: "4.12.1"})
+		Expect(err).Should(HaveOccurred())
+
+		template := ct.DeepCopy()
+		template.Spec.Upgrades = &ClusterTemplateUpgrades{
+			ReleaseImageParameter: "releaseImage",
+			Versions: []ClusterTemplateUpgradeVersion{
+				{
+					Version:      "4.12.1",
+					ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+				},
+				{
+					Version:      "4.12.2",
+					ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+				},
+			},
+		}
+
+		upgrade, err := template.ResolveUpgrade(ClusterUpgradeTarget{Version: "4.12.2"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(*upgrade).Should(Equal(ClusterUpgradeStatus{
+			Version:      "4.12.2",
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+			Parameter:    "releaseImage",
+		}))
+
+		upgrade, err = template.ResolveUpgrade(ClusterUpgradeTarget{
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(upgrade.Version).Should(Equal("4.12.1"))
+
+		_, err = template.ResolveUpgrade(ClusterUpgradeTarget{
+			Version:      "4.12.1",
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+		})
+		Expect(err).Should(HaveOccurred())
+		_, err = template.ResolveUpgrade(ClusterUpgradeTarget{Version: "4.13.0"})
+		Expect(err).Should(HaveOccurred())
+		_, err = template.ResolveUpgrade(ClusterUpgradeTarget{})
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("GetVersionStatus", func() {
+		Expect(ct.GetVersionStatus("v1").ClusterDefinition.Values).Should(Equal("foo: bar"))
+		Expect(ct.GetVersionStatus("v2")).Should(BeNil())
+		Expect(ct.GetVersionStatus("")).ShouldNot(BeNil())
+	})
+
+	It("ResolveEffectiveSpec", func() {
+		base := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "base",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterDefinition: "base-appset",
+				ClusterSetup:      []string{"setup"},
+				Cost:              pointer.Int(1),
+				ClusterProvider: &ClusterProviderConfig{
+					Version:        "v1alpha1",
+					Kind:           "VCluster",
+					ReadyCondition: "Ready",
+				},
+				Parameters: []ClusterTemplateParameter{
+					{
+						Name:  "region",
+						Value: "us-east-1",
+					},
+					{
+						Name:  "size",
+						Value: "small",
+					},
+				},
+			},
+		}
+		child := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "child",
+			},
+			Spec: ClusterTemplateSpec{
+				BaseTemplate: "base",
+				ClusterSetup: []string{"setup", "setup2"},
+				Cost:         pointer.Int(5),
+				Parameters: []ClusterTemplateParameter{
+					{
+						Name:  "size",
+						Value: "large",
+					},
+					{
+						Name:           "channel",
+						Value:          "stable",
+						ApplicationSet: "setup2",
+					},
+				},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, base, child)
+
+		spec, err := ResolveEffectiveSpec(ctx, client, child)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(spec.BaseTemplate).Should(BeEmpty())
+		Expect(spec.ClusterDefinition).Should(Equal("base-appset"))
+		Expect(spec.ClusterSetup).Should(Equal([]string{"setup", "setup2"}))
+		Expect(*spec.Cost).Should(Equal(5))
+		Expect(spec.Parameters).Should(Equal([]ClusterTemplateParameter{
+			{
+				Name:  "region",
+				Value: "us-east-1",
+			},
+			{
+				Name:  "size",
+				Value: "large",
+			},
+			{
+				Name:           "channel",
+				Value:          "stable",
+				ApplicationSet: "setup2",
+			},
+		}))
+		Expect(spec.ClusterProvider.Kind).Should(Equal("VCluster"))
+		Expect(*base.Spec.Cost).Should(Equal(1))
+		Expect(base.Spec.ClusterSetup).Should(Equal([]string{"setup"}))
+
+		child.Status.EffectiveSpec = spec
+		Expect(child.GetEffectiveSpec().ClusterDefinition).Should(Equal("base-appset"))
+		version, err := child.GetVersion("")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(version.ClusterSetup).Should(Equal([]string{"setup", "setup2"}))
+	})
+
+	It("ResolveEffectiveSpec fails on cycle", func() {
+		first := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "first",
+			},
+			Spec: ClusterTemplateSpec{
+				BaseTemplate: "second",
+			},
+		}
+		second := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "second",
+			},
+			Spec: ClusterTemplateSpec{
+				BaseTemplate:      "first",
+				ClusterDefinition: "appset",
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, first, second)
+
+		_, err := ResolveEffectiveSpec(ctx, client, first)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("cycle detected"))
+	})
+
+	It("ResolveEffectiveSpec fails without cluster definition", func() {
+		template := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, template)
+
+		_, err := ResolveEffectiveSpec(ctx, client, template)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("ClusterProviderConfig Validate", func() {
+		config := &ClusterProviderConfig{
+			Group:          "controlplane.cluster.x-k8s.io",
+			Version:        "v1alpha1",
+			Kind:           "KamajiControlPlane",
+			ReadyCondition: "Ready",
+			KubeconfigSecretRef: ClusterProviderSecretRef{
+				Name: "{.metadata.name}-admin-kubeconfig",
+				Key:  "admin.conf",
+			},
+			AdminCredentialsSecretRef: &ClusterProviderSecretRef{
+				Name:        "admin",
+				UsernameKey: "user",
+			},
+		}
+		Expect(config.Validate()).Should(Succeed())
+
+		invalid := config.DeepCopy()
+		invalid.ReadyJSONPath = "{.status.ready}"
+		Expect(invalid.Validate()).ShouldNot(Succeed())
+
+		invalid.ReadyCondition = ""
+		invalid.ReadyJSONPath = "{.status.ready"
+		Expect(invalid.Validate()).ShouldNot(Succeed())
+
+		invalid = config.DeepCopy()
+		invalid.ReadyCondition = ""
+		Expect(invalid.Validate()).ShouldNot(Succeed())
+
+		invalid = config.DeepCopy()
+		invalid.AdminCredentialsSecretRef.Name = "{.metadata.name"
+		Expect(invalid.Validate()).ShouldNot(Succeed())
+	})
+})
@@ -0,0 +1,96 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type ClusterQuotaAllowedTemplate struct {
+	// Name of the ClusterTemplate
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// Defines how many instances of the ClusterTemplate can exist in all selected namespaces
+	Count int `json:"count,omitempty"`
+}
+
+type ClusterTemplateClusterQuotaSpec struct {
+	// Selects the namespaces the quota applies to
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector"`
+	//+kubebuilder:validation:Minimum=1
+	// +optional
+	// Total budget for all clusters within the selected namespaces
+	Budget int `json:"budget,omitempty"`
+	// Represents all ClusterTemplates which can be used in the selected namespaces
+	AllowedTemplates []ClusterQuotaAllowedTemplate `json:"allowedTemplates"`
+}
+
+type ClusterQuotaNamespaceStatus struct {
+	// Name of the namespace
+	Namespace string `json:"namespace"`
+	// How much budget is currently spent in the namespace
+	BudgetSpent int `json:"budgetSpent"`
+	// Which instances are in use in the namespace
+	TemplateInstances []AllowedTemplateStatus `json:"templateInstances"`
+}
+
+type ClusterTemplateClusterQuotaStatus struct {
+	// How much budget is currently spent in all selected namespaces
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	BudgetSpent int `json:"budgetSpent"`
+	// Which instances are in use in all selected namespaces
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	TemplateInstances []AllowedTemplateStatus `json:"templateInstances"`
+	// Spent budget and instances in use broken down by namespace
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Namespaces []ClusterQuotaNamespaceStatus `json:"namespaces,omitempty"`
+	// Admitted instances which are not counted yet
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Reservations []QuotaReservation `json:"reservations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustertemplateclusterquotas,shortName=ctcq;ctcqs,scope=Cluster
+//+kubebuilder:printcolumn:name="Budget",type="integer",JSONPath=".spec.budget",description="Total budget"
+//+kubebuilder:printcolumn:name="Spent",type="integer",JSONPath=".status.budgetSpent",description="Spent budget"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template cluster quota",resources={{Pod, v1, ""}}
+
+// Defines which ClusterTemplates can be used in the namespaces selected by a label selector
+type ClusterTemplateClusterQuota struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTemplateClusterQuotaSpec   `json:"spec,omitempty"`
+	Status ClusterTemplateClusterQuotaStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateClusterQuotaList contains a list of ClusterTemplateClusterQuota
+type ClusterTemplateClusterQuotaList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateClusterQuota `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateClusterQuota{}, &ClusterTemplateClusterQuotaList{})
+}
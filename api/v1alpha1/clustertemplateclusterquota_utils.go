@@ -0,0 +1,26 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// SelectsNamespace returns true if the quota applies to the given namespace
+func (q *ClusterTemplateClusterQuota) SelectsNamespace(namespace *corev1.Namespace) (bool, error) {
+	selector, err := metav1.LabelSelectorAsSelector(&q.Spec.NamespaceSelector)
+	if err != nil {
+		return false, err
+	}
+	return selector.Matches(labels.Set(namespace.Labels)), nil
+}
+
+// AllowsTemplate returns true if the template is allowed by the cluster quota
+func (q *ClusterTemplateClusterQuota) AllowsTemplate(name string) bool {
+	for _, allowedTemplate := range q.Spec.AllowedTemplates {
+		if allowedTemplate.Name == name {
+			return true
+		}
+	}
+	return false
+}
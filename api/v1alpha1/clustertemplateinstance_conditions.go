@@ -18,6 +18,8 @@ const (
 	ClusterSetupSucceeded    ConditionType = "ClusterSetupSucceeded"
 	Ready                    ConditionType = "Ready"
 	ConsoleURLRetrieved      ConditionType = "ConsoleURLRetrieved"
+	ParametersUpdated        ConditionType = "ParametersUpdated"
+	ClusterUpgrade           ConditionType = "ClusterUpgrade"
 )
 
 type ClusterDefinitionReason string
@@ -109,6 +111,28 @@ const (
 	ClusterSetupNotCreated   ClusterSetupSucceededReason = "ClusterSetupNotCreated"
 )
 
+type ParametersUpdatedReason string
+
+const (
+	ParametersUpdating     ParametersUpdatedReason = "ParametersUpdating"
+	ParametersUpdateFailed ParametersUpdatedReason = "ParametersUpdateFailed"
+	ParametersSyncFailed   ParametersUpdatedReason = "ParametersSyncFailed"
+	ParametersApplied      ParametersUpdatedReason = "ParametersApplied"
+	TemplateRebased        ParametersUpdatedReason = "TemplateRebased"
+	UpgradeResolved        ParametersUpdatedReason = "UpgradeResolved"
+)
+
+type ClusterUpgradeReason string
+
+const (
+	UpgradeNotAllowed      ClusterUpgradeReason = "UpgradeNotAllowed"
+	UpgradePending         ClusterUpgradeReason = "UpgradePending"
+	ClusterUpgrading       ClusterUpgradeReason = "ClusterUpgrading"
+	ClusterUpgradeFailed   ClusterUpgradeReason = "ClusterUpgradeFailed"
+	ClusterUpgraded        ClusterUpgradeReason = "ClusterUpgraded"
+	UpgradeProgressUnknown ClusterUpgradeReason = "UpgradeProgressUnknown"
+)
+
 func (clusterInstance *ClusterTemplateInstance) SetClusterDefinitionCreatedCondition(
 	status metav1.ConditionStatus,
 	reason ClusterDefinitionReason,
@@ -235,6 +259,58 @@ func (clusterInstance *ClusterTemplateInstance) SetClusterSetupSucceededConditio
 	})
 }
 
+// SetParametersUpdatedCondition records the generation of the spec whose parameters are being rolled out
+func (clusterInstance *ClusterTemplateInstance) SetParametersUpdatedCondition(
+	status metav1.ConditionStatus,
+	reason ParametersUpdatedReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ParametersUpdated),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		ObservedGeneration: clusterInstance.Generation,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+func (clusterInstance *ClusterTemplateInstance) SetClusterUpgradeCondition(
+	status metav1.ConditionStatus,
+	reason ClusterUpgradeReason,
+	message string,
+) {
+	meta.SetStatusCondition(&clusterInstance.Status.Conditions, metav1.Condition{
+		Type:               string(ClusterUpgrade),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	})
+}
+
+// ParametersUpdateRequired returns true if the given hash of the parameters differs from the hash of the
+// parameters applied to the applications, or if the template snapshot changed since the applications were
+// generated. Instances which don't track the applied parameters yet fall back to the generation of the spec
+func (clusterInstance *ClusterTemplateInstance) ParametersUpdateRequired(parametersHash string) bool {
+	condition := meta.FindStatusCondition(
+		clusterInstance.Status.Conditions,
+		string(ParametersUpdated),
+	)
+	if condition != nil && (condition.Reason == string(ParametersUpdateFailed) ||
+		condition.Reason == string(TemplateRebased) ||
+		condition.Reason == string(UpgradeResolved)) {
+		return true
+	}
+	if clusterInstance.Status.ParametersHash != "" {
+		return clusterInstance.Status.ParametersHash != parametersHash
+	}
+	if condition == nil {
+		return clusterInstance.Generation > 1
+	}
+	return condition.ObservedGeneration != clusterInstance.Generation
+}
+
 func (clusterInstance *ClusterTemplateInstance) hasCondition(condition ConditionType) bool {
 	return meta.FindStatusCondition(
 		clusterInstance.Status.Conditions,
@@ -76,6 +76,51 @@ var _ = Describe("ClusterTemplateInstance conditions", func() {
 		testCondition(cti, ConsoleURLRetrieved, string(ConsoleURLFailed))
 	})
 
+	It("ParametersUpdateRequired", func() {
+		cti := ClusterTemplateInstance{}
+		cti.Generation = 1
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeFalse())
+
+		cti.Generation = 2
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeTrue())
+
+		cti.SetParametersUpdatedCondition(
+			metav1.ConditionFalse,
+			ParametersUpdating,
+			"foo",
+		)
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeFalse())
+
+		cti.SetParametersUpdatedCondition(
+			metav1.ConditionFalse,
+			ParametersUpdateFailed,
+			"foo",
+		)
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeTrue())
+
+		cti.SetParametersUpdatedCondition(
+			metav1.ConditionTrue,
+			ParametersApplied,
+			"foo",
+		)
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeFalse())
+
+		cti.Generation = 3
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeTrue())
+
+		// Once the applied parameters are tracked, only their change requires the update
+		cti.Status.ParametersHash = "foo"
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeFalse())
+		Expect(cti.ParametersUpdateRequired("bar")).To(BeTrue())
+
+		cti.SetParametersUpdatedCondition(
+			metav1.ConditionFalse,
+			TemplateRebased,
+			"foo",
+		)
+		Expect(cti.ParametersUpdateRequired("foo")).To(BeTrue())
+	})
+
 	It("PhaseCanExecute", func() {
 		cti := ClusterTemplateInstance{
 			Status: ClusterTemplateInstanceStatus{
@@ -23,31 +23,114 @@ import (
 )
 
 const (
-	CTIFinalizer           = "clustertemplateinstance.openshift.io/finalizer"
-	CTIRequesterAnnotation = "clustertemplates.openshift.io/requester"
-	CTINameLabel           = "clustertemplateinstance.openshift.io/name"
-	CTINamespaceLabel      = "clustertemplateinstance.openshift.io/namespace"
-	CTISetupLabel          = "clustertemplate.openshift.io/cluster-setup"
-	CTISetupSecretLabel    = "clustertemplate.openshift.io/cluster-setup-secret"
-	CTRepoLabel            = "clustertemplate.openshift.io/repository"
+	CTIFinalizer                 = "clustertemplateinstance.openshift.io/finalizer"
+	CTIRequesterAnnotation       = "clustertemplates.openshift.io/requester"
+	CTIRequesterGroupsAnnotation = "clustertemplates.openshift.io/requester-groups"
+	CTINameLabel                 = "clustertemplateinstance.openshift.io/name"
+	CTINamespaceLabel            = "clustertemplateinstance.openshift.io/namespace"
+	CTISetupLabel                = "clustertemplate.openshift.io/cluster-setup"
+	CTISetupSecretLabel          = "clustertemplate.openshift.io/cluster-setup-secret"
+	CTRepoLabel                  = "clustertemplate.openshift.io/repository"
+	CTIRebaseAnnotation          = "clustertemplateinstance.openshift.io/rebase-template"
+	CTIExtendAnnotation          = "clustertemplateinstance.openshift.io/extend-lifetime"
+	// Number of phase transitions which are kept in the status of the instance
+	MaxPhaseHistory = 30
 )
 
 type Parameter struct {
 	// Name of the Helm parameter
 	Name string `json:"name"`
 	// Value of the Helm parameter
-	Value string `json:"value"`
+	// +optional
+	Value string `json:"value,omitempty"`
+	// Source for the Helm parameter's value. Cannot be used if value is not empty.
+	// +optional
+	ValueFrom *ParameterValueSource `json:"valueFrom,omitempty"`
 	// Name of the application set to which parameter is applied
 	ApplicationSet string `json:"clusterSetup,omitempty"`
 }
 
+// ParameterValueSource represents a source for the value of a Parameter
+type ParameterValueSource struct {
+	// Selects a key of a secret in the instance's namespace
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// Selects a key of a config map in the instance's namespace
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// PowerState of the cluster
+// +kubebuilder:validation:Enum=Running;Hibernating
+type PowerState string
+
+const (
+	RunningPowerState     PowerState = "Running"
+	HibernatingPowerState PowerState = "Hibernating"
+)
+
+// Schedule of automatic power state changes of the cluster
+type PowerSchedule struct {
+	// Cron expression (ie "0 19 * * 1-5") of the times when the cluster is hibernated
+	Hibernate string `json:"hibernate"`
+	// Cron expression (ie "0 7 * * 1-5") of the times when the cluster is resumed
+	Resume string `json:"resume"`
+	// +optional
+	// IANA name of the time zone (ie "Europe/Prague") of the cron expressions. UTC is used if not specified
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+type PowerStateTransition struct {
+	// Power state which is requested
+	PowerState PowerState `json:"powerState"`
+	// Time of the transition
+	Time metav1.Time `json:"time"`
+}
+
 type ClusterTemplateInstanceSpec struct {
 	// A reference to a secret which contains kubeconfig of the cluster. If specified day1 operation won't be executed.
 	KubeconfigSecretRef *string `json:"kubeconfigSecretRef,omitempty"`
 	// A reference to ClusterTemplate which will be used for installing and setting up the cluster
 	ClusterTemplateRef string `json:"clusterTemplateRef"`
-	// Helm parameters to be passed to cluster installation or setup
+	// +optional
+	// Version of the ClusterTemplate. If not specified, clusterDefinition and clusterSetup of the template are used
+	ClusterTemplateVersion string `json:"clusterTemplateVersion,omitempty"`
+	// Helm parameters to be passed to cluster installation or setup. Parameters can be updated
+	// after the instance is created, the changes are propagated to the generated applications.
 	Parameters []Parameter `json:"parameters,omitempty"`
+	// +optional
+	// +kubebuilder:default=Running
+	// Desired power state of the cluster. Hibernating clusters are stopped by the cluster provider
+	// and can be resumed by setting the power state back to Running.
+	PowerState PowerState `json:"powerState,omitempty"`
+	// +optional
+	// Schedule of automatic hibernation and resume of the cluster. Takes precedence over the
+	// schedule defined by ClusterTemplateQuota.
+	PowerSchedule *PowerSchedule `json:"powerSchedule,omitempty"`
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	// Instance will be removed after specified time. Takes precedence over deleteAfter of the
+	// ClusterTemplateQuota and cannot exceed its maxLifetime. Set the extend-lifetime annotation
+	// to extend the lifetime of an existing instance.
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+	// +optional
+	// Priority of the instance in the queue of the ClusterTemplateQuota. Queued instances with
+	// higher priority are started first.
+	Priority int `json:"priority,omitempty"`
+	// +optional
+	// Target release of the cluster, has to be one of the upgrades allowed by the template. The
+	// upgrade cannot be removed once it is set.
+	Upgrade *ClusterUpgradeTarget `json:"upgrade,omitempty"`
+}
+
+type ClusterUpgradeTarget struct {
+	// +optional
+	// Target version of the cluster, e.g. 4.12.1
+	Version string `json:"version,omitempty"`
+	// +optional
+	// Target release image of the cluster. Either the version or the release image has to be set
+	ReleaseImage string `json:"releaseImage,omitempty"`
 }
 
 type ClusterSetupStatus struct {
@@ -64,6 +147,8 @@ type Phase string
 const (
 	PendingPhase                    Phase  = "Pending"
 	PendingMessage                  string = "Pending"
+	QueuedPhase                     Phase  = "Queued"
+	PendingApprovalPhase            Phase  = "PendingApproval"
 	ClusterDefinitionFailedPhase    Phase  = "ClusterDefinitionFailed"
 	ClusterInstallingPhase          Phase  = "ClusterInstalling"
 	ClusterInstallFailedPhase       Phase  = "ClusterInstallFailed"
@@ -81,6 +166,12 @@ const (
 	ClusterSetupRunningPhase        Phase  = "ClusterSetupRunning"
 	ReadyPhase                      Phase  = "Ready"
 	CredentialsFailedPhase          Phase  = "CredentialsFailed"
+	ParametersUpdateFailedPhase     Phase  = "ParametersUpdateFailed"
+	HibernatingPhase                Phase  = "Hibernating"
+	HibernatedPhase                 Phase  = "Hibernated"
+	ResumingPhase                   Phase  = "Resuming"
+	PowerStateFailedPhase           Phase  = "PowerStateFailed"
+	PooledPhase                     Phase  = "Pooled"
 	FailedPhase                     Phase  = "Failed"
 )
 
@@ -118,6 +209,136 @@ type ClusterTemplateInstanceStatus struct {
 	// Time of first attempt of login to a new cluster
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	FirstLoginAttempt *metav1.Time `json:"firstLoginAttempt,omitempty"`
+	// Template resolved on the first reconcile which is used for the whole life of the instance.
+	// Set the rebase-template annotation to take a new snapshot of the current template.
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	TemplateSnapshot *TemplateSnapshot `json:"templateSnapshot,omitempty"`
+	// Power state which the cluster reached
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	PowerState PowerState `json:"powerState,omitempty"`
+	// Next power state change requested by the power schedule
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NextPowerStateTransition *PowerStateTransition `json:"nextPowerStateTransition,omitempty"`
+	// Time when the instance is removed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Accepted extension of the lifetime of the instance
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	LifetimeExtension *metav1.Duration `json:"lifetimeExtension,omitempty"`
+	// Approval of the instance, set for instances of templates which require approval
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Approval *InstanceApproval `json:"approval,omitempty"`
+	// Recent phase transitions of the instance, the oldest ones are removed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	History []PhaseTransition `json:"history,omitempty"`
+	// Last phase whose notifications were added, the notifications of the current phase are
+	// retried until they are added
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	NotifiedPhase Phase `json:"notifiedPhase,omitempty"`
+	// Hash of the parameters, including the resolved values of their sources, which were last
+	// applied to the applications
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ParametersHash string `json:"parametersHash,omitempty"`
+	// Facts about the ready cluster reported by the cluster provider, refreshed periodically
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	ClusterInfo *ClusterInfo `json:"clusterInfo,omitempty"`
+	// Release to which the cluster is upgraded, resolved from the upgrades allowed by the template
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Upgrade *ClusterUpgradeStatus `json:"upgrade,omitempty"`
+}
+
+type ClusterUpgradeStatus struct {
+	// Target version of the cluster
+	Version string `json:"version"`
+	// Release image of the target version
+	ReleaseImage string `json:"releaseImage"`
+	// Helm param of the cluster definition which is set to the release image
+	Parameter string `json:"parameter"`
+}
+
+const (
+	// Control plane of the cluster runs as pods on the hub, i.e. HyperShift
+	HostedControlPlaneType = "Hosted"
+	// Control plane of the cluster runs on its own machines
+	StandaloneControlPlaneType = "Standalone"
+)
+
+type ClusterInfo struct {
+	// +optional
+	// OpenShift version of the cluster
+	OpenShiftVersion string `json:"openshiftVersion,omitempty"`
+	// +optional
+	// Kubernetes version of the cluster, reported by providers of non-OpenShift clusters
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+	// +optional
+	// Infrastructure platform of the cluster, e.g. AWS
+	Platform string `json:"platform,omitempty"`
+	// +optional
+	// Region of the platform in which the cluster runs
+	Region string `json:"region,omitempty"`
+	// +optional
+	// Type of the control plane - Hosted, Standalone or the kind of the control plane resource
+	ControlPlaneType string `json:"controlPlaneType,omitempty"`
+	// +optional
+	// Node pools of the cluster
+	NodePools []NodePoolInfo `json:"nodePools,omitempty"`
+	// Time when the info was refreshed
+	LastUpdated metav1.Time `json:"lastUpdated"`
+}
+
+type NodePoolInfo struct {
+	// Name of the node pool
+	Name string `json:"name"`
+	// +optional
+	// Instance type of the nodes
+	Size string `json:"size,omitempty"`
+	// Number of nodes in the pool
+	Replicas int32 `json:"replicas"`
+}
+
+type PhaseTransition struct {
+	// Phase which the instance entered
+	Phase Phase `json:"phase"`
+	// +optional
+	// Reason of the condition change which caused the transition
+	Reason string `json:"reason,omitempty"`
+	// +optional
+	// Message of the phase
+	Message string `json:"message,omitempty"`
+	// Time when the instance entered the phase
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+type InstanceApproval struct {
+	// User who approved the instance
+	ApprovedBy string `json:"approvedBy"`
+	// Time when the instance was approved
+	ApprovedAt metav1.Time `json:"approvedAt"`
+}
+
+type TemplateSnapshot struct {
+	// Version of the template
+	Version string `json:"version,omitempty"`
+	// ApplicationSet name which is used for installing the cluster
+	ClusterDefinition string `json:"clusterDefinition,omitempty"`
+	// Array of ApplicationSet names which are used for setting up the cluster
+	ClusterSetup []string `json:"clusterSetup,omitempty"`
+	// Cost of the template
+	Cost *int `json:"cost,omitempty"`
+	// Cost of every started hour of the cluster
+	CostPerHour *int `json:"costPerHour,omitempty"`
+	// Skip the registration of the cluster to the hub cluster
+	SkipClusterRegistration bool `json:"skipClusterRegistration,omitempty"`
+	// Labels of the template
+	Labels map[string]string `json:"labels,omitempty"`
+	// Source revision of each ApplicationSet, keyed by the ApplicationSet name
+	Revisions map[string]string `json:"revisions,omitempty"`
+	// Default values of helm chart params defined by the template
+	Parameters []ClusterTemplateParameter `json:"parameters,omitempty"`
+	// Generic cluster provider of the template
+	ClusterProvider *ClusterProviderConfig `json:"clusterProvider,omitempty"`
+	// Time when the snapshot was taken
+	Timestamp metav1.Time `json:"timestamp,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -18,16 +18,22 @@ package v1alpha1
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
 
 	"golang.org/x/exp/slices"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/kubernetes-client/go-base/config/api"
+	"github.com/robfig/cron"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -131,6 +137,50 @@ func (i *ClusterTemplateInstance) DeleteDay1Application(
 	return k8sClient.Update(ctx, appSet)
 }
 
+// MoveDay1Application hands over the cluster definition application to the target instance
+func (i *ClusterTemplateInstance) MoveDay1Application(
+	ctx context.Context,
+	k8sClient client.Client,
+	argoCDNamespace string,
+	clusterDefinition string,
+	target *ClusterTemplateInstance,
+) error {
+	appSet := &argo.ApplicationSet{}
+	if err := k8sClient.Get(
+		ctx,
+		types.NamespacedName{Name: clusterDefinition, Namespace: argoCDNamespace},
+		appSet,
+	); err != nil {
+		return err
+	}
+
+	moved := false
+	for _, g := range appSet.Spec.Generators {
+		if g.List != nil && g.List.Template.Labels[CTINameLabel] == i.Name && g.List.Template.Labels[CTINamespaceLabel] == i.Namespace {
+			g.List.Template.Labels[CTINameLabel] = target.Name
+			g.List.Template.Labels[CTINamespaceLabel] = target.Namespace
+			moved = true
+		}
+	}
+	if moved {
+		if err := k8sClient.Update(ctx, appSet); err != nil {
+			return err
+		}
+	}
+
+	// Relabel the application right away, so the target instance finds it before the ApplicationSet is synced
+	app, err := i.GetDay1Application(ctx, k8sClient, argoCDNamespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	app.Labels[CTINameLabel] = target.Name
+	app.Labels[CTINamespaceLabel] = target.Namespace
+	return k8sClient.Update(ctx, app)
+}
+
 func (i *ClusterTemplateInstance) DeleteDay2Application(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -168,12 +218,6 @@ func (i *ClusterTemplateInstance) UpdateApplicationSet(
 	server string,
 	isDay2 bool,
 ) error {
-	for _, g := range appSet.Spec.Generators {
-		if g.List != nil && g.List.Template.Labels[CTINameLabel] == i.Name && g.List.Template.Labels[CTINamespaceLabel] == i.Namespace {
-			return nil
-		}
-	}
-
 	name := string(i.UID)
 	if isDay2 {
 		name = name + "-" + appSet.Name
@@ -202,7 +246,7 @@ func (i *ClusterTemplateInstance) UpdateApplicationSet(
 	}
 
 	if appSet.Spec.Template.Spec.Source.Chart != "" {
-		params, err := i.GetHelmParameters(appSet, isDay2)
+		params, err := i.GetHelmParameters(ctx, k8sClient, appSet, isDay2)
 		if err != nil {
 			return err
 		}
@@ -216,13 +260,199 @@ func (i *ClusterTemplateInstance) UpdateApplicationSet(
 		}
 	}
 
+	// Pin the source revision recorded in the template snapshot, so the changes of the ApplicationSet
+	// don't affect already existing instances
+	if revision := i.getSnapshotRevision(appSet.Name); revision != "" {
+		gen.List.Template.Spec.Source.TargetRevision = revision
+	}
+
 	if isDay2 {
 		gen.List.Template.ApplicationSetTemplateMeta.Labels[CTISetupLabel] = ""
 	}
+
+	// Generator already exists, only sync the parameters which could be updated since it was created
+	for index, g := range appSet.Spec.Generators {
+		if g.List != nil && g.List.Template.Labels[CTINameLabel] == i.Name && g.List.Template.Labels[CTINamespaceLabel] == i.Namespace {
+			if equality.Semantic.DeepEqual(g.List.Template.Spec, gen.List.Template.Spec) {
+				return nil
+			}
+			appSet.Spec.Generators[index].List.Template.Spec = gen.List.Template.Spec
+			return k8sClient.Update(ctx, appSet)
+		}
+	}
+
 	appSet.Spec.Generators = append(appSet.Spec.Generators, gen)
 	return k8sClient.Update(ctx, appSet)
 }
 
+// GetPowerState returns the desired power state of the cluster, Running if not specified
+func (i *ClusterTemplateInstance) GetPowerState() PowerState {
+	if i.Spec.PowerState == "" {
+		return RunningPowerState
+	}
+	return i.Spec.PowerState
+}
+
+// IsHibernated returns true if the cluster reached the Hibernating power state
+func (i *ClusterTemplateInstance) IsHibernated() bool {
+	return i.Status.PowerState == HibernatingPowerState
+}
+
+// GetLifetimeLimits returns the default and the maximum lifetime of the instance defined by the
+// quotas of its namespace
+func (i *ClusterTemplateInstance) GetLifetimeLimits(
+	ctx context.Context,
+	k8sClient client.Client,
+) (*time.Duration, *time.Duration, error) {
+	quotas := &ClusterTemplateQuotaList{}
+	if err := k8sClient.List(ctx, quotas, client.InNamespace(i.Namespace)); err != nil {
+		return nil, nil, err
+	}
+
+	var deleteAfter, maxLifetime *time.Duration
+	for _, quota := range quotas.Items {
+		for _, allowedTemplate := range quota.Spec.AllowedTemplates {
+			if allowedTemplate.Name != i.Spec.ClusterTemplateRef {
+				continue
+			}
+			if allowedTemplate.DeleteAfter != nil {
+				deleteAfter = &allowedTemplate.DeleteAfter.Duration
+			}
+			if limit := allowedTemplate.MaxLifetime; limit != nil && (maxLifetime == nil || limit.Duration < *maxLifetime) {
+				maxLifetime = &limit.Duration
+			}
+		}
+	}
+	return deleteAfter, maxLifetime, nil
+}
+
+// GetExpiresAt returns the time when the instance is removed, nil if the instance does not expire.
+// The lifetime requested by the instance takes precedence over the default lifetime, the accepted
+// extension is added and the total is capped by the maximum lifetime. The expiration is computed
+// from the current limits, so changes of the quotas apply to existing instances as well.
+func (i *ClusterTemplateInstance) GetExpiresAt(deleteAfter *time.Duration, maxLifetime *time.Duration) *metav1.Time {
+	lifetime := i.getLifetime(deleteAfter, maxLifetime)
+	if lifetime == nil {
+		return nil
+	}
+	if i.Status.LifetimeExtension != nil {
+		*lifetime += i.Status.LifetimeExtension.Duration
+	}
+	if maxLifetime != nil && *lifetime > *maxLifetime {
+		lifetime = maxLifetime
+	}
+	expiresAt := metav1.NewTime(i.CreationTimestamp.Add(*lifetime))
+	return &expiresAt
+}
+
+// ExtendLifetime adds the extension to the accepted extension of the lifetime, the extension is
+// capped so the lifetime doesn't exceed the maximum lifetime. Lifetime of instances which don't
+// expire cannot be extended.
+func (i *ClusterTemplateInstance) ExtendLifetime(
+	extension time.Duration,
+	deleteAfter *time.Duration,
+	maxLifetime *time.Duration,
+) {
+	lifetime := i.getLifetime(deleteAfter, maxLifetime)
+	if lifetime == nil {
+		return
+	}
+	if i.Status.LifetimeExtension != nil {
+		extension += i.Status.LifetimeExtension.Duration
+	}
+	if maxLifetime != nil && *lifetime+extension > *maxLifetime {
+		extension = *maxLifetime - *lifetime
+	}
+	i.Status.LifetimeExtension = &metav1.Duration{Duration: extension}
+}
+
+// Returns the lifetime without extension capped by the maximum lifetime, nil if the instance does
+// not expire
+func (i *ClusterTemplateInstance) getLifetime(deleteAfter *time.Duration, maxLifetime *time.Duration) *time.Duration {
+	lifetime := deleteAfter
+	if i.Spec.Lifetime != nil {
+		lifetime = &i.Spec.Lifetime.Duration
+	}
+	if lifetime == nil {
+		lifetime = maxLifetime
+	}
+	if lifetime == nil {
+		return nil
+	}
+	if maxLifetime != nil && *lifetime > *maxLifetime {
+		lifetime = maxLifetime
+	}
+	capped := *lifetime
+	return &capped
+}
+
+// GetRequesterGroups returns the groups of the user who requested the instance
+func (i *ClusterTemplateInstance) GetRequesterGroups() []string {
+	groups := i.Annotations[CTIRequesterGroupsAnnotation]
+	if groups == "" {
+		return []string{}
+	}
+	return strings.Split(groups, ",")
+}
+
+// GetClusterProviderConfig returns the generic cluster provider configured by the template snapshot
+// of the instance, nil if the template doesn't configure one
+func (i *ClusterTemplateInstance) GetClusterProviderConfig() (*ClusterProviderConfig, error) {
+	if i.Status.TemplateSnapshot == nil || i.Status.TemplateSnapshot.ClusterProvider == nil {
+		return nil, nil
+	}
+	config := i.Status.TemplateSnapshot.ClusterProvider
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// IsPooled returns true if the instance is kept in a ClusterTemplatePool and was not claimed yet
+func (i *ClusterTemplateInstance) IsPooled() bool {
+	_, ok := i.Labels[CTIPoolLabel]
+	return ok
+}
+
+// NextTransition returns the first power state change of the schedule after the given time
+func (s *PowerSchedule) NextTransition(now time.Time) (*PowerStateTransition, error) {
+	loc, err := time.LoadLocation(s.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone '%s' - %q", s.TimeZone, err)
+	}
+	hibernate, err := cron.ParseStandard(s.Hibernate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hibernate schedule '%s' - %q", s.Hibernate, err)
+	}
+	resume, err := cron.ParseStandard(s.Resume)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resume schedule '%s' - %q", s.Resume, err)
+	}
+
+	nextHibernate := hibernate.Next(now.In(loc))
+	nextResume := resume.Next(now.In(loc))
+	if nextHibernate.IsZero() && nextResume.IsZero() {
+		return nil, nil
+	}
+	if nextResume.IsZero() || (!nextHibernate.IsZero() && nextHibernate.Before(nextResume)) {
+		return &PowerStateTransition{
+			PowerState: HibernatingPowerState,
+			Time:       metav1.NewTime(nextHibernate),
+		}, nil
+	}
+	return &PowerStateTransition{
+		PowerState: RunningPowerState,
+		Time:       metav1.NewTime(nextResume),
+	}, nil
+}
+
+func (i *ClusterTemplateInstance) getSnapshotRevision(appSetName string) string {
+	if i.Status.TemplateSnapshot == nil {
+		return ""
+	}
+	return i.Status.TemplateSnapshot.Revisions[appSetName]
+}
+
 func (i *ClusterTemplateInstance) labelDestionationNamespace(ctx context.Context, appSet *argo.ApplicationSet, k8sClient client.Client, argoCDNamespace string) error {
 	appSetNS := appSet.Spec.Template.Spec.Destination.Namespace
 	if appSetNS == "{{ instance_ns }}" {
@@ -358,8 +588,8 @@ func getDay2Appsets(
 	failOnMissing bool,
 ) ([]*argo.ApplicationSet, error) {
 	appSets := []*argo.ApplicationSet{}
-	appSet := &argo.ApplicationSet{}
 	for _, cs := range clusterSetup {
+		appSet := &argo.ApplicationSet{}
 		if err := k8sClient.Get(
 			ctx,
 			types.NamespacedName{Name: cs, Namespace: argoCDNamespace},
@@ -377,6 +607,8 @@ func getDay2Appsets(
 }
 
 func (i *ClusterTemplateInstance) GetHelmParameters(
+	ctx context.Context,
+	k8sClient client.Client,
 	appset *argo.ApplicationSet,
 	isDay2 bool,
 ) ([]argo.HelmParameter, error) {
@@ -394,17 +626,141 @@ func (i *ClusterTemplateInstance) GetHelmParameters(
 				}
 			}
 			if !found {
+				value, err := i.GetParameterValue(ctx, k8sClient, param)
+				if err != nil {
+					return nil, err
+				}
 				params = append(params, argo.HelmParameter{
 					Name:  param.Name,
-					Value: param.Value,
+					Value: value,
 				})
 			}
 		}
 	}
 
+	// Defaults of the template are used only for the params which are not set by the
+	// ApplicationSet nor by the instance
+	if i.Status.TemplateSnapshot != nil {
+		for _, param := range i.Status.TemplateSnapshot.Parameters {
+			if (!isDay2 && param.ApplicationSet == "") || param.ApplicationSet == appset.Name {
+				if slices.IndexFunc(params, func(p argo.HelmParameter) bool {
+					return p.Name == param.Name
+				}) == -1 {
+					params = append(params, argo.HelmParameter{
+						Name:  param.Name,
+						Value: param.Value,
+					})
+				}
+			}
+		}
+	}
+
+	// Release image of the upgrade takes precedence over any other value of the param
+	if upgrade := i.Status.Upgrade; upgrade != nil && !isDay2 {
+		index := slices.IndexFunc(params, func(p argo.HelmParameter) bool {
+			return p.Name == upgrade.Parameter
+		})
+		if index == -1 {
+			params = append(params, argo.HelmParameter{
+				Name:  upgrade.Parameter,
+				Value: upgrade.ReleaseImage,
+			})
+		} else {
+			// Params of the ApplicationSet are not modified
+			params = slices.Clone(params)
+			params[index].Value = upgrade.ReleaseImage
+		}
+	}
+
 	return params, nil
 }
 
+// UpgradeRequired returns true if the target of spec.upgrade differs from the resolved upgrade
+func (i *ClusterTemplateInstance) UpgradeRequired() bool {
+	target := i.Spec.Upgrade
+	if target == nil {
+		return false
+	}
+	upgrade := i.Status.Upgrade
+	return upgrade == nil ||
+		(target.Version != "" && target.Version != upgrade.Version) ||
+		(target.ReleaseImage != "" && target.ReleaseImage != upgrade.ReleaseImage)
+}
+
+// GetParametersHash returns the hash of the parameters of the spec with the values of their sources
+// resolved, so a change of a referenced secret or config map is detected as well
+func (i *ClusterTemplateInstance) GetParametersHash(
+	ctx context.Context,
+	k8sClient client.Client,
+) (string, error) {
+	params := []Parameter{}
+	for _, param := range i.Spec.Parameters {
+		value, err := i.GetParameterValue(ctx, k8sClient, param)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, Parameter{
+			Name:           param.Name,
+			Value:          value,
+			ApplicationSet: param.ApplicationSet,
+		})
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// GetParameterValue returns the literal value of the parameter or resolves it from the referenced
+// secret or config map in the instance's namespace
+func (i *ClusterTemplateInstance) GetParameterValue(
+	ctx context.Context,
+	k8sClient client.Client,
+	param Parameter,
+) (string, error) {
+	if param.ValueFrom == nil {
+		return param.Value, nil
+	}
+	if ref := param.ValueFrom.SecretKeyRef; ref != nil {
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(
+			ctx,
+			client.ObjectKey{Name: ref.Name, Namespace: i.Namespace},
+			secret,
+		); err != nil {
+			if apierrors.IsNotFound(err) && ref.Optional != nil && *ref.Optional {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to get secret '%s' of parameter '%s' - %q", ref.Name, param.Name, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok && (ref.Optional == nil || !*ref.Optional) {
+			return "", fmt.Errorf("key '%s' not found in secret '%s' of parameter '%s'", ref.Key, ref.Name, param.Name)
+		}
+		return string(value), nil
+	}
+	if ref := param.ValueFrom.ConfigMapKeyRef; ref != nil {
+		configMap := &corev1.ConfigMap{}
+		if err := k8sClient.Get(
+			ctx,
+			client.ObjectKey{Name: ref.Name, Namespace: i.Namespace},
+			configMap,
+		); err != nil {
+			if apierrors.IsNotFound(err) && ref.Optional != nil && *ref.Optional {
+				return "", nil
+			}
+			return "", fmt.Errorf("failed to get config map '%s' of parameter '%s' - %q", ref.Name, param.Name, err)
+		}
+		value, ok := configMap.Data[ref.Key]
+		if !ok && (ref.Optional == nil || !*ref.Optional) {
+			return "", fmt.Errorf("key '%s' not found in config map '%s' of parameter '%s'", ref.Key, ref.Name, param.Name)
+		}
+		return value, nil
+	}
+	return param.Value, nil
+}
+
 func (i *ClusterTemplateInstance) GetSubjectsWithClusterTemplateUserRole(
 	ctx context.Context, k8sClient client.Client) ([]rbacv1.Subject, error) {
 	allRoleBindingsInNamespace := &rbacv1.RoleBindingList{}
@@ -523,3 +879,94 @@ func (i *ClusterTemplateInstance) ContainsSetupSecret(secretName string) bool {
 
 	return false
 }
+
+// RequiresApproval returns true if the template of the instance or a quota of its namespace
+// requires the instances of the template to be approved. Pooled instances are approved once they
+// are claimed.
+func (i *ClusterTemplateInstance) RequiresApproval(
+	ctx context.Context,
+	k8sClient client.Client,
+) (bool, error) {
+	if i.Spec.KubeconfigSecretRef != nil || i.IsPooled() {
+		return false, nil
+	}
+
+	// Missing template is reported once the template snapshot is taken
+	template := &ClusterTemplate{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: i.Spec.ClusterTemplateRef},
+		template,
+	); err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+	if template.GetEffectiveSpec().RequiresApproval {
+		return true, nil
+	}
+
+	quotas := &ClusterTemplateQuotaList{}
+	if err := k8sClient.List(ctx, quotas, client.InNamespace(i.Namespace)); err != nil {
+		return false, err
+	}
+	for _, quota := range quotas.Items {
+		for _, allowedTemplate := range quota.Spec.AllowedTemplates {
+			if allowedTemplate.Name == i.Spec.ClusterTemplateRef && allowedTemplate.RequiresApproval {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// GetApproval returns the approval of the instance, nil if the instance is not approved yet.
+// Approvals of a removed instance of the same name don't apply.
+func (i *ClusterTemplateInstance) GetApproval(
+	ctx context.Context,
+	k8sClient client.Client,
+) (*ClusterTemplateInstanceApproval, error) {
+	approvals := &ClusterTemplateInstanceApprovalList{}
+	if err := k8sClient.List(ctx, approvals, client.InNamespace(i.Namespace)); err != nil {
+		return nil, err
+	}
+	for index := range approvals.Items {
+		approval := &approvals.Items[index]
+		if approval.Spec.ClusterTemplateInstanceRef == i.Name && approval.Spec.ClusterTemplateInstanceUID == i.UID {
+			return approval, nil
+		}
+	}
+	return nil, nil
+}
+
+// RecordPhase appends the current phase to the history if the phase changed. The oldest
+// transitions are removed once there are more than MaxPhaseHistory.
+func (i *ClusterTemplateInstance) RecordPhase(reason string, now metav1.Time) {
+	if i.Status.Phase == "" {
+		return
+	}
+	if len(i.Status.History) > 0 && i.Status.History[len(i.Status.History)-1].Phase == i.Status.Phase {
+		return
+	}
+	i.Status.History = append(i.Status.History, PhaseTransition{
+		Phase:     i.Status.Phase,
+		Reason:    reason,
+		Message:   i.Status.Message,
+		Timestamp: now,
+	})
+	if len(i.Status.History) > MaxPhaseHistory {
+		i.Status.History = i.Status.History[len(i.Status.History)-MaxPhaseHistory:]
+	}
+}
+
+// GetPhaseDurations returns how long the instance stayed in each transition of the history. The
+// last transition lasts until now.
+func (i *ClusterTemplateInstance) GetPhaseDurations(now time.Time) []time.Duration {
+	durations := make([]time.Duration, len(i.Status.History))
+	for index, transition := range i.Status.History {
+		end := now
+		if index < len(i.Status.History)-1 {
+			end = i.Status.History[index+1].Timestamp.Time
+		}
+		durations[index] = end.Sub(transition.Timestamp.Time)
+	}
+	return durations
+}
@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"context"
+	"time"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/kubernetes-client/go-base/config/api"
@@ -73,7 +74,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		}
 		appset := &argo.ApplicationSet{}
 
-		params, err := cti.GetHelmParameters(appset, false)
+		params, err := cti.GetHelmParameters(ctx, nil, appset, false)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{}))
@@ -92,7 +93,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 			},
 		}
 
-		params, err = cti.GetHelmParameters(appset, false)
+		params, err = cti.GetHelmParameters(ctx, nil, appset, false)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{
@@ -134,7 +135,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 			},
 		}
 
-		params, err = cti.GetHelmParameters(appset, false)
+		params, err = cti.GetHelmParameters(ctx, nil, appset, false)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{
@@ -177,7 +178,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 			},
 		}
 
-		params, err = cti.GetHelmParameters(appset, false)
+		params, err = cti.GetHelmParameters(ctx, nil, appset, false)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{
@@ -200,7 +201,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		}
 		appset := &argo.ApplicationSet{}
 
-		params, err := cti.GetHelmParameters(appset, true)
+		params, err := cti.GetHelmParameters(ctx, nil, appset, true)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{}))
@@ -234,7 +235,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 				},
 			},
 		}
-		params, err = cti.GetHelmParameters(appset, true)
+		params, err = cti.GetHelmParameters(ctx, nil, appset, true)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{
@@ -272,7 +273,7 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 				},
 			},
 		}
-		params, err = cti.GetHelmParameters(appset, true)
+		params, err = cti.GetHelmParameters(ctx, nil, appset, true)
 
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(params).Should(Equal([]argo.HelmParameter{
@@ -287,6 +288,219 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		}))
 	})
 
+	It("GetHelmParameters valueFrom", func() {
+		optional := true
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name: "secretParam",
+						ValueFrom: &ParameterValueSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foo-secret",
+								},
+								Key: "foo",
+							},
+						},
+					},
+					{
+						Name: "configMapParam",
+						ValueFrom: &ParameterValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foo-cm",
+								},
+								Key: "foo",
+							},
+						},
+					},
+					{
+						Name: "optionalParam",
+						ValueFrom: &ParameterValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "missing-cm",
+								},
+								Key:      "foo",
+								Optional: &optional,
+							},
+						},
+					},
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-secret",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"foo": []byte("secretValue"),
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-cm",
+				Namespace: "default",
+			},
+			Data: map[string]string{
+				"foo": "configMapValue",
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, secret, configMap)
+
+		params, err := cti.GetHelmParameters(ctx, client, &argo.ApplicationSet{}, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "secretParam",
+				Value: "secretValue",
+			},
+			{
+				Name:  "configMapParam",
+				Value: "configMapValue",
+			},
+			{
+				Name:  "optionalParam",
+				Value: "",
+			},
+		}))
+
+		cti.Spec.Parameters[0].ValueFrom.SecretKeyRef.Name = "missing-secret"
+		_, err = cti.GetHelmParameters(ctx, client, &argo.ApplicationSet{}, false)
+		Expect(err).Should(HaveOccurred())
+	})
+
+	It("GetHelmParameters template defaults", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name:  "size",
+						Value: "large",
+					},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				TemplateSnapshot: &TemplateSnapshot{
+					Parameters: []ClusterTemplateParameter{
+						{
+							Name:  "size",
+							Value: "small",
+						},
+						{
+							Name:  "region",
+							Value: "us-east-1",
+						},
+						{
+							Name:           "channel",
+							Value:          "stable",
+							ApplicationSet: "appset1",
+						},
+					},
+				},
+			},
+		}
+		appset := &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "appset0",
+			},
+		}
+
+		params, err := cti.GetHelmParameters(ctx, nil, appset, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "size",
+				Value: "large",
+			},
+			{
+				Name:  "region",
+				Value: "us-east-1",
+			},
+		}))
+
+		appset = &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "appset1",
+			},
+		}
+		params, err = cti.GetHelmParameters(ctx, nil, appset, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "channel",
+				Value: "stable",
+			},
+		}))
+	})
+	It("GetHelmParameters upgrade", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name:  "releaseImage",
+						Value: "quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+					},
+				},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				Upgrade: &ClusterUpgradeStatus{
+					Version:      "4.12.2",
+					ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+					Parameter:    "releaseImage",
+				},
+			},
+		}
+		appset := &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "appset0",
+			},
+		}
+
+		params, err := cti.GetHelmParameters(ctx, nil, appset, false)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(Equal([]argo.HelmParameter{
+			{
+				Name:  "releaseImage",
+				Value: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+			},
+		}))
+		Expect(cti.Spec.Parameters[0].Value).
+			Should(Equal("quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64"))
+
+		params, err = cti.GetHelmParameters(ctx, nil, appset, true)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(params).Should(BeEmpty())
+	})
+	It("UpgradeRequired", func() {
+		cti := ClusterTemplateInstance{}
+		Expect(cti.UpgradeRequired()).Should(BeFalse())
+
+		cti.Spec.Upgrade = &ClusterUpgradeTarget{Version: "4.12.2"}
+		Expect(cti.UpgradeRequired()).Should(BeTrue())
+
+		cti.Status.Upgrade = &ClusterUpgradeStatus{
+			Version:      "4.12.2",
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+			Parameter:    "releaseImage",
+		}
+		Expect(cti.UpgradeRequired()).Should(BeFalse())
+
+		cti.Spec.Upgrade.ReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.12.3-x86_64"
+		Expect(cti.UpgradeRequired()).Should(BeTrue())
+	})
 	It("GetDay1Application", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: metav1.ObjectMeta{
@@ -381,6 +595,102 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		Expect(string(s)).To(ContainSubstring("{\"instance_ns\":\"default\",\"url\":\"https://kubernetes.default.svc\"}"))
 	})
 
+	It("CreateDay1Application - updates parameters of existing generator", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				Parameters: []Parameter{
+					{
+						Name:  "fooParam",
+						Value: "foo",
+					},
+				},
+			},
+		}
+		appset := &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "cluster-aas-operator",
+			},
+			Spec: argo.ApplicationSetSpec{
+				Template: argo.ApplicationSetTemplate{
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Chart: "foo-chart",
+						},
+					},
+				},
+			},
+		}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, appset)
+		err := cti.CreateDay1Application(ctx, client, "cluster-aas-operator", false, "foo")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		cti.Spec.Parameters[0].Value = "bar"
+		err = cti.CreateDay1Application(ctx, client, "cluster-aas-operator", false, "foo")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		a := argo.ApplicationSetList{}
+		Expect(client.List(ctx, &a)).Should(Succeed())
+
+		Expect(len(a.Items[0].Spec.Generators)).To(Equal(1))
+		Expect(a.Items[0].Spec.Generators[0].List.Template.Spec.Source.Helm.Parameters).To(Equal(
+			[]argo.HelmParameter{
+				{
+					Name:  "fooParam",
+					Value: "bar",
+				},
+			},
+		))
+	})
+
+	It("CreateDay1Application - pins revision of template snapshot", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "default",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				TemplateSnapshot: &TemplateSnapshot{
+					ClusterDefinition: "foo",
+					Revisions: map[string]string{
+						"foo": "0.1.0",
+					},
+				},
+			},
+		}
+		appset := &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo",
+				Namespace: "cluster-aas-operator",
+			},
+			Spec: argo.ApplicationSetSpec{
+				Template: argo.ApplicationSetTemplate{
+					Spec: argo.ApplicationSpec{
+						Source: argo.ApplicationSource{
+							Chart:          "foo-chart",
+							TargetRevision: "0.2.0",
+						},
+					},
+				},
+			},
+		}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, appset)
+		err := cti.CreateDay1Application(ctx, client, "cluster-aas-operator", false, "foo")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		a := argo.ApplicationSetList{}
+		Expect(client.List(ctx, &a)).Should(Succeed())
+		Expect(
+			a.Items[0].Spec.Generators[0].List.Template.Spec.Source.TargetRevision,
+		).To(Equal("0.1.0"))
+	})
+
 	It("CreateDay2Applications", func() {
 		cti := ClusterTemplateInstance{
 			ObjectMeta: metav1.ObjectMeta{
@@ -619,4 +929,224 @@ var _ = Describe("ClusterTemplateInstance utils", func() {
 		Expect(err).ShouldNot(HaveOccurred())
 		Expect(ns.Labels["argocd.argoproj.io/managed-by"]).Should(Equal("argocdns"))
 	})
+
+	It("PowerSchedule NextTransition", func() {
+		schedule := PowerSchedule{
+			Hibernate: "0 19 * * 1-5",
+			Resume:    "0 7 * * 1-5",
+			TimeZone:  "Europe/Prague",
+		}
+		loc, err := time.LoadLocation("Europe/Prague")
+		Expect(err).ShouldNot(HaveOccurred())
+
+		// Monday noon
+		transition, err := schedule.NextTransition(time.Date(2023, 3, 6, 12, 0, 0, 0, loc))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(transition.PowerState).Should(Equal(HibernatingPowerState))
+		Expect(transition.Time.Time.Equal(time.Date(2023, 3, 6, 19, 0, 0, 0, loc))).Should(BeTrue())
+
+		// Friday evening
+		transition, err = schedule.NextTransition(time.Date(2023, 3, 10, 20, 0, 0, 0, loc))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(transition.PowerState).Should(Equal(RunningPowerState))
+		Expect(transition.Time.Time.Equal(time.Date(2023, 3, 13, 7, 0, 0, 0, loc))).Should(BeTrue())
+
+		// UTC is used if time zone is not specified
+		schedule.TimeZone = ""
+		transition, err = schedule.NextTransition(time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(transition.Time.Time.Equal(time.Date(2023, 3, 6, 19, 0, 0, 0, time.UTC))).Should(BeTrue())
+	})
+
+	It("PowerSchedule NextTransition - invalid schedule", func() {
+		schedule := PowerSchedule{
+			Hibernate: "0 19 * *",
+			Resume:    "0 7 * * 1-5",
+		}
+		_, err := schedule.NextTransition(time.Now())
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("invalid hibernate schedule '0 19 * *'"))
+
+		schedule.Hibernate = "0 19 * * 1-5"
+		schedule.TimeZone = "Foo/Bar"
+		_, err = schedule.NextTransition(time.Now())
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("invalid time zone 'Foo/Bar'"))
+	})
+
+	It("GetExpiresAt", func() {
+		created := metav1.NewTime(time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC))
+		instance := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: created,
+			},
+		}
+		deleteAfter := 4 * time.Hour
+		maxLifetime := 8 * time.Hour
+
+		Expect(instance.GetExpiresAt(nil, nil)).Should(BeNil())
+		Expect(instance.GetExpiresAt(&deleteAfter, nil).Time).Should(Equal(created.Add(deleteAfter)))
+		Expect(instance.GetExpiresAt(nil, &maxLifetime).Time).Should(Equal(created.Add(maxLifetime)))
+
+		// Requested lifetime takes precedence and is capped by the maximum lifetime
+		instance.Spec.Lifetime = &metav1.Duration{Duration: 2 * time.Hour}
+		Expect(instance.GetExpiresAt(&deleteAfter, &maxLifetime).Time).Should(Equal(created.Add(2 * time.Hour)))
+		instance.Spec.Lifetime = &metav1.Duration{Duration: 24 * time.Hour}
+		Expect(instance.GetExpiresAt(&deleteAfter, &maxLifetime).Time).Should(Equal(created.Add(maxLifetime)))
+
+		// Recorded expiration is ignored so lowered limits apply to existing instances
+		instance.Spec.Lifetime = nil
+		expiresAt := metav1.NewTime(created.Add(24 * time.Hour))
+		instance.Status.ExpiresAt = &expiresAt
+		lowered := 2 * time.Hour
+		Expect(instance.GetExpiresAt(&deleteAfter, &lowered).Time).Should(Equal(created.Add(lowered)))
+
+		// Accepted extension is added and capped by the maximum lifetime
+		instance.Status.LifetimeExtension = &metav1.Duration{Duration: 2 * time.Hour}
+		Expect(instance.GetExpiresAt(&deleteAfter, &maxLifetime).Time).Should(Equal(created.Add(6 * time.Hour)))
+		Expect(instance.GetExpiresAt(&deleteAfter, &lowered).Time).Should(Equal(created.Add(lowered)))
+	})
+
+	It("ExtendLifetime", func() {
+		created := metav1.NewTime(time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC))
+		instance := ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: created,
+			},
+		}
+		deleteAfter := 4 * time.Hour
+		maxLifetime := 8 * time.Hour
+
+		// Instance without expiration is not extended
+		instance.ExtendLifetime(time.Hour, nil, nil)
+		Expect(instance.Status.LifetimeExtension).Should(BeNil())
+
+		// Extensions are accumulated
+		instance.ExtendLifetime(time.Hour, &deleteAfter, &maxLifetime)
+		instance.ExtendLifetime(time.Hour, &deleteAfter, &maxLifetime)
+		Expect(instance.Status.LifetimeExtension.Duration).Should(Equal(2 * time.Hour))
+		Expect(instance.GetExpiresAt(&deleteAfter, &maxLifetime).Time).Should(Equal(created.Add(6 * time.Hour)))
+
+		// Extension is capped by the maximum lifetime
+		instance.ExtendLifetime(24*time.Hour, &deleteAfter, &maxLifetime)
+		Expect(instance.Status.LifetimeExtension.Duration).Should(Equal(4 * time.Hour))
+		Expect(instance.GetExpiresAt(&deleteAfter, &maxLifetime).Time).Should(Equal(created.Add(maxLifetime)))
+	})
+
+	It("RequiresApproval", func() {
+		testScheme := runtime.NewScheme()
+		err := AddToScheme(testScheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-quota",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name:             "foo-tmp",
+						RequiresApproval: true,
+					},
+				},
+			},
+		}
+		instance := &ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				UID:       "foo-uid",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		k8sClient := fake.NewFakeClientWithScheme(testScheme, ct)
+		requiresApproval, err := instance.RequiresApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(requiresApproval).Should(BeFalse())
+
+		// Required by the quota
+		k8sClient = fake.NewFakeClientWithScheme(testScheme, ct, ctq)
+		requiresApproval, err = instance.RequiresApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(requiresApproval).Should(BeTrue())
+
+		// Required by the template
+		ct.Spec.RequiresApproval = true
+		k8sClient = fake.NewFakeClientWithScheme(testScheme, ct)
+		requiresApproval, err = instance.RequiresApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(requiresApproval).Should(BeTrue())
+
+		approval, err := instance.GetApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(approval).Should(BeNil())
+
+		ctia := &ClusterTemplateInstanceApproval{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-approval",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceApprovalSpec{
+				ClusterTemplateInstanceRef: "foo-instance",
+				ClusterTemplateInstanceUID: "foo-uid",
+				Approver:                   "admin",
+			},
+		}
+		k8sClient = fake.NewFakeClientWithScheme(testScheme, ct, ctia)
+		approval, err = instance.GetApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(approval.Spec.Approver).Should(Equal("admin"))
+
+		// Approval of a removed instance of the same name does not apply
+		instance.UID = "bar-uid"
+		approval, err = instance.GetApproval(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(approval).Should(BeNil())
+	})
+
+	It("RecordPhase", func() {
+		start := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+		instance := &ClusterTemplateInstance{}
+		instance.RecordPhase("", metav1.NewTime(start))
+		Expect(instance.Status.History).Should(BeEmpty())
+
+		instance.Status.Phase = PendingPhase
+		instance.RecordPhase("", metav1.NewTime(start))
+		instance.Status.Phase = ClusterInstallingPhase
+		instance.Status.Message = "Installing"
+		instance.RecordPhase(string(ClusterInstalling), metav1.NewTime(start.Add(time.Minute)))
+		instance.RecordPhase(string(ClusterInstalling), metav1.NewTime(start.Add(2*time.Minute)))
+		Expect(instance.Status.History).Should(Equal([]PhaseTransition{
+			{Phase: PendingPhase, Timestamp: metav1.NewTime(start)},
+			{
+				Phase:     ClusterInstallingPhase,
+				Reason:    string(ClusterInstalling),
+				Message:   "Installing",
+				Timestamp: metav1.NewTime(start.Add(time.Minute)),
+			},
+		}))
+		Expect(instance.GetPhaseDurations(start.Add(time.Hour))).Should(Equal([]time.Duration{
+			time.Minute,
+			59 * time.Minute,
+		}))
+
+		for i := 0; i < MaxPhaseHistory; i++ {
+			if i%2 == 0 {
+				instance.Status.Phase = ClusterSetupDegradedPhase
+			} else {
+				instance.Status.Phase = ClusterSetupRunningPhase
+			}
+			instance.RecordPhase("", metav1.NewTime(start.Add(time.Duration(i+2)*time.Minute)))
+		}
+		Expect(instance.Status.History).Should(HaveLen(MaxPhaseHistory))
+		Expect(instance.Status.History[0].Phase).Should(Equal(ClusterSetupDegradedPhase))
+	})
 })
@@ -18,14 +18,26 @@ package v1alpha1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/kubernetes-client/go-base/config/api"
+	"github.com/xeipuuv/gojsonschema"
+	"golang.org/x/exp/slices"
 	"gopkg.in/yaml.v2"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/strvals"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -36,12 +48,14 @@ import (
 
 var clustertemplateinstancelog = logf.Log.WithName("clustertemplateinstance-resource")
 var instanceControllerClient client.Client
+var unescapedCommaRegex = regexp.MustCompile(`([^\\]),`)
 
 func (r *ClusterTemplateInstance) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	instanceControllerClient = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		WithDefaulter(ctiWebhook).
+		WithValidator(ctiValidator).
 		Complete()
 }
 
@@ -82,34 +96,147 @@ func (r *ClusterTemplateInstance) Default(ctx context.Context, obj runtime.Objec
 		cti.Annotations = map[string]string{}
 	}
 	cti.Annotations[CTIRequesterAnnotation] = req.UserInfo.Username
+	cti.Annotations[CTIRequesterGroupsAnnotation] = strings.Join(req.UserInfo.Groups, ",")
 
 	if val, ok := template.GetAnnotations()[ClusterProviderExperimentalAnnotation]; ok {
 		cti.Annotations[ClusterProviderExperimentalAnnotation] = val
 	}
 
+	// The generic cluster provider reads secrets with the privileges of the operator, so it is
+	// configured only by the template
+	if _, ok := cti.Annotations[ClusterProviderAnnotation]; ok {
+		return fmt.Errorf("annotation '%s' cannot be set", ClusterProviderAnnotation)
+	}
+	if ct, ok := template.(*ClusterTemplate); ok {
+		if config := ct.GetEffectiveSpec().ClusterProvider; config != nil {
+			if err := config.Validate(); err != nil {
+				return fmt.Errorf("cluster template '%v' - %q", cti.Spec.ClusterTemplateRef, err)
+			}
+		}
+	}
+
 	if !controllerutil.ContainsFinalizer(cti, CTIFinalizer) {
 		cti.Finalizers = append(cti.Finalizers, CTIFinalizer)
 	}
 	return nil
 }
 
-//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplateinstance,mutating=false,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=create;update,versions=v1alpha1,name=vclustertemplateinstance.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplateinstance,mutating=false,failurePolicy=fail,sideEffects=NoneOnDryRun,groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=create;update,versions=v1alpha1,name=vclustertemplateinstance.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &ClusterTemplateInstance{}
+var ctiValidator webhook.CustomValidator = &ClusterTemplateInstance{}
 
-func (r *ClusterTemplateInstance) ValidateCreate() error {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateInstance) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*ClusterTemplateInstance)
 	clustertemplateinstancelog.Info("validate create", "name", r.Name)
 
-	if err := r.checkQuota(); err != nil {
+	if err := r.checkInstance(ctx); err != nil {
+		return err
+	}
+	// Quota is reserved only for instances which passed all other checks, dry-run requests are
+	// checked against the quota without reserving it
+	dryRun := false
+	if req, err := admission.RequestFromContext(ctx); err == nil && req.DryRun != nil {
+		dryRun = *req.DryRun
+	}
+	return r.checkQuota(dryRun)
+}
+
+func (r *ClusterTemplateInstance) checkInstance(ctx context.Context) error {
+	if err := r.checkPowerState(); err != nil {
+		return err
+	}
+	if err := r.checkLifetime(); err != nil {
 		return err
 	}
-	if err := r.checkProps(); err != nil {
+	if err := r.checkUpgrade(); err != nil {
 		return err
 	}
+	return r.checkProps(ctx, nil)
+}
+
+// Requested lifetime cannot exceed the maximum lifetime defined by the quotas
+func (r *ClusterTemplateInstance) checkLifetime() error {
+	if r.Spec.Lifetime == nil {
+		return nil
+	}
+	_, maxLifetime, err := r.GetLifetimeLimits(context.TODO(), instanceControllerClient)
+	if err != nil {
+		return fmt.Errorf("could not list cluster template quotas - %q", err)
+	}
+	if maxLifetime != nil && r.Spec.Lifetime.Duration > *maxLifetime {
+		return fmt.Errorf(
+			"lifetime %s exceeds maximum lifetime %s of the quota",
+			r.Spec.Lifetime.Duration,
+			*maxLifetime,
+		)
+	}
+	return nil
+}
+
+// Lifetime extension cannot exceed the maximum lifetime defined by the quotas
+func (r *ClusterTemplateInstance) checkLifetimeExtension(oldCti *ClusterTemplateInstance) error {
+	extension, ok := r.Annotations[CTIExtendAnnotation]
+	if !ok || extension == oldCti.Annotations[CTIExtendAnnotation] {
+		return nil
+	}
+	duration, err := time.ParseDuration(extension)
+	if err != nil || duration <= 0 {
+		return fmt.Errorf("invalid lifetime extension '%s'", extension)
+	}
+	deleteAfter, maxLifetime, err := r.GetLifetimeLimits(context.TODO(), instanceControllerClient)
+	if err != nil {
+		return fmt.Errorf("could not list cluster template quotas - %q", err)
+	}
+	expiresAt := oldCti.GetExpiresAt(deleteAfter, maxLifetime)
+	if expiresAt == nil {
+		return fmt.Errorf("lifetime of instance without expiration cannot be extended")
+	}
+	if maxLifetime != nil && expiresAt.Add(duration).Sub(oldCti.CreationTimestamp.Time) > *maxLifetime {
+		return fmt.Errorf("lifetime extension exceeds maximum lifetime %s of the quota", *maxLifetime)
+	}
+	return nil
+}
 
+// Power state is managed by the cluster provider, clusters defined via kubeconfig secret don't have one
+func (r *ClusterTemplateInstance) checkPowerState() error {
+	if r.Spec.KubeconfigSecretRef != nil && r.Spec.PowerState == HibernatingPowerState {
+		return fmt.Errorf("cluster defined via kubeconfig secret cannot be hibernated")
+	}
+	if r.Spec.PowerSchedule != nil {
+		if r.Spec.KubeconfigSecretRef != nil {
+			return fmt.Errorf("cluster defined via kubeconfig secret cannot have a power schedule")
+		}
+		if _, err := r.Spec.PowerSchedule.NextTransition(time.Now()); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// Upgrade has to be one of the releases allowed by the template
+func (r *ClusterTemplateInstance) checkUpgrade() error {
+	if r.Spec.Upgrade == nil {
+		return nil
+	}
+	if r.Spec.KubeconfigSecretRef != nil {
+		return fmt.Errorf("cluster defined via kubeconfig secret cannot be upgraded")
+	}
+	template := &ClusterTemplate{}
+	if err := instanceControllerClient.Get(
+		context.TODO(),
+		client.ObjectKey{Name: r.Spec.ClusterTemplateRef},
+		template,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("cluster template '%v' not found", r.Spec.ClusterTemplateRef)
+		}
+		return fmt.Errorf("failed to get cluster template - %q", err)
+	}
+	_, err := template.ResolveUpgrade(*r.Spec.Upgrade)
+	return err
+}
+
 func (r *ClusterTemplateInstance) checkSecretIsValid() error {
 	secret := &corev1.Secret{}
 	if err := instanceControllerClient.Get(
@@ -136,7 +263,9 @@ func (r *ClusterTemplateInstance) checkSecretIsValid() error {
 	return nil
 }
 
-func (r *ClusterTemplateInstance) checkProps() error {
+// checkProps validates the parameters against the template. Sources of parameter values which
+// were already set on the old instance are not checked again on update.
+func (r *ClusterTemplateInstance) checkProps(ctx context.Context, oldCti *ClusterTemplateInstance) error {
 	var template client.Object
 	if r.Spec.KubeconfigSecretRef != nil {
 		if err := r.checkSecretIsValid(); err != nil {
@@ -157,11 +286,326 @@ func (r *ClusterTemplateInstance) checkProps() error {
 		return fmt.Errorf("failed to get cluster template - %q", err)
 	}
 
-	// TODO check values
+	values, errs := r.resolveParameterValues(ctx, oldCti)
+	errs = append(errs, r.validateVersion(template)...)
+	if len(errs) == 0 {
+		errs = r.validateParameters(template, values)
+	}
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(
+			GroupVersion.WithKind("ClusterTemplateInstance").GroupKind(),
+			r.Name,
+			errs,
+		)
+	}
 	return nil
 }
 
-func (r *ClusterTemplateInstance) checkQuota() error {
+// resolveParameterValues returns the values of the instance parameters. Values sourced from
+// secrets or config maps are resolved only if the user who sends the request is allowed to read
+// them, unless the source is unchanged since the old instance.
+func (r *ClusterTemplateInstance) resolveParameterValues(
+	ctx context.Context,
+	oldCti *ClusterTemplateInstance,
+) ([]string, field.ErrorList) {
+	errs := field.ErrorList{}
+	values := make([]string, len(r.Spec.Parameters))
+	paramsPath := field.NewPath("spec", "parameters")
+	for index, param := range r.Spec.Parameters {
+		if param.ValueFrom == nil {
+			values[index] = param.Value
+			continue
+		}
+		valueFromPath := paramsPath.Index(index).Child("valueFrom")
+		if param.Value != "" {
+			errs = append(errs, field.Invalid(
+				valueFromPath,
+				"",
+				"may not be specified when `value` is not empty",
+			))
+			continue
+		}
+
+		var refPath *field.Path
+		var resource, name string
+		switch {
+		case param.ValueFrom.SecretKeyRef != nil && param.ValueFrom.ConfigMapKeyRef != nil:
+			errs = append(errs, field.Invalid(
+				valueFromPath,
+				"",
+				"may not have more than one field specified at a time",
+			))
+			continue
+		case param.ValueFrom.SecretKeyRef != nil:
+			refPath = valueFromPath.Child("secretKeyRef")
+			resource = "secrets"
+			name = param.ValueFrom.SecretKeyRef.Name
+		case param.ValueFrom.ConfigMapKeyRef != nil:
+			refPath = valueFromPath.Child("configMapKeyRef")
+			resource = "configmaps"
+			name = param.ValueFrom.ConfigMapKeyRef.Name
+		default:
+			errs = append(errs, field.Required(
+				valueFromPath,
+				"must specify one of: `secretKeyRef` or `configMapKeyRef`",
+			))
+			continue
+		}
+
+		if !oldCti.hasValueFrom(param.ValueFrom) {
+			if err := r.checkReadAccess(ctx, resource, name); err != nil {
+				errs = append(errs, field.Forbidden(refPath, err.Error()))
+				continue
+			}
+		}
+		value, err := r.GetParameterValue(context.TODO(), instanceControllerClient, param)
+		if err != nil {
+			errs = append(errs, field.Invalid(refPath, name, err.Error()))
+			continue
+		}
+		values[index] = value
+	}
+	return values, errs
+}
+
+// hasValueFrom returns true if a parameter of the instance is sourced from the given source
+func (r *ClusterTemplateInstance) hasValueFrom(valueFrom *ParameterValueSource) bool {
+	if r == nil {
+		return false
+	}
+	for _, param := range r.Spec.Parameters {
+		if param.ValueFrom != nil && equality.Semantic.DeepEqual(param.ValueFrom, valueFrom) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReadAccess verifies that the user who sends the request can read the given resource in
+// the instance's namespace, so that users who update the instance cannot expose resources
+// readable only by the requester of the instance
+func (r *ClusterTemplateInstance) checkReadAccess(ctx context.Context, resource string, name string) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	user := req.UserInfo.Username
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user,
+			Groups: req.UserInfo.Groups,
+			UID:    req.UserInfo.UID,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: r.Namespace,
+				Verb:      "get",
+				Resource:  resource,
+				Name:      name,
+			},
+		},
+	}
+	if len(req.UserInfo.Extra) > 0 {
+		sar.Spec.Extra = map[string]authorizationv1.ExtraValue{}
+		for key, value := range req.UserInfo.Extra {
+			sar.Spec.Extra[key] = authorizationv1.ExtraValue(value)
+		}
+	}
+	if err := instanceControllerClient.Create(context.TODO(), sar); err != nil {
+		return fmt.Errorf("failed to check access of '%s' - %q", user, err)
+	}
+	if !sar.Status.Allowed {
+		return fmt.Errorf("user '%s' cannot get %s '%s'", user, resource, name)
+	}
+	return nil
+}
+
+// validateVersion checks that the requested version is defined by the template
+func (r *ClusterTemplateInstance) validateVersion(template client.Object) field.ErrorList {
+	if r.Spec.ClusterTemplateVersion == "" {
+		return nil
+	}
+	versionPath := field.NewPath("spec", "clusterTemplateVersion")
+	switch t := template.(type) {
+	case *ClusterTemplate:
+		if _, err := t.GetVersion(r.Spec.ClusterTemplateVersion); err != nil {
+			return field.ErrorList{field.NotFound(versionPath, r.Spec.ClusterTemplateVersion)}
+		}
+	case *ClusterTemplateSetup:
+		return field.ErrorList{field.Forbidden(versionPath, "versions are not supported by ClusterTemplateSetup")}
+	}
+	return nil
+}
+
+// validateParameters checks the instance parameters against the helm chart schemas
+// which the ClusterTemplate controller stored in the template status.
+func (r *ClusterTemplateInstance) validateParameters(
+	template client.Object,
+	values []string,
+) field.ErrorList {
+	var clusterDefinition *ClusterDefinitionSchema
+	var clusterSetupSchemas []ClusterSetupSchema
+	var clusterSetup []string
+	var defaults []ClusterTemplateParameter
+	switch t := template.(type) {
+	case *ClusterTemplate:
+		defaults = t.GetEffectiveSpec().Parameters
+		if version, err := t.GetVersion(r.Spec.ClusterTemplateVersion); err == nil {
+			clusterSetup = version.ClusterSetup
+		}
+		// Schemas of a new version may not be computed yet
+		if versionStatus := t.GetVersionStatus(r.Spec.ClusterTemplateVersion); versionStatus != nil {
+			clusterDefinition = &versionStatus.ClusterDefinition
+			clusterSetupSchemas = versionStatus.ClusterSetup
+		}
+	case *ClusterTemplateSetup:
+		clusterSetupSchemas = t.Status.ClusterSetup
+		clusterSetup = t.Spec.ClusterSetup
+	}
+
+	errs := field.ErrorList{}
+	paramsPath := field.NewPath("spec", "parameters")
+	for index, param := range r.Spec.Parameters {
+		if param.ApplicationSet != "" && !slices.Contains(clusterSetup, param.ApplicationSet) {
+			errs = append(errs, field.NotSupported(
+				paramsPath.Index(index).Child("clusterSetup"),
+				param.ApplicationSet,
+				clusterSetup,
+			))
+		}
+	}
+
+	if clusterDefinition != nil {
+		errs = append(errs, r.validateValues(
+			"",
+			clusterDefinition.Values,
+			clusterDefinition.Schema,
+			clusterDefinition.Params,
+			defaults,
+			values,
+		)...)
+	}
+	for _, setup := range clusterSetupSchemas {
+		errs = append(errs, r.validateValues(
+			setup.Name,
+			setup.Values,
+			setup.Schema,
+			setup.Params,
+			defaults,
+			values,
+		)...)
+	}
+	return errs
+}
+
+// validateValues merges the instance parameters of the given application set into the chart
+// values the same way ArgoCD does and validates the result against the chart schema.
+func (r *ClusterTemplateInstance) validateValues(
+	appSet string,
+	values string,
+	schema string,
+	overrides []ClusterTemplateParams,
+	defaults []ClusterTemplateParameter,
+	paramValues []string,
+) field.ErrorList {
+	if schema == "" {
+		return nil
+	}
+
+	errs := field.ErrorList{}
+	paramsPath := field.NewPath("spec", "parameters")
+	chartValues, err := chartutil.ReadValues([]byte(values))
+	if err != nil {
+		return append(errs, field.InternalError(
+			paramsPath,
+			fmt.Errorf("failed to read chart values - %q", err),
+		))
+	}
+
+	// Defaults of the template are overridden by the instance parameters
+	for _, param := range defaults {
+		if param.ApplicationSet != appSet {
+			continue
+		}
+		if err := setHelmValue(chartValues, param.Name, param.Value); err != nil {
+			return append(errs, field.InternalError(
+				paramsPath,
+				fmt.Errorf("failed to apply parameter '%s' of the cluster template - %q", param.Name, err),
+			))
+		}
+	}
+
+	paramIndexes := map[string]int{}
+	for index, param := range r.Spec.Parameters {
+		if param.ApplicationSet != appSet {
+			continue
+		}
+		// Parameters defined by the ApplicationSet take precedence, see GetHelmParameters
+		if slices.IndexFunc(overrides, func(o ClusterTemplateParams) bool {
+			return o.Name == param.Name
+		}) != -1 {
+			continue
+		}
+		if err := setHelmValue(chartValues, param.Name, paramValues[index]); err != nil {
+			errs = append(errs, field.Invalid(paramsPath.Index(index).Child("name"), param.Name, err.Error()))
+			continue
+		}
+		paramIndexes[param.Name] = index
+	}
+	for _, override := range overrides {
+		if err := setHelmValue(chartValues, override.Name, override.Value); err != nil {
+			return append(errs, field.InternalError(
+				paramsPath,
+				fmt.Errorf("failed to apply parameter '%s' of the application set - %q", override.Name, err),
+			))
+		}
+	}
+
+	valuesJSON, err := json.Marshal(chartValues)
+	if err != nil {
+		return append(errs, field.InternalError(paramsPath, err))
+	}
+	result, err := gojsonschema.Validate(
+		gojsonschema.NewStringLoader(schema),
+		gojsonschema.NewBytesLoader(valuesJSON),
+	)
+	if err != nil {
+		return append(errs, field.InternalError(
+			paramsPath,
+			fmt.Errorf("failed to validate values against schema - %q", err),
+		))
+	}
+
+	for _, desc := range result.Errors() {
+		detail := desc.String()
+		if appSet != "" {
+			detail = fmt.Sprintf("cluster setup '%s': %s", appSet, detail)
+		}
+		if index, ok := paramIndexes[desc.Field()]; ok {
+			errs = append(errs, field.Invalid(
+				paramsPath.Index(index).Child("value"),
+				r.Spec.Parameters[index].Value,
+				detail,
+			))
+		} else {
+			errs = append(errs, field.Invalid(paramsPath, desc.Field(), detail))
+		}
+	}
+	return errs
+}
+
+// setHelmValue sets the value the same way helm does for --set, including the escaping of
+// commas ArgoCD applies to non-list parameters
+func setHelmValue(values map[string]interface{}, name string, value string) error {
+	if !(strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")) {
+		value = unescapedCommaRegex.ReplaceAllString(value, `$1\,`)
+	}
+	return strvals.ParseInto(name+"="+value, values)
+}
+
+// Checks the quotas of the instance and reserves the cost of the instance in them. The reservation
+// is an optimistic update of the quota status, so instances admitted at the same time cannot
+// overrun the quota. Quotas which queue instances admit the instance to their queue instead of
+// rejecting it.
+func (r *ClusterTemplateInstance) checkQuota(dryRun bool) error {
 	// Do not check quota for the cluster template setup only:
 	if r.Spec.KubeconfigSecretRef != nil {
 		return nil
@@ -184,6 +628,11 @@ func (r *ClusterTemplateInstance) checkQuota() error {
 		return fmt.Errorf("cluster template does not exist")
 	}
 
+	cost := 0
+	if templateCost := ct.GetEffectiveSpec().Cost; templateCost != nil {
+		cost = *templateCost
+	}
+
 	quotas := ClusterTemplateQuotaList{}
 	opts := []client.ListOption{
 		client.InNamespace(r.Namespace),
@@ -192,65 +641,368 @@ func (r *ClusterTemplateInstance) checkQuota() error {
 		return fmt.Errorf("could not list cluster template quotas - %q", err)
 	}
 
-	if len(quotas.Items) == 0 {
-		return nil
+	reservation := r.newQuotaReservation(cost)
+	queued := false
+	for index := range quotas.Items {
+		quota := &quotas.Items[index]
+		if err := reserveQuota(context.TODO(), instanceControllerClient, quota, dryRun, func() error {
+			err := r.CheckNamespaceQuota(quota, cost)
+			// New instances wait behind the queued ones, unless the quota does not allow the template
+			if quota.Spec.QueueInstances && quota.AllowsTemplate(r.Spec.ClusterTemplateRef) &&
+				(err != nil || len(quota.Status.Queue) > 0) {
+				quota.Status.Queue = enqueueInstance(quota.Status.Queue, QueuedInstance{
+					Namespace:       r.Namespace,
+					Name:            r.Name,
+					ClusterTemplate: r.Spec.ClusterTemplateRef,
+					Priority:        r.Spec.Priority,
+					QueuedAt:        reservation.ReservedAt,
+				})
+				queued = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			quota.Status.Reservations = append(quota.Status.Reservations, reservation)
+			return nil
+		}); err != nil {
+			if !dryRun {
+				r.releaseReservations()
+			}
+			return err
+		}
 	}
 
-	templateAllowed := false
-	for _, quota := range quotas.Items {
-		if quota.Spec.Budget > 0 &&
-			quota.Spec.Budget < quota.Status.BudgetSpent+*ct.Spec.Cost {
+	// Queued instances reserve the cluster quotas once the quota controller starts them
+	var err error
+	if queued {
+		err = r.checkClusterQuotasAllowTemplate(context.TODO(), instanceControllerClient)
+	} else {
+		err = r.ReserveClusterQuotas(context.TODO(), instanceControllerClient, cost, dryRun)
+	}
+	if err != nil && !dryRun {
+		r.releaseReservations()
+	}
+	return err
+}
+
+// ReserveClusterQuotas checks the cluster quotas which select the namespace of the instance and
+// reserves the cost of the instance in them. Nothing is reserved if any of the cluster quotas does
+// not allow the instance, the reservations are only checked for dry-run requests.
+func (r *ClusterTemplateInstance) ReserveClusterQuotas(
+	ctx context.Context,
+	k8sClient client.Client,
+	cost int,
+	dryRun bool,
+) error {
+	clusterQuotas, err := r.getClusterQuotas(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	reservation := r.newQuotaReservation(cost)
+	for index := range clusterQuotas {
+		quota := &clusterQuotas[index]
+		if err := reserveQuota(ctx, k8sClient, quota, dryRun, func() error {
+			// The instance may have been reserved by a previous attempt to start it
+			for _, reserved := range quota.Status.Reservations {
+				if reserved.Namespace == r.Namespace && reserved.Name == r.Name {
+					return nil
+				}
+			}
+			if err := r.checkClusterQuota(quota, cost); err != nil {
+				return err
+			}
+			quota.Status.Reservations = append(quota.Status.Reservations, reservation)
+			return nil
+		}); err != nil {
+			if !dryRun {
+				if err := r.releaseClusterQuotaReservations(ctx, k8sClient); err != nil {
+					clustertemplateinstancelog.Error(err, "failed to release cluster quota reservations", "name", r.Name)
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Checks that the cluster quotas which select the namespace of the instance allow its template
+func (r *ClusterTemplateInstance) checkClusterQuotasAllowTemplate(ctx context.Context, k8sClient client.Client) error {
+	clusterQuotas, err := r.getClusterQuotas(ctx, k8sClient)
+	if err != nil {
+		return err
+	}
+	for _, quota := range clusterQuotas {
+		if !quota.AllowsTemplate(r.Spec.ClusterTemplateRef) {
 			return fmt.Errorf(
-				"failed quota: cluster instance not allowed - cluster cost would exceed budget",
+				"failed quota: cluster quota '%s' does not allow '%s' cluster template",
+				quota.Name,
+				r.Spec.ClusterTemplateRef,
 			)
 		}
+	}
+	return nil
+}
+
+func (r *ClusterTemplateInstance) newQuotaReservation(cost int) QuotaReservation {
+	return QuotaReservation{
+		Namespace:       r.Namespace,
+		Name:            r.Name,
+		ClusterTemplate: r.Spec.ClusterTemplateRef,
+		Cost:            cost,
+		Requester:       r.Annotations[CTIRequesterAnnotation],
+		ReservedAt:      metav1.Now(),
+	}
+}
+
+// Reserves the quota with a fresh copy of its status. Conflicting reservations are retried. The
+// reservation is only checked, not stored, for dry-run requests.
+func reserveQuota(
+	ctx context.Context,
+	k8sClient client.Client,
+	quota client.Object,
+	dryRun bool,
+	reserve func() error,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(quota), quota); err != nil {
+			return fmt.Errorf("could not get cluster template quota - %q", err)
+		}
+		if err := reserve(); err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+		return k8sClient.Status().Update(ctx, quota)
+	})
+}
+
+// Releases the reservations of the instance which was not admitted
+func (r *ClusterTemplateInstance) releaseReservations() {
+	if err := r.ReleaseQuotaReservations(context.TODO(), instanceControllerClient); err != nil {
+		clustertemplateinstancelog.Error(err, "failed to release quota reservations", "name", r.Name)
+	}
+}
+
+// CheckNamespaceQuota checks if an instance of the given cost fits in the quota, including the
+// reserved usage
+func (r *ClusterTemplateInstance) CheckNamespaceQuota(quota *ClusterTemplateQuota, cost int) error {
+	reservedBudget, reservedCount := getReservedUsage(quota.Status.Reservations, r.Spec.ClusterTemplateRef)
+	if quota.Spec.Budget > 0 &&
+		quota.Spec.Budget < quota.Status.BudgetSpent+reservedBudget+cost {
+		return fmt.Errorf(
+			"failed quota: cluster instance not allowed - cluster cost would exceed budget",
+		)
+	}
+
+	if billing := quota.Spec.Billing; billing != nil && billing.Budget > 0 &&
+		quota.GetBillingSpent(time.Now()) >= billing.Budget {
+		return fmt.Errorf(
+			"failed quota: cluster instance not allowed - budget of the billing period is exhausted",
+		)
+	}
 
-		maxAllowed := 0
-		for _, tempInstance := range quota.Spec.AllowedTemplates {
+	templateAllowed := false
+	maxAllowed := 0
+	for _, tempInstance := range quota.Spec.AllowedTemplates {
+		if tempInstance.Name == r.Spec.ClusterTemplateRef {
+			templateAllowed = true
+			maxAllowed = tempInstance.Count
+		}
+	}
+
+	if !templateAllowed {
+		return fmt.Errorf(
+			"failed quota: quota does not allow '%s' cluster template",
+			r.Spec.ClusterTemplateRef,
+		)
+	}
+
+	if maxAllowed > 0 {
+		for _, tempInstance := range quota.Status.TemplateInstances {
 			if tempInstance.Name == r.Spec.ClusterTemplateRef {
-				templateAllowed = true
-				maxAllowed = tempInstance.Count
+				if tempInstance.Count+reservedCount >= maxAllowed {
+					return fmt.Errorf(
+						"failed quota: cluster instance not allowed - maximum cluster instances reached",
+					)
+				}
 			}
 		}
+	}
 
-		if maxAllowed > 0 {
-			for _, tempInstance := range quota.Status.TemplateInstances {
-				if tempInstance.Name == r.Spec.ClusterTemplateRef {
-					if tempInstance.Count >= maxAllowed {
-						return fmt.Errorf(
-							"failed quota: cluster instance not allowed - maximum cluster instances reached",
-						)
-					}
-				}
+	return r.checkRequesterLimits(quota, cost)
+}
+
+// Returns the cluster quotas which select the namespace of the instance
+func (r *ClusterTemplateInstance) getClusterQuotas(
+	ctx context.Context,
+	k8sClient client.Client,
+) ([]ClusterTemplateClusterQuota, error) {
+	clusterQuotas := ClusterTemplateClusterQuotaList{}
+	if err := k8sClient.List(ctx, &clusterQuotas); err != nil {
+		return nil, fmt.Errorf("could not list cluster template cluster quotas - %q", err)
+	}
+	if len(clusterQuotas.Items) == 0 {
+		return nil, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: r.Namespace},
+		namespace,
+	); err != nil {
+		return nil, fmt.Errorf("could not get namespace - %q", err)
+	}
+
+	selectedQuotas := []ClusterTemplateClusterQuota{}
+	for _, quota := range clusterQuotas.Items {
+		selected, err := quota.SelectsNamespace(namespace)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace selector of cluster quota '%s' - %q", quota.Name, err)
+		}
+		if selected {
+			selectedQuotas = append(selectedQuotas, quota)
+		}
+	}
+	return selectedQuotas, nil
+}
+
+// Checks the cluster quota which selects the namespace of the instance
+func (r *ClusterTemplateInstance) checkClusterQuota(quota *ClusterTemplateClusterQuota, cost int) error {
+	reservedBudget, reservedCount := getReservedUsage(quota.Status.Reservations, r.Spec.ClusterTemplateRef)
+	if quota.Spec.Budget > 0 &&
+		quota.Spec.Budget < quota.Status.BudgetSpent+reservedBudget+cost {
+		return fmt.Errorf(
+			"failed quota: cluster instance not allowed - cluster cost would exceed budget of cluster quota '%s'",
+			quota.Name,
+		)
+	}
+
+	templateAllowed := false
+	for _, allowedTemplate := range quota.Spec.AllowedTemplates {
+		if allowedTemplate.Name != r.Spec.ClusterTemplateRef {
+			continue
+		}
+		templateAllowed = true
+		if allowedTemplate.Count == 0 {
+			continue
+		}
+		for _, tempInstance := range quota.Status.TemplateInstances {
+			if tempInstance.Name == r.Spec.ClusterTemplateRef &&
+				tempInstance.Count+reservedCount >= allowedTemplate.Count {
+				return fmt.Errorf(
+					"failed quota: cluster instance not allowed - maximum cluster instances of cluster quota '%s' reached",
+					quota.Name,
+				)
 			}
 		}
 	}
 
 	if !templateAllowed {
 		return fmt.Errorf(
-			"failed quota: quota does not allow '%s' cluster template",
+			"failed quota: cluster quota '%s' does not allow '%s' cluster template",
+			quota.Name,
 			r.Spec.ClusterTemplateRef,
 		)
 	}
 	return nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *ClusterTemplateInstance) ValidateUpdate(old runtime.Object) error {
+// Checks the limits of the quota which apply to the requester of the instance
+func (r *ClusterTemplateInstance) checkRequesterLimits(quota *ClusterTemplateQuota, cost int) error {
+	requester := r.Annotations[CTIRequesterAnnotation]
+	spent := RequesterStatus{}
+	for _, requesterStatus := range quota.Status.Requesters {
+		if requesterStatus.Name == requester {
+			spent = requesterStatus
+		}
+	}
+	requesterReservations := []QuotaReservation{}
+	for _, reservation := range quota.Status.Reservations {
+		if reservation.Requester == requester {
+			requesterReservations = append(requesterReservations, reservation)
+		}
+	}
+	reservedBudget, reservedCount := getReservedUsage(requesterReservations, r.Spec.ClusterTemplateRef)
+
+	for _, limit := range quota.Spec.RequesterLimits {
+		if !limit.AppliesTo(requester, r.GetRequesterGroups()) {
+			continue
+		}
+		if limit.Budget > 0 && limit.Budget < spent.BudgetSpent+reservedBudget+cost {
+			return fmt.Errorf(
+				"failed quota: cluster instance not allowed - cluster cost would exceed budget of requester '%s'",
+				requester,
+			)
+		}
+		for _, templateLimit := range limit.AllowedTemplates {
+			if templateLimit.Name != r.Spec.ClusterTemplateRef {
+				continue
+			}
+			count := reservedCount
+			for _, tempInstance := range spent.TemplateInstances {
+				if tempInstance.Name == r.Spec.ClusterTemplateRef {
+					count += tempInstance.Count
+				}
+			}
+			if count >= templateLimit.Count {
+				return fmt.Errorf(
+					"failed quota: cluster instance not allowed - maximum cluster instances of requester '%s' reached",
+					requester,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateInstance) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	r := newObj.(*ClusterTemplateInstance)
 	clustertemplateinstancelog.Info("validate update", "name", r.Name)
-	oldCti := old.(*ClusterTemplateInstance)
+	oldCti := oldObj.(*ClusterTemplateInstance)
 
-	if oldCti.Annotations[CTIRequesterAnnotation] != r.Annotations[CTIRequesterAnnotation] {
+	if oldCti.Annotations[CTIRequesterAnnotation] != r.Annotations[CTIRequesterAnnotation] ||
+		oldCti.Annotations[CTIRequesterGroupsAnnotation] != r.Annotations[CTIRequesterGroupsAnnotation] {
 		return fmt.Errorf("cluster requester cannot be changed")
 	}
-	if !equality.Semantic.DeepEqual(r.Spec, oldCti.Spec) {
+	if oldCti.Annotations[ClusterProviderAnnotation] != r.Annotations[ClusterProviderAnnotation] {
+		return fmt.Errorf("annotation '%s' cannot be changed", ClusterProviderAnnotation)
+	}
+	oldSpec := oldCti.Spec.DeepCopy()
+	oldSpec.Parameters = r.Spec.Parameters
+	oldSpec.PowerState = r.Spec.PowerState
+	oldSpec.PowerSchedule = r.Spec.PowerSchedule
+	oldSpec.Upgrade = r.Spec.Upgrade
+	if !equality.Semantic.DeepEqual(r.Spec, *oldSpec) {
 		return fmt.Errorf("spec is immutable")
 	}
+	// The cluster would be downgraded to the release of the template
+	if oldCti.Spec.Upgrade != nil && r.Spec.Upgrade == nil {
+		return fmt.Errorf("upgrade cannot be removed")
+	}
+	if !equality.Semantic.DeepEqual(r.Spec.Upgrade, oldCti.Spec.Upgrade) {
+		if err := r.checkUpgrade(); err != nil {
+			return err
+		}
+	}
+	if err := r.checkPowerState(); err != nil {
+		return err
+	}
+	if err := r.checkLifetimeExtension(oldCti); err != nil {
+		return err
+	}
+	if !equality.Semantic.DeepEqual(r.Spec.Parameters, oldCti.Spec.Parameters) {
+		return r.checkProps(ctx, oldCti)
+	}
 	return nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *ClusterTemplateInstance) ValidateDelete() error {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateInstance) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	r := obj.(*ClusterTemplateInstance)
 	clustertemplateinstancelog.Info("validate delete", "name", r.Name)
 
 	// TODO(user): fill in your validation logic upon object deletion.
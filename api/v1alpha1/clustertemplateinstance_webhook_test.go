@@ -3,19 +3,71 @@ package v1alpha1
 import (
 	"context"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"golang.org/x/exp/slices"
 	admissionv1 "k8s.io/api/admission/v1"
 	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+const testSchema = `{
+  "$schema": "http://json-schema.org/schema#",
+  "type": "object",
+  "required": ["nodePool"],
+  "properties": {
+    "nodePool": {
+      "type": "object",
+      "properties": {
+        "replicas": {
+          "type": "integer"
+        }
+      }
+    }
+  }
+}`
+
+// validationCtx carries the admission request of the validating webhook
+var validationCtx = admission.NewContextWithRequest(context.TODO(), admission.Request{
+	AdmissionRequest: admissionv1.AdmissionRequest{
+		UserInfo: authenticationv1.UserInfo{
+			Username: "test-user",
+		},
+	},
+})
+
+// accessReviewClient answers SubjectAccessReviews with the configured result, reviews of members
+// of the allowed group are allowed as well
+type accessReviewClient struct {
+	client.Client
+	allowed      bool
+	allowedGroup string
+	lastReview   *authorizationv1.SubjectAccessReview
+}
+
+func (c *accessReviewClient) Create(
+	ctx context.Context,
+	obj client.Object,
+	opts ...client.CreateOption,
+) error {
+	if sar, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+		sar.Status.Allowed = c.allowed || slices.Contains(sar.Spec.Groups, c.allowedGroup)
+		c.lastReview = sar
+		return nil
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
 var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 	It("Fails when template does not exists", func() {
 		scheme := runtime.NewScheme()
@@ -44,9 +96,9 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 				ClusterTemplateRef: "foo-tmp",
 			},
 		}
-		err = cti.ValidateCreate()
+		err = cti.ValidateCreate(validationCtx, &cti)
 		Expect(err).Should(HaveOccurred())
-		Expect(err.Error()).Should(Equal("cluster template does not exist"))
+		Expect(err.Error()).Should(Equal("cluster template 'foo-tmp' not found"))
 	})
 	It("Fails when quota does not allow template", func() {
 		scheme := runtime.NewScheme()
@@ -84,7 +136,7 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 				ClusterTemplateRef: "foo-tmp",
 			},
 		}
-		err = cti.ValidateCreate()
+		err = cti.ValidateCreate(validationCtx, &cti)
 		Expect(err).Should(HaveOccurred())
 		Expect(
 			err.Error(),
@@ -123,46 +175,1394 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 				ClusterTemplateRef: "foo-tmp",
 			},
 		}
-		err = cti.ValidateCreate()
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Fails when max insances reached", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name:  "foo-tmp",
+						Count: 1,
+					},
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				TemplateInstances: []AllowedTemplateStatus{
+					{
+						Name:  "foo-tmp",
+						Count: 1,
+					},
+				},
+			},
+		}
+		cost := 0
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - maximum cluster instances reached"))
+	})
+	It("Passes when ctq allows template", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name:  "foo-tmp",
+						Count: 1,
+					},
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				TemplateInstances: []AllowedTemplateStatus{
+					{
+						Name:  "foo-tmp",
+						Count: 0,
+					},
+				},
+			},
+		}
+		cost := 1
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Fails when parameters do not match cluster definition schema", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterSetup: []string{"setup"},
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Values: "nodePool:\n  replicas: 2\n",
+					Schema: testSchema,
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:  "nodePool.replicas",
+						Value: "abc",
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(apierrors.IsInvalid(err)).Should(BeTrue())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].value"))
+		Expect(err.Error()).Should(ContainSubstring("nodePool.replicas"))
+
+		cti.Spec.Parameters[0].Value = "3"
+		Expect(cti.ValidateCreate(validationCtx, &cti)).ShouldNot(HaveOccurred())
+	})
+	It("Fails when parameters do not match cluster setup schema", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterSetup: []string{"setup"},
+			},
+			Status: ClusterTemplateStatus{
+				ClusterSetup: []ClusterSetupSchema{
+					{
+						Name:   "setup",
+						Schema: testSchema,
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:  "nodePool.replicas",
+						Value: "3",
+					},
+					{
+						Name:           "nodePool.replicas",
+						Value:          "abc",
+						ApplicationSet: "setup",
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[1].value"))
+		Expect(err.Error()).Should(ContainSubstring("cluster setup 'setup'"))
+		Expect(err.Error()).ShouldNot(ContainSubstring("spec.parameters[0]"))
+	})
+	It("Fails when parameter references unknown cluster setup", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterSetup: []string{"setup"},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:           "foo",
+						Value:          "bar",
+						ApplicationSet: "unknown",
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].clusterSetup"))
+	})
+	It("Fails when required value is missing", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Schema: testSchema,
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("nodePool is required"))
+	})
+
+	It("Passes when required value is set by template default", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				BaseTemplate: "base-tmp",
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Schema: testSchema,
+				},
+				EffectiveSpec: &ClusterTemplateSpec{
+					ClusterDefinition: "foo-appset",
+					Parameters: []ClusterTemplateParameter{
+						{
+							Name:  "nodePool.replicas",
+							Value: "3",
+						},
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Fails when requester limits are reached", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				Budget: 10,
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				RequesterLimits: []RequesterLimit{
+					{
+						Groups: []string{"developers"},
+						Budget: 3,
+					},
+					{
+						Users: []string{"bob"},
+						AllowedTemplates: []RequesterTemplateLimit{
+							{
+								Name:  "foo-tmp",
+								Count: 1,
+							},
+						},
+					},
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				BudgetSpent: 4,
+				TemplateInstances: []AllowedTemplateStatus{
+					{
+						Name:  "foo-tmp",
+						Count: 2,
+					},
+				},
+				Requesters: []RequesterStatus{
+					{
+						Name:        "alice",
+						BudgetSpent: 2,
+						TemplateInstances: []AllowedTemplateStatus{
+							{
+								Name:  "foo-tmp",
+								Count: 1,
+							},
+						},
+					},
+					{
+						Name:        "bob",
+						BudgetSpent: 2,
+						TemplateInstances: []AllowedTemplateStatus{
+							{
+								Name:  "foo-tmp",
+								Count: 1,
+							},
+						},
+					},
+				},
+			},
+		}
+		cost := 2
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation:       "alice",
+					CTIRequesterGroupsAnnotation: "developers,system:authenticated",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - cluster cost would exceed budget of requester 'alice'"))
+
+		cti.Annotations = map[string]string{
+			CTIRequesterAnnotation: "bob",
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - maximum cluster instances of requester 'bob' reached"))
+
+		cti.Annotations = map[string]string{
+			CTIRequesterAnnotation: "carol",
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Fails when cluster quota of the namespace is exceeded", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ns := &corev1.Namespace{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo",
+				Labels: map[string]string{
+					"team": "a",
+				},
+			},
+		}
+		ctcq := &ClusterTemplateClusterQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "team-a",
+			},
+			Spec: ClusterTemplateClusterQuotaSpec{
+				NamespaceSelector: v1.LabelSelector{
+					MatchLabels: map[string]string{
+						"team": "a",
+					},
+				},
+				Budget: 5,
+				AllowedTemplates: []ClusterQuotaAllowedTemplate{
+					{
+						Name:  "foo-tmp",
+						Count: 2,
+					},
+				},
+			},
+			Status: ClusterTemplateClusterQuotaStatus{
+				BudgetSpent: 4,
+				TemplateInstances: []AllowedTemplateStatus{
+					{
+						Name:  "foo-tmp",
+						Count: 1,
+					},
+				},
+			},
+		}
+		otherCtcq := &ClusterTemplateClusterQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "team-b",
+			},
+			Spec: ClusterTemplateClusterQuotaSpec{
+				NamespaceSelector: v1.LabelSelector{
+					MatchLabels: map[string]string{
+						"team": "b",
+					},
+				},
+				AllowedTemplates: []ClusterQuotaAllowedTemplate{},
+			},
+		}
+		cost := 2
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ns, ctcq, otherCtcq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - cluster cost would exceed budget of cluster quota 'team-a'"))
+
+		cost = 1
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ns, ctcq, otherCtcq, ct)
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Reserves quota for admitted instances", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				Budget: 5,
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name:        "foo-tmp",
+						MaxLifetime: &v1.Duration{Duration: time.Hour},
+					},
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				BudgetSpent: 2,
+			},
+		}
+		cost := 2
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Reservations)).Should(Equal(1))
+		Expect(ctq.Status.Reservations[0].Name).Should(Equal("foo-instance"))
+		Expect(ctq.Status.Reservations[0].Cost).Should(Equal(2))
+
+		// Status of the quota was not updated by the controller yet
+		cti.Name = "bar-instance"
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - cluster cost would exceed budget"))
+
+		// Quota is not reserved for instances which fail other checks
+		cti.Name = "baz-instance"
+		cti.Spec.Lifetime = &v1.Duration{Duration: 2 * time.Hour}
+		cost = 1
+		err = instanceControllerClient.Update(ctx, ct)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Reservations)).Should(Equal(1))
+		Expect(ctq.Status.Reservations[0].Name).Should(Equal("foo-instance"))
+
+		// Dry-run requests are checked against the quota without reserving it
+		dryRun := true
+		dryRunCtx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "test-user",
+				},
+				DryRun: &dryRun,
+			},
+		})
+		cti.Spec.Lifetime = nil
+		err = cti.ValidateCreate(dryRunCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Reservations)).Should(Equal(1))
+	})
+
+	It("Queues instances which exceed the quota", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				Budget: 2,
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				QueueInstances: true,
+			},
+			Status: ClusterTemplateQuotaStatus{
+				BudgetSpent: 2,
+			},
+		}
+		cost := 2
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Cost: &cost,
+			},
+		}
+		// Queued instances don't reserve the cluster quota until they are started
+		ctcq := &ClusterTemplateClusterQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "team-a",
+			},
+			Spec: ClusterTemplateClusterQuotaSpec{
+				Budget: 2,
+				AllowedTemplates: []ClusterQuotaAllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+			},
+			Status: ClusterTemplateClusterQuotaStatus{
+				BudgetSpent: 2,
+			},
+		}
+		ns := &corev1.Namespace{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo",
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct, ctcq, ns)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctcq), ctcq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctcq.Status.Reservations)).Should(Equal(0))
+
+		// Instances with higher priority are started first
+		cti.Name = "bar-instance"
+		cti.Spec.Priority = 1
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Reservations)).Should(Equal(0))
+		Expect(len(ctq.Status.Queue)).Should(Equal(2))
+		Expect(ctq.Status.Queue[0].Name).Should(Equal("bar-instance"))
+		Expect(ctq.Status.Queue[1].Name).Should(Equal("foo-instance"))
+
+		// Instances which are not admitted are not queued
+		cti.Name = "baz-instance"
+		cti.Spec.PowerSchedule = &PowerSchedule{Hibernate: "invalid", Resume: "invalid"}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Queue)).Should(Equal(2))
+
+		// Templates which are not allowed are not queued
+		err = instanceControllerClient.Create(ctx, &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "bar-tmp",
+			},
+		})
+		Expect(err).ShouldNot(HaveOccurred())
+		cti.Spec.PowerSchedule = nil
+		cti.Spec.ClusterTemplateRef = "bar-tmp"
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: quota does not allow 'bar-tmp' cluster template"))
+
+		// Templates which are not allowed by the cluster quota are not queued
+		ctq.Spec.AllowedTemplates = append(ctq.Spec.AllowedTemplates, AllowedTemplate{Name: "bar-tmp"})
+		err = instanceControllerClient.Update(ctx, ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster quota 'team-a' does not allow 'bar-tmp' cluster template"))
+		err = instanceControllerClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(len(ctq.Status.Queue)).Should(Equal(2))
+	})
+
+	It("Fails when budget of the billing period is exhausted", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				Billing: &Billing{
+					Period: WeeklyBillingPeriod,
+					Budget: 100,
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				Billing: &BillingStatus{
+					PeriodStart: v1.NewTime(WeeklyBillingPeriod.Start(time.Now())),
+					Spent:       100,
+				},
+			},
+		}
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - budget of the billing period is exhausted"))
+
+		// Spent budget of the previous period is not counted
+		ctq.Status.Billing.PeriodStart = v1.NewTime(ctq.Status.Billing.PeriodStart.AddDate(0, 0, -7))
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Fails when cost of effective spec exceeds budget", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				Budget: 5,
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+			},
+			Status: ClusterTemplateQuotaStatus{
+				BudgetSpent: 2,
+			},
+		}
+		baseCost := 1
+		cost := 4
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				BaseTemplate: "base-tmp",
+				Cost:         &baseCost,
+			},
+			Status: ClusterTemplateStatus{
+				EffectiveSpec: &ClusterTemplateSpec{
+					ClusterDefinition: "foo-appset",
+					Cost:              &cost,
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("failed quota: cluster instance not allowed - cluster cost would exceed budget"))
+	})
+
+	It("Validates parameters against schema of the version", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Spec: ClusterTemplateSpec{
+				Versions: []ClusterTemplateVersion{
+					{
+						Name: "v1",
+					},
+				},
+			},
+			Status: ClusterTemplateStatus{
+				Versions: []ClusterTemplateVersionStatus{
+					{
+						Name: "v1",
+						ClusterDefinition: ClusterDefinitionSchema{
+							Schema: testSchema,
+						},
+					},
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		Expect(cti.ValidateCreate(validationCtx, &cti)).ShouldNot(HaveOccurred())
+
+		cti.Spec.ClusterTemplateVersion = "v1"
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("nodePool is required"))
+
+		cti.Spec.ClusterTemplateVersion = "v2"
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.clusterTemplateVersion"))
+	})
+	It("Resolves parameter value from secret", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Schema: testSchema,
+				},
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-secret",
+				Namespace: "foo",
+			},
+			Data: map[string][]byte{
+				"replicas": []byte("abc"),
+			},
+		}
+		fakeClient := &accessReviewClient{
+			Client:  fake.NewFakeClientWithScheme(scheme, ct, secret),
+			allowed: true,
+		}
+		instanceControllerClient = fakeClient
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name: "nodePool.replicas",
+						ValueFrom: &ParameterValueSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foo-secret",
+								},
+								Key: "replicas",
+							},
+						},
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].value"))
+		Expect(err.Error()).ShouldNot(ContainSubstring("abc"))
+
+		secret.Data["replicas"] = []byte("3")
+		fakeClient.Client = fake.NewFakeClientWithScheme(scheme, ct, secret)
+		Expect(cti.ValidateCreate(validationCtx, &cti)).ShouldNot(HaveOccurred())
+
+		cti.Spec.Parameters[0].ValueFrom.SecretKeyRef.Key = "bar"
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].valueFrom.secretKeyRef"))
+		Expect(err.Error()).Should(ContainSubstring("key 'bar' not found"))
+	})
+	It("Resolves parameter value readable by a group of the requester", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-cm",
+				Namespace: "foo",
+			},
+			Data: map[string]string{
+				"foo": "bar",
+			},
+		}
+		fakeClient := &accessReviewClient{
+			Client:       fake.NewFakeClientWithScheme(scheme, ct, configMap),
+			allowedGroup: "cm-readers",
+		}
+		instanceControllerClient = fakeClient
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation:       "foo",
+					CTIRequesterGroupsAnnotation: "devs,cm-readers",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name: "foo",
+						ValueFrom: &ParameterValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foo-cm",
+								},
+								Key: "foo",
+							},
+						},
+					},
+				},
+			},
+		}
+		requesterCtx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "foo",
+					UID:      "foo-uid",
+					Groups:   []string{"devs", "cm-readers"},
+					Extra: map[string]authenticationv1.ExtraValue{
+						"scopes.authorization.openshift.io": {"user:full"},
+					},
+				},
+			},
+		})
+		Expect(cti.ValidateCreate(requesterCtx, &cti)).ShouldNot(HaveOccurred())
+		Expect(fakeClient.lastReview.Spec.User).Should(Equal("foo"))
+		Expect(fakeClient.lastReview.Spec.Groups).Should(Equal([]string{"devs", "cm-readers"}))
+		Expect(fakeClient.lastReview.Spec.UID).Should(Equal("foo-uid"))
+		Expect(fakeClient.lastReview.Spec.Extra).Should(Equal(map[string]authorizationv1.ExtraValue{
+			"scopes.authorization.openshift.io": {"user:full"},
+		}))
+
+		// Access is checked for the user who sends the request, not for the recorded requester
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("user 'test-user' cannot get configmaps 'foo-cm'"))
+		Expect(fakeClient.lastReview.Spec.UID).Should(BeEmpty())
+	})
+	It("Fails when requester cannot read parameter config map", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-cm",
+				Namespace: "foo",
+			},
+			Data: map[string]string{
+				"foo": "bar",
+			},
+		}
+		instanceControllerClient = &accessReviewClient{
+			Client:  fake.NewFakeClientWithScheme(scheme, ct, configMap),
+			allowed: false,
+		}
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name: "foo",
+						ValueFrom: &ParameterValueSource{
+							ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{
+									Name: "foo-cm",
+								},
+								Key: "foo",
+							},
+						},
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].valueFrom.configMapKeyRef"))
+		Expect(err.Error()).Should(ContainSubstring("user 'test-user' cannot get configmaps 'foo-cm'"))
+	})
+	It("Fails when other user adds parameter value source on update", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		err = corev1.AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-secret",
+				Namespace: "foo",
+			},
+			Data: map[string][]byte{
+				"foo": []byte("bar"),
+			},
+		}
+		fakeClient := &accessReviewClient{
+			Client:       fake.NewFakeClientWithScheme(scheme, ct, secret),
+			allowedGroup: "secret-readers",
+		}
+		instanceControllerClient = fakeClient
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation:       "foo",
+					CTIRequesterGroupsAnnotation: "secret-readers",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:  "bar",
+						Value: "bar",
+					},
+				},
+			},
+		}
+		secretSource := &ParameterValueSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: "foo-secret",
+				},
+				Key: "foo",
+			},
+		}
+		newCti := cti.DeepCopy()
+		newCti.Spec.Parameters = append(newCti.Spec.Parameters, Parameter{
+			Name:      "foo",
+			ValueFrom: secretSource,
+		})
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[1].valueFrom.secretKeyRef"))
+		Expect(err.Error()).Should(ContainSubstring("user 'test-user' cannot get secrets 'foo-secret'"))
+		Expect(fakeClient.lastReview.Spec.User).Should(Equal("test-user"))
+
+		// Sources set by the requester are not checked again when other users update the instance
+		cti = newCti.DeepCopy()
+		newCti.Spec.Parameters[0].Value = "baz"
+		Expect(newCti.ValidateUpdate(validationCtx, cti, newCti)).Should(Succeed())
+
+		newCti.Spec.Parameters[1].ValueFrom.SecretKeyRef.Key = "bar"
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("user 'test-user' cannot get secrets 'foo-secret'"))
+	})
+	It("Fails when parameter has both value and valueFrom", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				Parameters: []Parameter{
+					{
+						Name:      "foo",
+						Value:     "bar",
+						ValueFrom: &ParameterValueSource{},
+					},
+				},
+			},
+		}
+		err = cti.ValidateCreate(validationCtx, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].valueFrom"))
+	})
+
+	It("Fails when changing cluster provider annotation", func() {
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+		newCti := cti.DeepCopy()
+		newCti.Annotations[ClusterProviderAnnotation] = `{"version":"v1","kind":"Secret"}`
+
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("annotation 'clustertemplate.openshift.io/cluster-provider' cannot be changed"))
+	})
+
+	It("Fails when updating requester", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "bar",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		err := cti.ValidateUpdate(validationCtx, newCti, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("cluster requester cannot be changed"))
+	})
+
+	It("Fails when updating requester", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-bar",
+			},
+		}
+
+		err := cti.ValidateUpdate(validationCtx, newCti, &cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("spec is immutable"))
+	})
+	It("Succeeds when updating annotations", func() {
+		cti := ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+					"foo":                  "bar",
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		err := cti.ValidateUpdate(validationCtx, newCti, &cti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+	It("Succeeds when updating parameters", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-tmp",
+			},
+			Status: ClusterTemplateStatus{
+				ClusterDefinition: ClusterDefinitionSchema{
+					Values: "nodePool:\n  replicas: 2\n",
+					Schema: testSchema,
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.Parameters = []Parameter{
+			{
+				Name:  "nodePool.replicas",
+				Value: "3",
+			},
+		}
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		newCti.Spec.Parameters[0].Value = "abc"
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(apierrors.IsInvalid(err)).Should(BeTrue())
+		Expect(err.Error()).Should(ContainSubstring("spec.parameters[0].value"))
+	})
+	It("Succeeds when updating power state", func() {
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+				PowerState:         RunningPowerState,
+			},
+		}
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.PowerState = HibernatingPowerState
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
 		Expect(err).ShouldNot(HaveOccurred())
 	})
-	It("Fails when max insances reached", func() {
-		scheme := runtime.NewScheme()
-		err := AddToScheme(scheme)
-		Expect(err).NotTo(HaveOccurred())
-		ctq := &ClusterTemplateQuota{
+
+	It("Fails when hibernating cluster defined via kubeconfig secret", func() {
+		kubeconfigSecret := "foo-secret"
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
-				Name:      "bar",
+				Name:      "foo-instance",
 				Namespace: "foo",
 			},
-			Spec: ClusterTemplateQuotaSpec{
-				AllowedTemplates: []AllowedTemplate{
-					{
-						Name:  "foo-tmp",
-						Count: 1,
-					},
-				},
-			},
-			Status: ClusterTemplateQuotaStatus{
-				TemplateInstances: []AllowedTemplateStatus{
-					{
-						Name:  "foo-tmp",
-						Count: 1,
-					},
-				},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef:  "foo-tmp",
+				KubeconfigSecretRef: &kubeconfigSecret,
 			},
 		}
-		cost := 0
-		ct := &ClusterTemplate{
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.PowerState = HibernatingPowerState
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("cluster defined via kubeconfig secret cannot be hibernated"))
+	})
+
+	It("Succeeds when updating power schedule", func() {
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
-				Name: "foo-tmp",
+				Name:      "foo-instance",
+				Namespace: "foo",
 			},
-			Spec: ClusterTemplateSpec{
-				Cost: &cost,
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
 			},
 		}
-		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
-		cti := ClusterTemplateInstance{
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.PowerSchedule = &PowerSchedule{
+			Hibernate: "0 19 * * 1-5",
+			Resume:    "0 7 * * 1-5",
+			TimeZone:  "Europe/Prague",
+		}
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	It("Fails when power schedule is invalid", func() {
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      "foo-instance",
 				Namespace: "foo",
@@ -171,13 +1571,18 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 				ClusterTemplateRef: "foo-tmp",
 			},
 		}
-		err = cti.ValidateCreate()
+
+		newCti := cti.DeepCopy()
+		newCti.Spec.PowerSchedule = &PowerSchedule{
+			Hibernate: "0 19 * * 1-5",
+			Resume:    "every morning",
+		}
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
 		Expect(err).Should(HaveOccurred())
-		Expect(
-			err.Error(),
-		).Should(Equal("failed quota: cluster instance not allowed - maximum cluster instances reached"))
+		Expect(err.Error()).Should(ContainSubstring("invalid resume schedule 'every morning'"))
 	})
-	It("Passes when ctq allows template", func() {
+
+	It("Fails when lifetime exceeds maximum lifetime of the quota", func() {
 		scheme := runtime.NewScheme()
 		err := AddToScheme(scheme)
 		Expect(err).NotTo(HaveOccurred())
@@ -189,30 +1594,13 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 			Spec: ClusterTemplateQuotaSpec{
 				AllowedTemplates: []AllowedTemplate{
 					{
-						Name:  "foo-tmp",
-						Count: 1,
-					},
-				},
-			},
-			Status: ClusterTemplateQuotaStatus{
-				TemplateInstances: []AllowedTemplateStatus{
-					{
-						Name:  "foo-tmp",
-						Count: 0,
+						Name:        "foo-tmp",
+						MaxLifetime: &v1.Duration{Duration: 8 * time.Hour},
 					},
 				},
 			},
 		}
-		cost := 1
-		ct := &ClusterTemplate{
-			ObjectMeta: v1.ObjectMeta{
-				Name: "foo-tmp",
-			},
-			Spec: ClusterTemplateSpec{
-				Cost: &cost,
-			},
-		}
-		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq, ct)
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq)
 		cti := ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      "foo-instance",
@@ -220,109 +1608,143 @@ var _ = Describe("ClusterTemplateInstance validating webhook", func() {
 			},
 			Spec: ClusterTemplateInstanceSpec{
 				ClusterTemplateRef: "foo-tmp",
+				Lifetime:           &v1.Duration{Duration: 24 * time.Hour},
 			},
 		}
-		err = cti.ValidateCreate()
+		err = cti.checkLifetime()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("lifetime 24h0m0s exceeds maximum lifetime 8h0m0s of the quota"))
+
+		cti.Spec.Lifetime = &v1.Duration{Duration: 4 * time.Hour}
+		err = cti.checkLifetime()
 		Expect(err).ShouldNot(HaveOccurred())
 	})
 
-	It("Fails when updating requester", func() {
-		cti := ClusterTemplateInstance{
+	It("Validates lifetime extension against maximum lifetime of the quota", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
 			ObjectMeta: v1.ObjectMeta{
-				Name:      "foo-instance",
+				Name:      "bar",
 				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "foo",
-				},
 			},
-			Spec: ClusterTemplateInstanceSpec{
-				ClusterTemplateRef: "foo-tmp",
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name:        "foo-tmp",
+						MaxLifetime: &v1.Duration{Duration: 8 * time.Hour},
+					},
+				},
 			},
 		}
-
-		newCti := &ClusterTemplateInstance{
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ctq)
+		created := v1.NewTime(time.Now().Add(-time.Hour))
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
-				Name:      "foo-instance",
-				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "bar",
-				},
+				Name:              "foo-instance",
+				Namespace:         "foo",
+				CreationTimestamp: created,
 			},
 			Spec: ClusterTemplateInstanceSpec{
 				ClusterTemplateRef: "foo-tmp",
+				Lifetime:           &v1.Duration{Duration: 2 * time.Hour},
+			},
+			Status: ClusterTemplateInstanceStatus{
+				LifetimeExtension: &v1.Duration{Duration: 2 * time.Hour},
 			},
 		}
 
-		err := cti.ValidateUpdate(newCti)
+		newCti := cti.DeepCopy()
+		newCti.Annotations = map[string]string{CTIExtendAnnotation: "2h"}
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		newCti.Annotations[CTIExtendAnnotation] = "6h"
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
 		Expect(err).Should(HaveOccurred())
-		Expect(
-			err.Error(),
-		).Should(Equal("cluster requester cannot be changed"))
+		Expect(err.Error()).Should(Equal("lifetime extension exceeds maximum lifetime 8h0m0s of the quota"))
+
+		newCti.Annotations[CTIExtendAnnotation] = "tomorrow"
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("invalid lifetime extension 'tomorrow'"))
 	})
 
-	It("Fails when updating requester", func() {
-		cti := ClusterTemplateInstance{
+	It("Validates upgrade against upgrades allowed by the template", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
 			ObjectMeta: v1.ObjectMeta{
-				Name:      "foo-instance",
-				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "foo",
-				},
+				Name: "foo-tmp",
 			},
-			Spec: ClusterTemplateInstanceSpec{
-				ClusterTemplateRef: "foo-tmp",
+			Spec: ClusterTemplateSpec{
+				Upgrades: &ClusterTemplateUpgrades{
+					ReleaseImageParameter: "releaseImage",
+					Versions: []ClusterTemplateUpgradeVersion{
+						{Version: "4.12.1", ReleaseImage: "quay.io/ocp-release:4.12.1"},
+					},
+				},
 			},
 		}
-
-		newCti := &ClusterTemplateInstance{
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      "foo-instance",
 				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "foo",
-				},
 			},
 			Spec: ClusterTemplateInstanceSpec{
-				ClusterTemplateRef: "foo-bar",
+				ClusterTemplateRef: "foo-tmp",
 			},
 		}
 
-		err := cti.ValidateUpdate(newCti)
+		newCti := cti.DeepCopy()
+		newCti.Spec.Upgrade = &ClusterUpgradeTarget{Version: "4.12.1"}
+		Expect(newCti.ValidateUpdate(validationCtx, cti, newCti)).Should(Succeed())
+
+		newCti.Spec.Upgrade = &ClusterUpgradeTarget{ReleaseImage: "quay.io/ocp-release:4.12.1"}
+		Expect(newCti.ValidateUpdate(validationCtx, cti, newCti)).Should(Succeed())
+
+		newCti.Spec.Upgrade = &ClusterUpgradeTarget{Version: "4.13.0"}
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
 		Expect(err).Should(HaveOccurred())
-		Expect(
-			err.Error(),
-		).Should(Equal("spec is immutable"))
+		Expect(err.Error()).Should(Equal("upgrade is not allowed by cluster template 'foo-tmp'"))
+
+		newCti.Spec.Upgrade = &ClusterUpgradeTarget{}
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("either version or release image of the upgrade is required"))
+
+		cti.Spec.Upgrade = &ClusterUpgradeTarget{Version: "4.12.1"}
+		newCti.Spec.Upgrade = nil
+		err = newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("upgrade cannot be removed"))
 	})
-	It("Succeeds when updating annotations", func() {
-		cti := ClusterTemplateInstance{
+
+	It("Fails when updating parameters together with other spec fields", func() {
+		cti := &ClusterTemplateInstance{
 			ObjectMeta: v1.ObjectMeta{
 				Name:      "foo-instance",
 				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "foo",
-				},
 			},
 			Spec: ClusterTemplateInstanceSpec{
 				ClusterTemplateRef: "foo-tmp",
 			},
 		}
 
-		newCti := &ClusterTemplateInstance{
-			ObjectMeta: v1.ObjectMeta{
-				Name:      "foo-instance",
-				Namespace: "foo",
-				Annotations: map[string]string{
-					CTIRequesterAnnotation: "foo",
-					"foo":                  "bar",
-				},
-			},
-			Spec: ClusterTemplateInstanceSpec{
-				ClusterTemplateRef: "foo-tmp",
+		newCti := cti.DeepCopy()
+		newCti.Spec.ClusterTemplateRef = "foo-bar"
+		newCti.Spec.Parameters = []Parameter{
+			{
+				Name:  "foo",
+				Value: "bar",
 			},
 		}
-
-		err := cti.ValidateUpdate(newCti)
-		Expect(err).ShouldNot(HaveOccurred())
+		err := newCti.ValidateUpdate(validationCtx, cti, newCti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("spec is immutable"))
 	})
 })
 
@@ -534,4 +1956,117 @@ var _ = Describe("ClusterTemplateInstance mutating webhook", func() {
 		Expect(ok).To(BeTrue())
 		Expect(value).To(Equal("true"))
 	})
+	It("Rejects cluster provider annotation and invalid cluster provider", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).ShouldNot(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-template",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterProvider: &ClusterProviderConfig{
+					Group:         "infrastructure.cluster.x-k8s.io",
+					Version:       "v1alpha1",
+					Kind:          "VCluster",
+					ReadyJSONPath: "{.status.ready}",
+					KubeconfigSecretRef: ClusterProviderSecretRef{
+						Name: "vc-{.metadata.name}",
+						Key:  "config",
+					},
+				},
+			},
+		}
+		invalidCT := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "invalid-template",
+			},
+			Spec: ClusterTemplateSpec{
+				ClusterProvider: &ClusterProviderConfig{
+					Version: "v1alpha1",
+					Kind:    "VCluster",
+				},
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct, invalidCT)
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-template",
+			},
+		}
+		webhookCtx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "foo",
+				},
+			},
+		})
+		err = cti.Default(webhookCtx, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, ok := cti.Annotations[ClusterProviderAnnotation]
+		Expect(ok).To(BeFalse())
+
+		cti = &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: "foo",
+				Annotations: map[string]string{
+					ClusterProviderAnnotation: `{"version":"v1","kind":"Secret"}`,
+				},
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-template",
+			},
+		}
+		err = cti.Default(webhookCtx, cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(
+			Equal("annotation 'clustertemplate.openshift.io/cluster-provider' cannot be set"),
+		)
+
+		cti = &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "invalid-template",
+			},
+		}
+		err = cti.Default(webhookCtx, cti)
+		Expect(err).Should(HaveOccurred())
+	})
+	It("Adds requester annotations", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ct := &ClusterTemplate{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "foo-template",
+			},
+		}
+		instanceControllerClient = fake.NewFakeClientWithScheme(scheme, ct)
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-template",
+			},
+		}
+		ctx := context.TODO()
+		webhookCtx := admission.NewContextWithRequest(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "foo",
+					Groups:   []string{"developers", "system:authenticated"},
+				},
+			},
+		})
+		err = cti.Default(webhookCtx, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(cti.Annotations[CTIRequesterAnnotation]).To(Equal("foo"))
+		Expect(cti.GetRequesterGroups()).To(Equal([]string{"developers", "system:authenticated"}))
+	})
 })
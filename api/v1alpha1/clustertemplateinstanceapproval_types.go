@@ -0,0 +1,62 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type ClusterTemplateInstanceApprovalSpec struct {
+	// Name of the approved ClusterTemplateInstance in the namespace of the approval
+	ClusterTemplateInstanceRef string `json:"clusterTemplateInstanceRef"`
+	// +optional
+	// UID of the approved ClusterTemplateInstance. Set by the admission webhook, so an approval does not
+	// apply to a later instance of the same name.
+	ClusterTemplateInstanceUID types.UID `json:"clusterTemplateInstanceUID,omitempty"`
+	// +optional
+	// User who approved the instance. Set by the admission webhook to the user who created the approval.
+	Approver string `json:"approver,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=clustertemplateinstanceapprovals,shortName=ctia;ctias,scope=Namespaced
+//+kubebuilder:printcolumn:name="Instance",type="string",JSONPath=".spec.clusterTemplateInstanceRef",description="Approved instance"
+//+kubebuilder:printcolumn:name="Approver",type="string",JSONPath=".spec.approver",description="User who approved the instance"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template instance approval",resources={{Pod, v1, ""}}
+
+// Approves a ClusterTemplateInstance of a template which requires approval. The permission to create
+// approvals is granted via RBAC.
+type ClusterTemplateInstanceApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterTemplateInstanceApprovalSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateInstanceApprovalList contains a list of ClusterTemplateInstanceApproval
+type ClusterTemplateInstanceApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateInstanceApproval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateInstanceApproval{}, &ClusterTemplateInstanceApprovalList{})
+}
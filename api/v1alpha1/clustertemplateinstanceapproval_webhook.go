@@ -0,0 +1,122 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var clustertemplateinstanceapprovallog = logf.Log.WithName("clustertemplateinstanceapproval-resource")
+var approvalControllerClient client.Client
+
+func (r *ClusterTemplateInstanceApproval) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	approvalControllerClient = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(approvalWebhook).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/mutate-clustertemplate-openshift-io-v1alpha1-clustertemplateinstanceapproval,mutating=true,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplateinstanceapprovals,verbs=create,versions=v1alpha1,name=mclustertemplateinstanceapproval.kb.io,admissionReviewVersions=v1
+
+var approvalWebhook webhook.CustomDefaulter = &ClusterTemplateInstanceApproval{}
+
+// Default records the user who created the approval as the approver and the UID of the approved
+// instance
+func (r *ClusterTemplateInstanceApproval) Default(ctx context.Context, obj runtime.Object) error {
+	approval := obj.(*ClusterTemplateInstanceApproval)
+	clustertemplateinstanceapprovallog.Info("default", "name", approval.Name)
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	approval.Spec.Approver = req.UserInfo.Username
+
+	cti, err := approval.getInstance()
+	if err != nil {
+		return err
+	}
+	approval.Spec.ClusterTemplateInstanceUID = cti.UID
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplateinstanceapproval,mutating=false,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplateinstanceapprovals,verbs=create;update,versions=v1alpha1,name=vclustertemplateinstanceapproval.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &ClusterTemplateInstanceApproval{}
+
+func (r *ClusterTemplateInstanceApproval) ValidateCreate() error {
+	clustertemplateinstanceapprovallog.Info("validate create", "name", r.Name)
+
+	cti, err := r.getInstance()
+	if err != nil {
+		return err
+	}
+
+	if cti.UID != r.Spec.ClusterTemplateInstanceUID {
+		return fmt.Errorf(
+			"approval does not match the UID of cluster template instance '%s'",
+			r.Spec.ClusterTemplateInstanceRef,
+		)
+	}
+	if cti.Annotations[CTIRequesterAnnotation] == r.Spec.Approver {
+		return fmt.Errorf("cluster requester cannot approve own cluster instance")
+	}
+	return nil
+}
+
+func (r *ClusterTemplateInstanceApproval) getInstance() (*ClusterTemplateInstance, error) {
+	cti := &ClusterTemplateInstance{}
+	if err := approvalControllerClient.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: r.Namespace, Name: r.Spec.ClusterTemplateInstanceRef},
+		cti,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("cluster template instance '%s' not found", r.Spec.ClusterTemplateInstanceRef)
+		}
+		return nil, fmt.Errorf("failed to get cluster template instance - %q", err)
+	}
+	return cti, nil
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplateInstanceApproval) ValidateUpdate(old runtime.Object) error {
+	clustertemplateinstanceapprovallog.Info("validate update", "name", r.Name)
+	oldApproval := old.(*ClusterTemplateInstanceApproval)
+
+	if !equality.Semantic.DeepEqual(r.Spec, oldApproval.Spec) {
+		return fmt.Errorf("spec is immutable")
+	}
+	return nil
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ClusterTemplateInstanceApproval) ValidateDelete() error {
+	clustertemplateinstanceapprovallog.Info("validate delete", "name", r.Name)
+	return nil
+}
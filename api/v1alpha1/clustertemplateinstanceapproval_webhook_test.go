@@ -0,0 +1,113 @@
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var _ = Describe("ClusterTemplateInstanceApproval webhook", func() {
+	It("Records the approver and the approved instance", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				UID:       "foo-uid",
+			},
+		}
+		approvalControllerClient = fake.NewFakeClientWithScheme(scheme, cti)
+		approval := &ClusterTemplateInstanceApproval{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-approval",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceApprovalSpec{
+				ClusterTemplateInstanceRef: "foo-instance",
+				Approver:                   "bar",
+			},
+		}
+		webhookCtx := admission.NewContextWithRequest(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{
+					Username: "admin",
+				},
+			},
+		})
+		err = approval.Default(webhookCtx, approval)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(approval.Spec.Approver).To(Equal("admin"))
+		Expect(approval.Spec.ClusterTemplateInstanceUID).To(Equal(cti.UID))
+	})
+
+	It("Validates the approved instance", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-instance",
+				Namespace: "foo",
+				UID:       "foo-uid",
+				Annotations: map[string]string{
+					CTIRequesterAnnotation: "foo",
+				},
+			},
+		}
+		approvalControllerClient = fake.NewFakeClientWithScheme(scheme, cti)
+		approval := &ClusterTemplateInstanceApproval{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-approval",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateInstanceApprovalSpec{
+				ClusterTemplateInstanceRef: "foo-instance",
+				ClusterTemplateInstanceUID: "foo-uid",
+				Approver:                   "admin",
+			},
+		}
+		err = approval.ValidateCreate()
+		Expect(err).ShouldNot(HaveOccurred())
+
+		approval.Spec.ClusterTemplateInstanceUID = "bar-uid"
+		err = approval.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(
+			err.Error(),
+		).Should(Equal("approval does not match the UID of cluster template instance 'foo-instance'"))
+		approval.Spec.ClusterTemplateInstanceUID = "foo-uid"
+
+		approval.Spec.Approver = "foo"
+		err = approval.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("cluster requester cannot approve own cluster instance"))
+
+		approval.Spec.ClusterTemplateInstanceRef = "bar-instance"
+		err = approval.ValidateCreate()
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("cluster template instance 'bar-instance' not found"))
+	})
+
+	It("Fails when approval is changed", func() {
+		oldApproval := &ClusterTemplateInstanceApproval{
+			Spec: ClusterTemplateInstanceApprovalSpec{
+				ClusterTemplateInstanceRef: "foo-instance",
+				Approver:                   "admin",
+			},
+		}
+		approval := oldApproval.DeepCopy()
+		approval.Spec.ClusterTemplateInstanceRef = "bar-instance"
+		err := approval.ValidateUpdate(oldApproval)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("spec is immutable"))
+	})
+})
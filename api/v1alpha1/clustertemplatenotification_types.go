@@ -0,0 +1,153 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Lifecycle event of a ClusterTemplateInstance which is notified
+// +kubebuilder:validation:Enum=Ready;Failed;Expiring
+type NotificationEvent string
+
+const (
+	// The cluster of the instance is ready
+	ReadyNotificationEvent NotificationEvent = "Ready"
+	// The instance entered a failed phase
+	FailedNotificationEvent NotificationEvent = "Failed"
+	// The instance is going to be removed because its lifetime expires
+	ExpiringNotificationEvent NotificationEvent = "Expiring"
+)
+
+type DeliveryState string
+
+const (
+	PendingDeliveryState   DeliveryState = "Pending"
+	DeliveredDeliveryState DeliveryState = "Delivered"
+	FailedDeliveryState    DeliveryState = "Failed"
+)
+
+type WebhookNotification struct {
+	// URL which receives the notification via HTTP POST
+	URL string `json:"url"`
+	// +optional
+	// Go template of the JSON body. The template gets the namespace, the instance, the template,
+	// the event, the phase, the message and the expiration of the instance. The json function
+	// quotes a value as JSON string. A default body is sent if not set.
+	Body string `json:"body,omitempty"`
+	// +optional
+	// Key of a secret in the namespace of the notification which signs the body with HMAC-SHA256.
+	// The signature is sent in the X-Signature-256 header.
+	HMACSecretRef *corev1.SecretKeySelector `json:"hmacSecretRef,omitempty"`
+}
+
+type EmailNotification struct {
+	// Address of the SMTP server in host:port format
+	SMTPServer string `json:"smtpServer"`
+	// Sender of the email
+	From string `json:"from"`
+	// +kubebuilder:validation:MinItems=1
+	// Recipients of the email
+	To []string `json:"to"`
+	// +optional
+	// Secret in the namespace of the notification with username and password keys which are used
+	// to authenticate to the SMTP server
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+type ClusterTemplateNotificationSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	// Events of the instances in the namespace which are notified
+	Events []NotificationEvent `json:"events"`
+	// +optional
+	// Time before the removal of the instance when the Expiring event is notified. Defaults to 30m.
+	ExpiringBefore *metav1.Duration `json:"expiringBefore,omitempty"`
+	// +optional
+	// Sends the notifications to an HTTP webhook
+	Webhook *WebhookNotification `json:"webhook,omitempty"`
+	// +optional
+	// Sends the notifications via email
+	Email *EmailNotification `json:"email,omitempty"`
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	// Number of retries of a failed delivery
+	MaxRetries int `json:"maxRetries,omitempty"`
+}
+
+type NotificationDelivery struct {
+	// Name of the ClusterTemplateInstance
+	Instance string `json:"instance"`
+	// Name of the template of the instance
+	Template string `json:"template"`
+	// Notified event
+	Event NotificationEvent `json:"event"`
+	// Phase of the instance when the event happened
+	Phase Phase `json:"phase"`
+	// Message of the phase
+	Message string `json:"message"`
+	// +optional
+	// Time when the instance is removed
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+	// Time when the event happened
+	CreatedAt metav1.Time `json:"createdAt"`
+	// State of the delivery
+	State DeliveryState `json:"state"`
+	// Number of delivery attempts
+	Attempts int `json:"attempts"`
+	// +optional
+	// Time of the last delivery attempt
+	LastAttempt *metav1.Time `json:"lastAttempt,omitempty"`
+	// +optional
+	// Error of the last delivery attempt
+	Error string `json:"error,omitempty"`
+}
+
+type ClusterTemplateNotificationStatus struct {
+	// Recent deliveries of the notifications, the oldest ones are removed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Deliveries []NotificationDelivery `json:"deliveries,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustertemplatenotifications,shortName=ctn;ctns,scope=Namespaced
+//+kubebuilder:printcolumn:name="Events",type="string",JSONPath=".spec.events",description="Notified events"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template notification",resources={{Pod, v1, ""}}
+
+// Notifies lifecycle events of the ClusterTemplateInstances in the namespace via HTTP webhook or email
+type ClusterTemplateNotification struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTemplateNotificationSpec   `json:"spec,omitempty"`
+	Status ClusterTemplateNotificationStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplateNotificationList contains a list of ClusterTemplateNotification
+type ClusterTemplateNotificationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplateNotification `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplateNotification{}, &ClusterTemplateNotificationList{})
+}
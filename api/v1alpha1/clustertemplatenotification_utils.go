@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Time before the removal of the instance when the Expiring event is notified by default
+	DefaultExpiringBefore = 30 * time.Minute
+	// Number of deliveries which are kept in the status of the notification
+	MaxNotificationDeliveries = 50
+	// Delay of the first retry of a failed delivery, every next retry is delayed twice as long
+	deliveryRetryDelay = 30 * time.Second
+)
+
+// Notifies returns true if the notification is sent on the event
+func (n *ClusterTemplateNotification) Notifies(event NotificationEvent) bool {
+	return slices.Contains(n.Spec.Events, event)
+}
+
+// GetExpiringBefore returns the time before the removal of the instance when the Expiring event
+// is notified
+func (n *ClusterTemplateNotification) GetExpiringBefore() time.Duration {
+	if n.Spec.ExpiringBefore == nil {
+		return DefaultExpiringBefore
+	}
+	return n.Spec.ExpiringBefore.Duration
+}
+
+// AddDelivery adds a pending delivery to the status. The Expiring event is delivered once per
+// expiration of the instance, false is returned if it was already added. The oldest finished
+// deliveries are removed once there are more than MaxNotificationDeliveries.
+func (n *ClusterTemplateNotification) AddDelivery(delivery NotificationDelivery) bool {
+	if delivery.Event == ExpiringNotificationEvent {
+		for _, existing := range n.Status.Deliveries {
+			if existing.Instance == delivery.Instance &&
+				existing.Event == ExpiringNotificationEvent &&
+				isSameSecond(existing.ExpiresAt, delivery.ExpiresAt) {
+				return false
+			}
+		}
+	}
+	delivery.State = PendingDeliveryState
+	n.Status.Deliveries = append(n.Status.Deliveries, delivery)
+
+	for i := 0; i < len(n.Status.Deliveries) && len(n.Status.Deliveries) > MaxNotificationDeliveries; {
+		if n.Status.Deliveries[i].State == PendingDeliveryState {
+			i++
+			continue
+		}
+		n.Status.Deliveries = append(n.Status.Deliveries[:i], n.Status.Deliveries[i+1:]...)
+	}
+	// Too many pending deliveries, the oldest ones are dropped
+	if len(n.Status.Deliveries) > MaxNotificationDeliveries {
+		n.Status.Deliveries = n.Status.Deliveries[len(n.Status.Deliveries)-MaxNotificationDeliveries:]
+	}
+	return true
+}
+
+// GetDelivery returns the delivery of the same event of the same instance, nil if it was removed
+func (n *ClusterTemplateNotification) GetDelivery(delivery NotificationDelivery) *NotificationDelivery {
+	for i := range n.Status.Deliveries {
+		existing := &n.Status.Deliveries[i]
+		if existing.Instance == delivery.Instance &&
+			existing.Event == delivery.Event &&
+			isSameSecond(&existing.CreatedAt, &delivery.CreatedAt) &&
+			isSameSecond(existing.ExpiresAt, delivery.ExpiresAt) {
+			return existing
+		}
+	}
+	return nil
+}
+
+// The time is serialized with second precision
+func isSameSecond(a *metav1.Time, b *metav1.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Unix() == b.Unix()
+}
+
+// NextAttempt returns the time of the next delivery attempt, the failed attempts are retried with
+// exponential backoff
+func (d *NotificationDelivery) NextAttempt() time.Time {
+	if d.LastAttempt == nil || d.Attempts == 0 {
+		return d.CreatedAt.Time
+	}
+	return d.LastAttempt.Add(deliveryRetryDelay << (d.Attempts - 1))
+}
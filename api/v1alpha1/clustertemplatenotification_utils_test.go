@@ -0,0 +1,100 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClusterTemplateNotification utils", func() {
+	It("Notifies", func() {
+		ctn := &ClusterTemplateNotification{
+			Spec: ClusterTemplateNotificationSpec{
+				Events: []NotificationEvent{ReadyNotificationEvent},
+			},
+		}
+		Expect(ctn.Notifies(ReadyNotificationEvent)).Should(BeTrue())
+		Expect(ctn.Notifies(ExpiringNotificationEvent)).Should(BeFalse())
+		Expect(ctn.GetExpiringBefore()).Should(Equal(DefaultExpiringBefore))
+
+		ctn.Spec.ExpiringBefore = &metav1.Duration{Duration: time.Hour}
+		Expect(ctn.GetExpiringBefore()).Should(Equal(time.Hour))
+	})
+
+	It("AddDelivery", func() {
+		ctn := &ClusterTemplateNotification{}
+		expiresAt := metav1.NewTime(time.Now().Add(time.Hour))
+		delivery := NotificationDelivery{
+			Instance:  "foo",
+			Event:     ExpiringNotificationEvent,
+			ExpiresAt: &expiresAt,
+		}
+		Expect(ctn.AddDelivery(delivery)).Should(BeTrue())
+		Expect(ctn.Status.Deliveries[0].State).Should(Equal(PendingDeliveryState))
+		Expect(ctn.AddDelivery(delivery)).Should(BeFalse())
+
+		extended := metav1.NewTime(expiresAt.Add(time.Hour))
+		delivery.ExpiresAt = &extended
+		Expect(ctn.AddDelivery(delivery)).Should(BeTrue())
+
+		ready := NotificationDelivery{Instance: "foo", Event: ReadyNotificationEvent}
+		Expect(ctn.AddDelivery(ready)).Should(BeTrue())
+		Expect(ctn.AddDelivery(ready)).Should(BeTrue())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(4))
+	})
+
+	It("AddDelivery removes the oldest finished deliveries", func() {
+		ctn := &ClusterTemplateNotification{}
+		for i := 0; i < MaxNotificationDeliveries; i++ {
+			ctn.AddDelivery(NotificationDelivery{
+				Instance: fmt.Sprintf("foo-%d", i),
+				Event:    ReadyNotificationEvent,
+			})
+			if i > 0 {
+				ctn.Status.Deliveries[i].State = DeliveredDeliveryState
+			}
+		}
+		ctn.AddDelivery(NotificationDelivery{Instance: "bar", Event: FailedNotificationEvent})
+
+		Expect(ctn.Status.Deliveries).Should(HaveLen(MaxNotificationDeliveries))
+		Expect(ctn.Status.Deliveries[0].Instance).Should(Equal("foo-0"))
+		Expect(ctn.Status.Deliveries[1].Instance).Should(Equal("foo-2"))
+		Expect(ctn.Status.Deliveries[MaxNotificationDeliveries-1].Instance).Should(Equal("bar"))
+	})
+
+	It("NextAttempt", func() {
+		now := time.Now()
+		delivery := NotificationDelivery{CreatedAt: metav1.NewTime(now)}
+		Expect(delivery.NextAttempt()).Should(Equal(now))
+
+		delivery.Attempts = 1
+		delivery.LastAttempt = &metav1.Time{Time: now}
+		Expect(delivery.NextAttempt()).Should(Equal(now.Add(30 * time.Second)))
+
+		delivery.Attempts = 3
+		Expect(delivery.NextAttempt()).Should(Equal(now.Add(2 * time.Minute)))
+	})
+
+	It("GetDelivery", func() {
+		now := time.Now()
+		ctn := &ClusterTemplateNotification{}
+		ctn.AddDelivery(NotificationDelivery{
+			Instance:  "foo",
+			Event:     ReadyNotificationEvent,
+			CreatedAt: metav1.NewTime(now),
+		})
+		// Serialized with second precision
+		delivery := NotificationDelivery{
+			Instance:  "foo",
+			Event:     ReadyNotificationEvent,
+			CreatedAt: metav1.NewTime(now.Truncate(time.Second)),
+		}
+		Expect(ctn.GetDelivery(delivery)).Should(Equal(&ctn.Status.Deliveries[0]))
+
+		delivery.Event = FailedNotificationEvent
+		Expect(ctn.GetDelivery(delivery)).Should(BeNil())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var clustertemplatenotificationlog = logf.Log.WithName("clustertemplatenotification-resource")
+var notificationControllerClient client.Client
+
+func (r *ClusterTemplateNotification) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	notificationControllerClient = mgr.GetClient()
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(notificationValidator).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-clustertemplate-openshift-io-v1alpha1-clustertemplatenotification,mutating=false,failurePolicy=fail,sideEffects=None,groups=clustertemplate.openshift.io,resources=clustertemplatenotifications,verbs=create;update,versions=v1alpha1,name=vclustertemplatenotification.kb.io,admissionReviewVersions=v1
+
+var notificationValidator webhook.CustomValidator = &ClusterTemplateNotification{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateNotification) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	ctn := obj.(*ClusterTemplateNotification)
+	clustertemplatenotificationlog.Info("validate create", "name", ctn.Name)
+	return ctn.validate(ctx)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateNotification) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) error {
+	oldCtn := oldObj.(*ClusterTemplateNotification)
+	ctn := newObj.(*ClusterTemplateNotification)
+	clustertemplatenotificationlog.Info("validate update", "name", ctn.Name)
+	if equality.Semantic.DeepEqual(oldCtn.Spec, ctn.Spec) {
+		return nil
+	}
+	return ctn.validate(ctx)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*ClusterTemplateNotification) ValidateDelete(ctx context.Context, obj runtime.Object) error {
+	return nil
+}
+
+// validate checks the webhook URL and that the user who sets the spec can read the referenced
+// secrets, since the notifications are delivered with the privileges of the operator
+func (r *ClusterTemplateNotification) validate(ctx context.Context) error {
+	secrets := []string{}
+	if r.Spec.Webhook != nil {
+		target, err := url.Parse(r.Spec.Webhook.URL)
+		if err != nil {
+			return fmt.Errorf("invalid webhook url - %q", err)
+		}
+		if (target.Scheme != "http" && target.Scheme != "https") || target.Hostname() == "" {
+			return fmt.Errorf("webhook url '%s' is not an absolute http or https url", r.Spec.Webhook.URL)
+		}
+		if r.Spec.Webhook.HMACSecretRef != nil {
+			secrets = append(secrets, r.Spec.Webhook.HMACSecretRef.Name)
+		}
+	}
+	if r.Spec.Email != nil && r.Spec.Email.CredentialsSecretRef != nil {
+		secrets = append(secrets, r.Spec.Email.CredentialsSecretRef.Name)
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   req.UserInfo.Username,
+				Groups: req.UserInfo.Groups,
+				UID:    req.UserInfo.UID,
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: r.Namespace,
+					Verb:      "get",
+					Resource:  "secrets",
+					Name:      secret,
+				},
+			},
+		}
+		if len(req.UserInfo.Extra) > 0 {
+			sar.Spec.Extra = map[string]authorizationv1.ExtraValue{}
+			for key, value := range req.UserInfo.Extra {
+				sar.Spec.Extra[key] = authorizationv1.ExtraValue(value)
+			}
+		}
+		if err := notificationControllerClient.Create(context.TODO(), sar); err != nil {
+			return fmt.Errorf("failed to check access of '%s' - %q", req.UserInfo.Username, err)
+		}
+		if !sar.Status.Allowed {
+			return fmt.Errorf("user '%s' cannot get secrets '%s'", req.UserInfo.Username, secret)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplateNotification webhook", func() {
+	var ctn *ClusterTemplateNotification
+	var fakeClient *accessReviewClient
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).Should(Succeed())
+		fakeClient = &accessReviewClient{Client: fake.NewFakeClientWithScheme(scheme)}
+		notificationControllerClient = fakeClient
+		ctn = &ClusterTemplateNotification{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      "foo-notification",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateNotificationSpec{
+				Webhook: &WebhookNotification{
+					URL: "https://hooks.example.com/caas",
+					HMACSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "hmac"},
+						Key:                  "key",
+					},
+				},
+			},
+		}
+	})
+
+	It("Checks that the user can read the referenced secrets", func() {
+		err := ctn.ValidateCreate(validationCtx, ctn)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("user 'test-user' cannot get secrets 'hmac'"))
+		Expect(fakeClient.lastReview.Spec.User).Should(Equal("test-user"))
+		Expect(fakeClient.lastReview.Spec.ResourceAttributes.Namespace).Should(Equal("foo"))
+
+		fakeClient.allowed = true
+		Expect(ctn.ValidateCreate(validationCtx, ctn)).Should(Succeed())
+	})
+
+	It("Checks the secrets only when the spec changes", func() {
+		newCtn := ctn.DeepCopy()
+		newCtn.Labels = map[string]string{"foo": "bar"}
+		Expect(ctn.ValidateUpdate(validationCtx, ctn, newCtn)).Should(Succeed())
+
+		newCtn.Spec.Email = &EmailNotification{
+			SMTPServer:           "smtp.example.com:587",
+			From:                 "caas@example.com",
+			To:                   []string{"dev@example.com"},
+			CredentialsSecretRef: &corev1.LocalObjectReference{Name: "smtp"},
+		}
+		err := ctn.ValidateUpdate(validationCtx, ctn, newCtn)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("user 'test-user' cannot get secrets 'hmac'"))
+	})
+
+	It("Fails on invalid webhook url", func() {
+		fakeClient.allowed = true
+		ctn.Spec.Webhook.URL = "file:///etc/passwd"
+		err := ctn.ValidateCreate(validationCtx, ctn)
+		Expect(err).Should(HaveOccurred())
+		Expect(err.Error()).Should(Equal("webhook url 'file:///etc/passwd' is not an absolute http or https url"))
+	})
+})
@@ -0,0 +1,80 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	CTIPoolLabel           = "clustertemplatepool.openshift.io/name"
+	CTIPoolClaimAnnotation = "clustertemplatepool.openshift.io/claimed-by"
+)
+
+type ClusterTemplatePoolSpec struct {
+	// A reference to ClusterTemplate which is used for the pooled instances
+	ClusterTemplateRef string `json:"clusterTemplateRef"`
+	// +optional
+	// Version of the ClusterTemplate which is used for the pooled instances
+	ClusterTemplateVersion string `json:"clusterTemplateVersion,omitempty"`
+	// +optional
+	// Helm parameters of the pooled instances. Only instances with the same parameters can claim
+	// a cluster from the pool.
+	Parameters []Parameter `json:"parameters,omitempty"`
+	// +kubebuilder:validation:Minimum=0
+	// Number of pre-provisioned clusters which are kept in the pool
+	Size int `json:"size"`
+}
+
+type ClusterTemplatePoolStatus struct {
+	// Number of clusters in the pool which were not claimed yet
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Size int `json:"size"`
+	// Number of clusters in the pool which are ready to be claimed
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Ready int `json:"ready"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:path=clustertemplatepools,shortName=ctp;ctps,scope=Cluster
+//+kubebuilder:printcolumn:name="Template",type="string",JSONPath=".spec.clusterTemplateRef",description="Cluster template"
+//+kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".spec.size",description="Desired size of the pool"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready",description="Clusters ready to be claimed"
+//+operator-sdk:csv:customresourcedefinitions:displayName="Cluster template pool",resources={{Pod, v1, ""}}
+
+// Keeps pre-provisioned clusters of a ClusterTemplate which are claimed by new ClusterTemplateInstances
+type ClusterTemplatePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterTemplatePoolSpec   `json:"spec,omitempty"`
+	Status ClusterTemplatePoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterTemplatePoolList contains a list of ClusterTemplatePool
+type ClusterTemplatePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterTemplatePool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterTemplatePool{}, &ClusterTemplatePoolList{})
+}
@@ -0,0 +1,48 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CanBeClaimedBy returns true if the clusters of the pool match the template, version and parameters
+// of the instance
+func (p *ClusterTemplatePool) CanBeClaimedBy(cti *ClusterTemplateInstance) bool {
+	if cti.Spec.KubeconfigSecretRef != nil || cti.IsPooled() {
+		return false
+	}
+	if p.Spec.ClusterTemplateRef != cti.Spec.ClusterTemplateRef ||
+		p.Spec.ClusterTemplateVersion != cti.Spec.ClusterTemplateVersion {
+		return false
+	}
+	if len(p.Spec.Parameters) == 0 && len(cti.Spec.Parameters) == 0 {
+		return true
+	}
+	return equality.Semantic.DeepEqual(p.Spec.Parameters, cti.Spec.Parameters)
+}
+
+// GetPooledInstance returns a new instance of the pool
+func (p *ClusterTemplatePool) GetPooledInstance(namespace string) *ClusterTemplateInstance {
+	return &ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: p.Name + "-",
+			Namespace:    namespace,
+			Labels: map[string]string{
+				CTIPoolLabel: p.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					Kind:       "ClusterTemplatePool",
+					APIVersion: APIVersion,
+					Name:       p.Name,
+					UID:        p.UID,
+				},
+			},
+		},
+		Spec: ClusterTemplateInstanceSpec{
+			ClusterTemplateRef:     p.Spec.ClusterTemplateRef,
+			ClusterTemplateVersion: p.Spec.ClusterTemplateVersion,
+			Parameters:             p.Spec.Parameters,
+		},
+	}
+}
@@ -0,0 +1,111 @@
+package v1alpha1
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplatePool utils", func() {
+	pool := ClusterTemplatePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "foo-pool",
+		},
+		Spec: ClusterTemplatePoolSpec{
+			ClusterTemplateRef: "foo",
+			Size:               2,
+		},
+	}
+
+	It("CanBeClaimedBy", func() {
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "bar-ns",
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo",
+			},
+		}
+		Expect(pool.CanBeClaimedBy(cti)).Should(BeTrue())
+
+		cti.Spec.ClusterTemplateVersion = "v2"
+		Expect(pool.CanBeClaimedBy(cti)).Should(BeFalse())
+
+		cti.Spec.ClusterTemplateVersion = ""
+		cti.Spec.Parameters = []Parameter{{Name: "foo", Value: "bar"}}
+		Expect(pool.CanBeClaimedBy(cti)).Should(BeFalse())
+
+		cti.Spec.Parameters = nil
+		cti.Labels = map[string]string{CTIPoolLabel: pool.Name}
+		Expect(pool.CanBeClaimedBy(cti)).Should(BeFalse())
+	})
+
+	It("GetPooledInstance", func() {
+		cti := pool.GetPooledInstance("pool-ns")
+		Expect(cti.Namespace).Should(Equal("pool-ns"))
+		Expect(cti.GenerateName).Should(Equal("foo-pool-"))
+		Expect(cti.IsPooled()).Should(BeTrue())
+		Expect(cti.Spec.ClusterTemplateRef).Should(Equal("foo"))
+		Expect(cti.OwnerReferences[0].Kind).Should(Equal("ClusterTemplatePool"))
+	})
+
+	It("MoveDay1Application", func() {
+		pooled := pool.GetPooledInstance("pool-ns")
+		pooled.Name = "foo-pool-abc"
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "bar-ns",
+			},
+		}
+		labels := func() map[string]string {
+			return map[string]string{
+				CTINameLabel:      pooled.Name,
+				CTINamespaceLabel: pooled.Namespace,
+			}
+		}
+		appset := &argo.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "appset",
+				Namespace: "argocd",
+			},
+			Spec: argo.ApplicationSetSpec{
+				Generators: []argo.ApplicationSetGenerator{
+					{
+						List: &argo.ListGenerator{
+							Template: argo.ApplicationSetTemplate{
+								ApplicationSetTemplateMeta: argo.ApplicationSetTemplateMeta{
+									Labels: labels(),
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		app := &argo.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "argocd",
+				Labels:    labels(),
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, appset, app)
+
+		err := pooled.MoveDay1Application(ctx, k8sClient, "argocd", "appset", cti)
+		Expect(err).ShouldNot(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(appset), appset)).Should(Succeed())
+		Expect(appset.Spec.Generators[0].List.Template.Labels[CTINameLabel]).Should(Equal("bar"))
+		Expect(appset.Spec.Generators[0].List.Template.Labels[CTINamespaceLabel]).Should(Equal("bar-ns"))
+
+		movedApp, err := cti.GetDay1Application(ctx, k8sClient, "argocd")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(movedApp.Name).Should(Equal("app"))
+	})
+})
@@ -18,8 +18,39 @@ package v1alpha1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
+type BillingPeriod string
+
+const (
+	WeeklyBillingPeriod  BillingPeriod = "Weekly"
+	MonthlyBillingPeriod BillingPeriod = "Monthly"
+)
+
+type Billing struct {
+	// +kubebuilder:validation:Enum=Weekly;Monthly
+	// Period after which the spent budget is reset. Weekly periods start on Monday, monthly periods
+	// on the first day of the month (UTC).
+	Period BillingPeriod `json:"period"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// Budget which can be spent by the hourly cost of the clusters during one period
+	Budget int `json:"budget,omitempty"`
+}
+
+type BillingStatus struct {
+	// Start of the current billing period
+	PeriodStart metav1.Time `json:"periodStart"`
+	// Hourly cost of all clusters accumulated in the current period
+	Spent int `json:"spent"`
+	// Hourly cost of the removed clusters accumulated in the current period
+	Settled int `json:"settled"`
+	// +optional
+	// UIDs of the removed instances which are included in the settled cost
+	SettledInstances []types.UID `json:"settledInstances,omitempty"`
+}
+
 type AllowedTemplateStatus struct {
 	// Name of the ClusterTemplate
 	Name string `json:"name"`
@@ -44,6 +75,82 @@ type AllowedTemplate struct {
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
 	DeleteAfter *metav1.Duration `json:"deleteAfter,omitempty"`
+	// Maximum lifetime of the template instance, including the lifetime requested by the instance
+	// and its extensions
+	// +optional
+	// +kubebuilder:validation:Type=string
+	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
+	MaxLifetime *metav1.Duration `json:"maxLifetime,omitempty"`
+	// +optional
+	// Schedule of automatic hibernation and resume of the template instances
+	PowerSchedule *PowerSchedule `json:"powerSchedule,omitempty"`
+	// +optional
+	// Instances of the template are not provisioned until they are approved by a
+	// ClusterTemplateInstanceApproval
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+}
+
+type RequesterTemplateLimit struct {
+	// Name of the ClusterTemplate
+	Name string `json:"name"`
+	// +kubebuilder:validation:Minimum=1
+	// Defines how many instances of the ClusterTemplate can exist per requester
+	Count int `json:"count"`
+}
+
+type RequesterLimit struct {
+	// +optional
+	// Users the limit applies to
+	Users []string `json:"users,omitempty"`
+	// +optional
+	// Groups the limit applies to. The limit applies to each member of the group separately.
+	Groups []string `json:"groups,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// Budget for all clusters of a single requester
+	Budget int `json:"budget,omitempty"`
+	// +optional
+	// Defines how many instances of the ClusterTemplates can exist per requester
+	AllowedTemplates []RequesterTemplateLimit `json:"allowedTemplates,omitempty"`
+}
+
+type RequesterStatus struct {
+	// Name of the user who requested the instances
+	Name string `json:"name"`
+	// How much budget is currently spent by the requester
+	BudgetSpent int `json:"budgetSpent"`
+	// Which instances are in use by the requester
+	TemplateInstances []AllowedTemplateStatus `json:"templateInstances"`
+}
+
+type QuotaReservation struct {
+	// Namespace of the ClusterTemplateInstance
+	Namespace string `json:"namespace"`
+	// Name of the ClusterTemplateInstance
+	Name string `json:"name"`
+	// Name of the ClusterTemplate
+	ClusterTemplate string `json:"clusterTemplate"`
+	// Reserved budget
+	Cost int `json:"cost"`
+	// +optional
+	// User who requested the ClusterTemplateInstance
+	Requester string `json:"requester,omitempty"`
+	// Time when the reservation was made
+	ReservedAt metav1.Time `json:"reservedAt"`
+}
+
+type QueuedInstance struct {
+	// Namespace of the ClusterTemplateInstance
+	Namespace string `json:"namespace"`
+	// Name of the ClusterTemplateInstance
+	Name string `json:"name"`
+	// Name of the ClusterTemplate
+	ClusterTemplate string `json:"clusterTemplate"`
+	// +optional
+	// Priority of the ClusterTemplateInstance
+	Priority int `json:"priority,omitempty"`
+	// Time when the instance was queued
+	QueuedAt metav1.Time `json:"queuedAt"`
 }
 
 type ClusterTemplateQuotaSpec struct {
@@ -53,6 +160,23 @@ type ClusterTemplateQuotaSpec struct {
 	Budget int `json:"budget,omitempty"`
 	// Represents all ClusterTemplates which can be used in given namespace
 	AllowedTemplates []AllowedTemplate `json:"allowedTemplates"`
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	// Percentage of the template cost which is counted for hibernated clusters. Hibernated clusters
+	// are counted at the full cost if not specified
+	HibernatedCostPercentage *int `json:"hibernatedCostPercentage,omitempty"`
+	// +optional
+	// Limits of the individual requesters. A limit without users and groups applies to every
+	// requester.
+	RequesterLimits []RequesterLimit `json:"requesterLimits,omitempty"`
+	// +optional
+	// Accumulates the hourly cost of the clusters per billing period
+	Billing *Billing `json:"billing,omitempty"`
+	// +optional
+	// Instances which do not fit in the quota are queued instead of rejected. Queued instances
+	// are started by priority and in the order of their creation once the quota allows it.
+	QueueInstances bool `json:"queueInstances,omitempty"`
 }
 
 // ClusterTemplateQuotaStatus defines the observed state of ClusterTemplateQuota
@@ -63,6 +187,22 @@ type ClusterTemplateQuotaStatus struct {
 	// Which instances are in use
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	TemplateInstances []AllowedTemplateStatus `json:"templateInstances"`
+	// Spent budget and instances in use broken down by requester
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Requesters []RequesterStatus `json:"requesters,omitempty"`
+	// Admitted instances which are not counted yet
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Reservations []QuotaReservation `json:"reservations,omitempty"`
+	// Hourly cost accumulated in the current billing period
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Billing *BillingStatus `json:"billing,omitempty"`
+	// Admitted instances which wait for the quota, in the order they are started
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	// +optional
+	Queue []QueuedInstance `json:"queue,omitempty"`
 }
 
 //+kubebuilder:object:root=true
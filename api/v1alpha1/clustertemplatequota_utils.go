@@ -0,0 +1,330 @@
+package v1alpha1
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"golang.org/x/exp/slices"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Reservations of instances which were not created (ie because their admission failed) are
+// released after this time
+var QuotaReservationTimeout = time.Minute
+
+// AppliesTo returns true if the limit applies to the given requester
+func (l *RequesterLimit) AppliesTo(requester string, groups []string) bool {
+	if len(l.Users) == 0 && len(l.Groups) == 0 {
+		return true
+	}
+	if slices.Contains(l.Users, requester) {
+		return true
+	}
+	for _, group := range groups {
+		if slices.Contains(l.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the reserving instance was not created in time
+func (r *QuotaReservation) IsExpired(now time.Time) bool {
+	return r.ReservedAt.Add(QuotaReservationTimeout).Before(now)
+}
+
+// Returns the budget and the number of instances of the template which are reserved
+func getReservedUsage(reservations []QuotaReservation, template string) (int, int) {
+	budget := 0
+	count := 0
+	for _, reservation := range reservations {
+		budget += reservation.Cost
+		if reservation.ClusterTemplate == template {
+			count++
+		}
+	}
+	return budget, count
+}
+
+// Returns the reservations without the reservation of the given instance
+func removeReservation(
+	reservations []QuotaReservation,
+	namespace string,
+	name string,
+) ([]QuotaReservation, bool) {
+	kept := []QuotaReservation{}
+	for _, reservation := range reservations {
+		if reservation.Namespace != namespace || reservation.Name != name {
+			kept = append(kept, reservation)
+		}
+	}
+	return kept, len(kept) != len(reservations)
+}
+
+// Adds the instance to the queue behind the instances with the same or higher priority
+func enqueueInstance(queue []QueuedInstance, instance QueuedInstance) []QueuedInstance {
+	index := len(queue)
+	for i, queued := range queue {
+		if queued.Priority < instance.Priority {
+			index = i
+			break
+		}
+	}
+	return slices.Insert(queue, index, instance)
+}
+
+// Returns the queue without the given instance
+func removeQueuedInstance(
+	queue []QueuedInstance,
+	namespace string,
+	name string,
+) ([]QueuedInstance, bool) {
+	kept := []QueuedInstance{}
+	for _, queued := range queue {
+		if queued.Namespace != namespace || queued.Name != name {
+			kept = append(kept, queued)
+		}
+	}
+	return kept, len(kept) != len(queue)
+}
+
+// IsQueued returns true if the instance waits in the queue of the quota
+func (q *ClusterTemplateQuota) IsQueued(namespace string, name string) bool {
+	for _, queued := range q.Status.Queue {
+		if queued.Namespace == namespace && queued.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpired returns true if the queued instance was not created in time
+func (q *QueuedInstance) IsExpired(now time.Time) bool {
+	return q.QueuedAt.Add(QuotaReservationTimeout).Before(now)
+}
+
+// ReleaseQuotaReservations removes the reservations of the instance from all quotas and the
+// instance from their queues
+func (i *ClusterTemplateInstance) ReleaseQuotaReservations(
+	ctx context.Context,
+	k8sClient client.Client,
+) error {
+	quotas := &ClusterTemplateQuotaList{}
+	if err := k8sClient.List(ctx, quotas, client.InNamespace(i.Namespace)); err != nil {
+		return err
+	}
+	for index := range quotas.Items {
+		quota := &quotas.Items[index]
+		if err := updateQuotaStatus(ctx, k8sClient, quota, func() bool {
+			reservations, reserved := removeReservation(quota.Status.Reservations, i.Namespace, i.Name)
+			queue, queued := removeQueuedInstance(quota.Status.Queue, i.Namespace, i.Name)
+			quota.Status.Reservations = reservations
+			quota.Status.Queue = queue
+			return reserved || queued
+		}); err != nil {
+			return err
+		}
+	}
+
+	return i.releaseClusterQuotaReservations(ctx, k8sClient)
+}
+
+// Removes the reservations of the instance from all cluster quotas
+func (i *ClusterTemplateInstance) releaseClusterQuotaReservations(
+	ctx context.Context,
+	k8sClient client.Client,
+) error {
+	clusterQuotas := &ClusterTemplateClusterQuotaList{}
+	if err := k8sClient.List(ctx, clusterQuotas); err != nil {
+		return err
+	}
+	for index := range clusterQuotas.Items {
+		quota := &clusterQuotas.Items[index]
+		if err := updateQuotaStatus(ctx, k8sClient, quota, func() bool {
+			reservations, found := removeReservation(quota.Status.Reservations, i.Namespace, i.Name)
+			quota.Status.Reservations = reservations
+			return found
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Updates the status of a fresh copy of the quota, the update is skipped if the function doesn't
+// change the status. Conflicting updates are retried.
+func updateQuotaStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	quota client.Object,
+	update func() bool,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(quota), quota); err != nil {
+			// Removed quota has nothing to update
+			return client.IgnoreNotFound(err)
+		}
+		if !update() {
+			return nil
+		}
+		return k8sClient.Status().Update(ctx, quota)
+	})
+}
+
+// Start returns the start of the billing period which contains the given time
+func (p BillingPeriod) Start(now time.Time) time.Time {
+	now = now.UTC()
+	if p == WeeklyBillingPeriod {
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// End returns the start of the billing period which follows the period of the given time
+func (p BillingPeriod) End(now time.Time) time.Time {
+	start := p.Start(now)
+	if p == WeeklyBillingPeriod {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// BilledCost returns the cost of every started hour of a cluster which existed from start until
+// end, counted since the start of the billing period
+func BilledCost(costPerHour int, start time.Time, periodStart time.Time, end time.Time) int {
+	if start.Before(periodStart) {
+		start = periodStart
+	}
+	if !end.After(start) {
+		return 0
+	}
+	hours := int(math.Ceil(end.Sub(start).Hours()))
+	return costPerHour * hours
+}
+
+// GetCostPerHour returns the hourly cost of the template the instance was created from
+func (i *ClusterTemplateInstance) GetCostPerHour(templates []ClusterTemplate) int {
+	if snapshot := i.Status.TemplateSnapshot; snapshot != nil {
+		if snapshot.CostPerHour != nil {
+			return *snapshot.CostPerHour
+		}
+		return 0
+	}
+	for _, template := range templates {
+		if costPerHour := template.GetEffectiveSpec().CostPerHour; template.Name == i.Spec.ClusterTemplateRef &&
+			costPerHour != nil {
+			return *costPerHour
+		}
+	}
+	return 0
+}
+
+// GetBillingSpent returns the hourly cost accumulated in the current billing period
+func (q *ClusterTemplateQuota) GetBillingSpent(now time.Time) int {
+	if q.Spec.Billing == nil || q.Status.Billing == nil {
+		return 0
+	}
+	if !q.Status.Billing.PeriodStart.Time.Equal(q.Spec.Billing.Period.Start(now)) {
+		return 0
+	}
+	return q.Status.Billing.Spent
+}
+
+// GetBillingStatus returns the billing status of the current period, the accumulated cost is reset
+// when a new period starts
+func (q *ClusterTemplateQuota) GetBillingStatus(now time.Time) *BillingStatus {
+	periodStart := q.Spec.Billing.Period.Start(now)
+	if q.Status.Billing != nil && q.Status.Billing.PeriodStart.Time.Equal(periodStart) {
+		return q.Status.Billing.DeepCopy()
+	}
+	return &BillingStatus{
+		PeriodStart:      metav1.NewTime(periodStart),
+		SettledInstances: []types.UID{},
+	}
+}
+
+// SettleBilling adds the hourly cost of the removed instance to the quotas with a billing period
+func (i *ClusterTemplateInstance) SettleBilling(ctx context.Context, k8sClient client.Client) error {
+	quotas := &ClusterTemplateQuotaList{}
+	if err := k8sClient.List(ctx, quotas, client.InNamespace(i.Namespace)); err != nil {
+		return err
+	}
+	templates := &ClusterTemplateList{}
+	if err := k8sClient.List(ctx, templates); err != nil {
+		return err
+	}
+
+	start := i.GetProvisioningStart()
+	if start == nil {
+		// Instances which were never provisioned are not billed
+		return nil
+	}
+	end := time.Now()
+	if i.DeletionTimestamp != nil {
+		end = i.DeletionTimestamp.Time
+	}
+	costPerHour := i.GetCostPerHour(templates.Items)
+	for index := range quotas.Items {
+		quota := &quotas.Items[index]
+		if quota.Spec.Billing == nil || !quota.AllowsTemplate(i.Spec.ClusterTemplateRef) {
+			continue
+		}
+		if err := updateQuotaStatus(ctx, k8sClient, quota, func() bool {
+			billing := quota.GetBillingStatus(time.Now())
+			if slices.Contains(billing.SettledInstances, i.UID) {
+				return false
+			}
+			billing.Settled += BilledCost(costPerHour, start.Time, billing.PeriodStart.Time, end)
+			billing.SettledInstances = append(billing.SettledInstances, i.UID)
+			quota.Status.Billing = billing
+			return true
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetProvisioningStart returns the time when the provisioning of the instance started, nil if the
+// instance was never provisioned (ie it was removed while queued or waiting for approval). The
+// provisioning starts with the template snapshot and is followed by the provisioning phases, the
+// earliest of them is used as the snapshot is retaken when the instance is rebased.
+func (i *ClusterTemplateInstance) GetProvisioningStart() *metav1.Time {
+	snapshot := i.Status.TemplateSnapshot
+	if snapshot == nil {
+		return nil
+	}
+	var start *metav1.Time
+	if !snapshot.Timestamp.IsZero() {
+		start = snapshot.Timestamp.DeepCopy()
+	}
+	for _, transition := range i.Status.History {
+		switch transition.Phase {
+		case PendingPhase, QueuedPhase, PendingApprovalPhase:
+			continue
+		}
+		if start == nil || transition.Timestamp.Before(start) {
+			start = transition.Timestamp.DeepCopy()
+		}
+	}
+	// Snapshot taken over from a pooled instance precedes the creation of the instance
+	if start == nil || start.Before(&i.CreationTimestamp) {
+		start = i.CreationTimestamp.DeepCopy()
+	}
+	return start
+}
+
+// AllowsTemplate returns true if the template is allowed by the quota
+func (q *ClusterTemplateQuota) AllowsTemplate(name string) bool {
+	for _, allowedTemplate := range q.Spec.AllowedTemplates {
+		if allowedTemplate.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplateQuota utils", func() {
+	It("BillingPeriod Start and End", func() {
+		// Wednesday
+		now := time.Date(2023, 3, 15, 10, 30, 0, 0, time.UTC)
+
+		Expect(WeeklyBillingPeriod.Start(now)).Should(Equal(time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC)))
+		Expect(WeeklyBillingPeriod.End(now)).Should(Equal(time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)))
+		Expect(MonthlyBillingPeriod.Start(now)).Should(Equal(time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)))
+		Expect(MonthlyBillingPeriod.End(now)).Should(Equal(time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC)))
+
+		// Sunday belongs to the week which started on Monday
+		sunday := time.Date(2023, 3, 19, 23, 0, 0, 0, time.UTC)
+		Expect(WeeklyBillingPeriod.Start(sunday)).Should(Equal(time.Date(2023, 3, 13, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("BilledCost", func() {
+		periodStart := time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		// Every started hour is billed
+		created := time.Date(2023, 3, 10, 8, 0, 0, 0, time.UTC)
+		Expect(BilledCost(3, created, periodStart, created.Add(90*time.Minute))).Should(Equal(6))
+		Expect(BilledCost(3, created, periodStart, created)).Should(Equal(0))
+
+		// Only the hours of the current period are billed
+		created = time.Date(2023, 2, 28, 20, 0, 0, 0, time.UTC)
+		Expect(BilledCost(3, created, periodStart, periodStart.Add(2*time.Hour))).Should(Equal(6))
+	})
+
+	It("SettleBilling", func() {
+		scheme := runtime.NewScheme()
+		err := AddToScheme(scheme)
+		Expect(err).NotTo(HaveOccurred())
+		ctq := &ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "bar",
+				Namespace: "foo",
+			},
+			Spec: ClusterTemplateQuotaSpec{
+				AllowedTemplates: []AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				Billing: &Billing{
+					Period: MonthlyBillingPeriod,
+				},
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme, ctq)
+
+		costPerHour := 2
+		now := time.Now()
+		created := metav1.NewTime(now.Add(-90 * time.Minute))
+		deleted := metav1.NewTime(now)
+		if created.Time.Before(MonthlyBillingPeriod.Start(now)) {
+			created = metav1.NewTime(MonthlyBillingPeriod.Start(now))
+		}
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "foo-instance",
+				Namespace:         "foo",
+				UID:               "foo-uid",
+				CreationTimestamp: created,
+				DeletionTimestamp: &deleted,
+			},
+			Spec: ClusterTemplateInstanceSpec{
+				ClusterTemplateRef: "foo-tmp",
+			},
+			Status: ClusterTemplateInstanceStatus{
+				TemplateSnapshot: &TemplateSnapshot{
+					CostPerHour: &costPerHour,
+				},
+			},
+		}
+		expected := BilledCost(costPerHour, created.Time, MonthlyBillingPeriod.Start(now), deleted.Time)
+
+		err = cti.SettleBilling(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ctq.Status.Billing.Settled).Should(Equal(expected))
+
+		// The instance is settled only once
+		err = cti.SettleBilling(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ctq.Status.Billing.Settled).Should(Equal(expected))
+
+		// Instance which was never provisioned is not billed
+		cti.UID = "bar-uid"
+		cti.Status.TemplateSnapshot = nil
+		err = cti.SettleBilling(ctx, k8sClient)
+		Expect(err).ShouldNot(HaveOccurred())
+		err = k8sClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ctq.Status.Billing.Settled).Should(Equal(expected))
+		Expect(ctq.Status.Billing.SettledInstances).ShouldNot(ContainElement(cti.UID))
+	})
+
+	It("GetProvisioningStart", func() {
+		created := metav1.NewTime(time.Date(2023, 3, 6, 12, 0, 0, 0, time.UTC))
+		cti := &ClusterTemplateInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: created,
+			},
+			Status: ClusterTemplateInstanceStatus{
+				History: []PhaseTransition{
+					{Phase: PendingPhase, Timestamp: created},
+					{Phase: QueuedPhase, Timestamp: created},
+				},
+			},
+		}
+		Expect(cti.GetProvisioningStart()).Should(BeNil())
+
+		// Provisioning starts with the snapshot, phases of a rebase don't move the start
+		started := metav1.NewTime(created.Add(2 * time.Hour))
+		cti.Status.TemplateSnapshot = &TemplateSnapshot{
+			Timestamp: metav1.NewTime(created.Add(5 * time.Hour)),
+		}
+		cti.Status.History = append(cti.Status.History,
+			PhaseTransition{Phase: ClusterInstallingPhase, Timestamp: started},
+			PhaseTransition{Phase: ReadyPhase, Timestamp: metav1.NewTime(created.Add(3 * time.Hour))},
+		)
+		Expect(cti.GetProvisioningStart().Time).Should(Equal(started.Time))
+
+		// Snapshot of a pooled instance precedes the creation
+		cti.Status.History = nil
+		cti.Status.TemplateSnapshot.Timestamp = metav1.NewTime(created.Add(-time.Hour))
+		Expect(cti.GetProvisioningStart().Time).Should(Equal(created.Time))
+	})
+})
@@ -21,6 +21,10 @@ import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 type ConfigSpec struct {
 	// ArgoCd namespace where the ArgoCD instance is running
 	ArgoCDNamespace string `json:"argoCDNamespace,omitempty"`
+	// Namespace of the pooled instances and their secrets, created by the operator. Defaults to
+	// cluster-templates-pool
+	// +optional
+	PoolNamespace string `json:"poolNamespace,omitempty"`
 	// Custom UI image
 	UIImage string `json:"uiImage,omitempty"`
 	// Flag that indicate if UI console plugin should be deployed
@@ -30,6 +34,10 @@ type ConfigSpec struct {
 	// +kubebuilder:validation:Type=string
 	// +kubebuilder:validation:Pattern="^([0-9]+(\\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$"
 	LoginAttemptTimeoutOverride *metav1.Duration `json:"loginAttemptTimeoutOverride,omitempty"`
+	// Networks in CIDR notation which notifications can be delivered to. By default, notifications
+	// are not delivered to loopback, private and link-local addresses
+	// +optional
+	NotificationAllowedNetworks []string `json:"notificationAllowedNetworks,omitempty"`
 }
 
 //+kubebuilder:object:root=true
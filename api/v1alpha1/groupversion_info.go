@@ -60,6 +60,12 @@ var (
 		Version:  "v1",
 	}
 
+	CAPIClusterGVK = schema.GroupVersionResource{
+		Group:    "cluster.x-k8s.io",
+		Resource: "Cluster",
+		Version:  "v1beta1",
+	}
+
 	ConsolePluginGVK = schema.GroupVersionResource{
 		Group:    "console.openshift.io",
 		Resource: "ConsolePlugin",
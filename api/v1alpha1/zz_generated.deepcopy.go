@@ -25,6 +25,7 @@ import (
 	"k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -35,6 +36,16 @@ func (in *AllowedTemplate) DeepCopyInto(out *AllowedTemplate) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.MaxLifetime != nil {
+		in, out := &in.MaxLifetime, &out.MaxLifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(PowerSchedule)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedTemplate.
@@ -62,6 +73,42 @@ func (in *AllowedTemplateStatus) DeepCopy() *AllowedTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Billing) DeepCopyInto(out *Billing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Billing.
+func (in *Billing) DeepCopy() *Billing {
+	if in == nil {
+		return nil
+	}
+	out := new(Billing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BillingStatus) DeepCopyInto(out *BillingStatus) {
+	*out = *in
+	in.PeriodStart.DeepCopyInto(&out.PeriodStart)
+	if in.SettledInstances != nil {
+		in, out := &in.SettledInstances, &out.SettledInstances
+		*out = make([]types.UID, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BillingStatus.
+func (in *BillingStatus) DeepCopy() *BillingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BillingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDefinitionSchema) DeepCopyInto(out *ClusterDefinitionSchema) {
 	*out = *in
@@ -87,6 +134,98 @@ func (in *ClusterDefinitionSchema) DeepCopy() *ClusterDefinitionSchema {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterInfo) DeepCopyInto(out *ClusterInfo) {
+	*out = *in
+	if in.NodePools != nil {
+		in, out := &in.NodePools, &out.NodePools
+		*out = make([]NodePoolInfo, len(*in))
+		copy(*out, *in)
+	}
+	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterInfo.
+func (in *ClusterInfo) DeepCopy() *ClusterInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProviderConfig) DeepCopyInto(out *ClusterProviderConfig) {
+	*out = *in
+	out.KubeconfigSecretRef = in.KubeconfigSecretRef
+	if in.AdminCredentialsSecretRef != nil {
+		in, out := &in.AdminCredentialsSecretRef, &out.AdminCredentialsSecretRef
+		*out = new(ClusterProviderSecretRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProviderConfig.
+func (in *ClusterProviderConfig) DeepCopy() *ClusterProviderConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProviderConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProviderSecretRef) DeepCopyInto(out *ClusterProviderSecretRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProviderSecretRef.
+func (in *ClusterProviderSecretRef) DeepCopy() *ClusterProviderSecretRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProviderSecretRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuotaAllowedTemplate) DeepCopyInto(out *ClusterQuotaAllowedTemplate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaAllowedTemplate.
+func (in *ClusterQuotaAllowedTemplate) DeepCopy() *ClusterQuotaAllowedTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuotaAllowedTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterQuotaNamespaceStatus) DeepCopyInto(out *ClusterQuotaNamespaceStatus) {
+	*out = *in
+	if in.TemplateInstances != nil {
+		in, out := &in.TemplateInstances, &out.TemplateInstances
+		*out = make([]AllowedTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterQuotaNamespaceStatus.
+func (in *ClusterQuotaNamespaceStatus) DeepCopy() *ClusterQuotaNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterQuotaNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSetupSchema) DeepCopyInto(out *ClusterSetupSchema) {
 	*out = *in
@@ -155,7 +294,7 @@ func (in *ClusterTemplate) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
+func (in *ClusterTemplateClusterQuota) DeepCopyInto(out *ClusterTemplateClusterQuota) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -163,18 +302,18 @@ func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstance.
-func (in *ClusterTemplateInstance) DeepCopy() *ClusterTemplateInstance {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateClusterQuota.
+func (in *ClusterTemplateClusterQuota) DeepCopy() *ClusterTemplateClusterQuota {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstance)
+	out := new(ClusterTemplateClusterQuota)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateClusterQuota) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -182,31 +321,31 @@ func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceList) DeepCopyInto(out *ClusterTemplateInstanceList) {
+func (in *ClusterTemplateClusterQuotaList) DeepCopyInto(out *ClusterTemplateClusterQuotaList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateInstance, len(*in))
+		*out = make([]ClusterTemplateClusterQuota, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceList.
-func (in *ClusterTemplateInstanceList) DeepCopy() *ClusterTemplateInstanceList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateClusterQuotaList.
+func (in *ClusterTemplateClusterQuotaList) DeepCopy() *ClusterTemplateClusterQuotaList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceList)
+	out := new(ClusterTemplateClusterQuotaList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateClusterQuotaList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -214,107 +353,81 @@ func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceSpec) DeepCopyInto(out *ClusterTemplateInstanceSpec) {
+func (in *ClusterTemplateClusterQuotaSpec) DeepCopyInto(out *ClusterTemplateClusterQuotaSpec) {
 	*out = *in
-	if in.KubeconfigSecretRef != nil {
-		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
-		*out = new(string)
-		**out = **in
-	}
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make([]Parameter, len(*in))
+	in.NamespaceSelector.DeepCopyInto(&out.NamespaceSelector)
+	if in.AllowedTemplates != nil {
+		in, out := &in.AllowedTemplates, &out.AllowedTemplates
+		*out = make([]ClusterQuotaAllowedTemplate, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSpec.
-func (in *ClusterTemplateInstanceSpec) DeepCopy() *ClusterTemplateInstanceSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateClusterQuotaSpec.
+func (in *ClusterTemplateClusterQuotaSpec) DeepCopy() *ClusterTemplateClusterQuotaSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceSpec)
+	out := new(ClusterTemplateClusterQuotaSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateInstanceStatus) DeepCopyInto(out *ClusterTemplateInstanceStatus) {
+func (in *ClusterTemplateClusterQuotaStatus) DeepCopyInto(out *ClusterTemplateClusterQuotaStatus) {
 	*out = *in
-	if in.AdminPassword != nil {
-		in, out := &in.AdminPassword, &out.AdminPassword
-		*out = new(v1.LocalObjectReference)
-		**out = **in
-	}
-	if in.Kubeconfig != nil {
-		in, out := &in.Kubeconfig, &out.Kubeconfig
-		*out = new(v1.LocalObjectReference)
-		**out = **in
+	if in.TemplateInstances != nil {
+		in, out := &in.TemplateInstances, &out.TemplateInstances
+		*out = make([]AllowedTemplateStatus, len(*in))
+		copy(*out, *in)
 	}
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]metav1.Condition, len(*in))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]ClusterQuotaNamespaceStatus, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = new([]ClusterSetupStatus)
-		if **in != nil {
-			in, out := *in, *out
-			*out = make([]ClusterSetupStatus, len(*in))
-			copy(*out, *in)
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make([]QuotaReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.ClusterSetupSecrets != nil {
-		in, out := &in.ClusterSetupSecrets, &out.ClusterSetupSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
-	out.ManagedCluster = in.ManagedCluster
-	if in.FirstLoginAttempt != nil {
-		in, out := &in.FirstLoginAttempt, &out.FirstLoginAttempt
-		*out = (*in).DeepCopy()
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceStatus.
-func (in *ClusterTemplateInstanceStatus) DeepCopy() *ClusterTemplateInstanceStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateClusterQuotaStatus.
+func (in *ClusterTemplateClusterQuotaStatus) DeepCopy() *ClusterTemplateClusterQuotaStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateInstanceStatus)
+	out := new(ClusterTemplateClusterQuotaStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+func (in *ClusterTemplateInstance) DeepCopyInto(out *ClusterTemplateInstance) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplate, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
-func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstance.
+func (in *ClusterTemplateInstance) DeepCopy() *ClusterTemplateInstance {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateList)
+	out := new(ClusterTemplateInstance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateInstance) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -322,41 +435,57 @@ func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateParams) DeepCopyInto(out *ClusterTemplateParams) {
+func (in *ClusterTemplateInstanceApproval) DeepCopyInto(out *ClusterTemplateInstanceApproval) {
 	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateParams.
-func (in *ClusterTemplateParams) DeepCopy() *ClusterTemplateParams {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceApproval.
+func (in *ClusterTemplateInstanceApproval) DeepCopy() *ClusterTemplateInstanceApproval {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateParams)
+	out := new(ClusterTemplateInstanceApproval)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateInstanceApproval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuota) DeepCopyInto(out *ClusterTemplateQuota) {
+func (in *ClusterTemplateInstanceApprovalList) DeepCopyInto(out *ClusterTemplateInstanceApprovalList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateInstanceApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuota.
-func (in *ClusterTemplateQuota) DeepCopy() *ClusterTemplateQuota {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceApprovalList.
+func (in *ClusterTemplateInstanceApprovalList) DeepCopy() *ClusterTemplateInstanceApprovalList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuota)
+	out := new(ClusterTemplateInstanceApprovalList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateInstanceApprovalList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -364,31 +493,46 @@ func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaList) DeepCopyInto(out *ClusterTemplateQuotaList) {
+func (in *ClusterTemplateInstanceApprovalSpec) DeepCopyInto(out *ClusterTemplateInstanceApprovalSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceApprovalSpec.
+func (in *ClusterTemplateInstanceApprovalSpec) DeepCopy() *ClusterTemplateInstanceApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateInstanceApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateInstanceList) DeepCopyInto(out *ClusterTemplateInstanceList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateQuota, len(*in))
+		*out = make([]ClusterTemplateInstance, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaList.
-func (in *ClusterTemplateQuotaList) DeepCopy() *ClusterTemplateQuotaList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceList.
+func (in *ClusterTemplateInstanceList) DeepCopy() *ClusterTemplateInstanceList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaList)
+	out := new(ClusterTemplateInstanceList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateInstanceList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -396,49 +540,173 @@ func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaSpec) DeepCopyInto(out *ClusterTemplateQuotaSpec) {
+func (in *ClusterTemplateInstanceSpec) DeepCopyInto(out *ClusterTemplateInstanceSpec) {
 	*out = *in
-	if in.AllowedTemplates != nil {
-		in, out := &in.AllowedTemplates, &out.AllowedTemplates
-		*out = make([]AllowedTemplate, len(*in))
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(string)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PowerSchedule != nil {
+		in, out := &in.PowerSchedule, &out.PowerSchedule
+		*out = new(PowerSchedule)
+		**out = **in
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgradeTarget)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaSpec.
-func (in *ClusterTemplateQuotaSpec) DeepCopy() *ClusterTemplateQuotaSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceSpec.
+func (in *ClusterTemplateInstanceSpec) DeepCopy() *ClusterTemplateInstanceSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaSpec)
+	out := new(ClusterTemplateInstanceSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateQuotaStatus) DeepCopyInto(out *ClusterTemplateQuotaStatus) {
+func (in *ClusterTemplateInstanceStatus) DeepCopyInto(out *ClusterTemplateInstanceStatus) {
 	*out = *in
-	if in.TemplateInstances != nil {
-		in, out := &in.TemplateInstances, &out.TemplateInstances
-		*out = make([]AllowedTemplateStatus, len(*in))
+	if in.AdminPassword != nil {
+		in, out := &in.AdminPassword, &out.AdminPassword
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Kubeconfig != nil {
+		in, out := &in.Kubeconfig, &out.Kubeconfig
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = new([]ClusterSetupStatus)
+		if **in != nil {
+			in, out := *in, *out
+			*out = make([]ClusterSetupStatus, len(*in))
+			copy(*out, *in)
+		}
+	}
+	if in.ClusterSetupSecrets != nil {
+		in, out := &in.ClusterSetupSecrets, &out.ClusterSetupSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	out.ManagedCluster = in.ManagedCluster
+	if in.FirstLoginAttempt != nil {
+		in, out := &in.FirstLoginAttempt, &out.FirstLoginAttempt
+		*out = (*in).DeepCopy()
+	}
+	if in.TemplateSnapshot != nil {
+		in, out := &in.TemplateSnapshot, &out.TemplateSnapshot
+		*out = new(TemplateSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NextPowerStateTransition != nil {
+		in, out := &in.NextPowerStateTransition, &out.NextPowerStateTransition
+		*out = new(PowerStateTransition)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LifetimeExtension != nil {
+		in, out := &in.LifetimeExtension, &out.LifetimeExtension
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(InstanceApproval)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]PhaseTransition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ClusterInfo != nil {
+		in, out := &in.ClusterInfo, &out.ClusterInfo
+		*out = new(ClusterInfo)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgradeStatus)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaStatus.
-func (in *ClusterTemplateQuotaStatus) DeepCopy() *ClusterTemplateQuotaStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateInstanceStatus.
+func (in *ClusterTemplateInstanceStatus) DeepCopy() *ClusterTemplateInstanceStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateQuotaStatus)
+	out := new(ClusterTemplateInstanceStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSetup) DeepCopyInto(out *ClusterTemplateSetup) {
+func (in *ClusterTemplateList) DeepCopyInto(out *ClusterTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateList.
+func (in *ClusterTemplateList) DeepCopy() *ClusterTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateNotification) DeepCopyInto(out *ClusterTemplateNotification) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -446,18 +714,18 @@ func (in *ClusterTemplateSetup) DeepCopyInto(out *ClusterTemplateSetup) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetup.
-func (in *ClusterTemplateSetup) DeepCopy() *ClusterTemplateSetup {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateNotification.
+func (in *ClusterTemplateNotification) DeepCopy() *ClusterTemplateNotification {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSetup)
+	out := new(ClusterTemplateNotification)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateSetup) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateNotification) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -465,31 +733,31 @@ func (in *ClusterTemplateSetup) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSetupList) DeepCopyInto(out *ClusterTemplateSetupList) {
+func (in *ClusterTemplateNotificationList) DeepCopyInto(out *ClusterTemplateNotificationList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterTemplateSetup, len(*in))
+		*out = make([]ClusterTemplateNotification, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupList.
-func (in *ClusterTemplateSetupList) DeepCopy() *ClusterTemplateSetupList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateNotificationList.
+func (in *ClusterTemplateNotificationList) DeepCopy() *ClusterTemplateNotificationList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSetupList)
+	out := new(ClusterTemplateNotificationList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterTemplateSetupList) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplateNotificationList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -497,115 +765,113 @@ func (in *ClusterTemplateSetupList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSetupSpec) DeepCopyInto(out *ClusterTemplateSetupSpec) {
+func (in *ClusterTemplateNotificationSpec) DeepCopyInto(out *ClusterTemplateNotificationSpec) {
 	*out = *in
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]string, len(*in))
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExpiringBefore != nil {
+		in, out := &in.ExpiringBefore, &out.ExpiringBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookNotification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Email != nil {
+		in, out := &in.Email, &out.Email
+		*out = new(EmailNotification)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupSpec.
-func (in *ClusterTemplateSetupSpec) DeepCopy() *ClusterTemplateSetupSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateNotificationSpec.
+func (in *ClusterTemplateNotificationSpec) DeepCopy() *ClusterTemplateNotificationSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSetupSpec)
+	out := new(ClusterTemplateNotificationSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSetupStatus) DeepCopyInto(out *ClusterTemplateSetupStatus) {
+func (in *ClusterTemplateNotificationStatus) DeepCopyInto(out *ClusterTemplateNotificationStatus) {
 	*out = *in
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]ClusterSetupSchema, len(*in))
+	if in.Deliveries != nil {
+		in, out := &in.Deliveries, &out.Deliveries
+		*out = make([]NotificationDelivery, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupStatus.
-func (in *ClusterTemplateSetupStatus) DeepCopy() *ClusterTemplateSetupStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateNotificationStatus.
+func (in *ClusterTemplateNotificationStatus) DeepCopy() *ClusterTemplateNotificationStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSetupStatus)
+	out := new(ClusterTemplateNotificationStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+func (in *ClusterTemplateParameter) DeepCopyInto(out *ClusterTemplateParameter) {
 	*out = *in
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Cost != nil {
-		in, out := &in.Cost, &out.Cost
-		*out = new(int)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
-func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateParameter.
+func (in *ClusterTemplateParameter) DeepCopy() *ClusterTemplateParameter {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateSpec)
+	out := new(ClusterTemplateParameter)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+func (in *ClusterTemplateParams) DeepCopyInto(out *ClusterTemplateParams) {
 	*out = *in
-	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
-	if in.ClusterSetup != nil {
-		in, out := &in.ClusterSetup, &out.ClusterSetup
-		*out = make([]ClusterSetupSchema, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
-func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateParams.
+func (in *ClusterTemplateParams) DeepCopy() *ClusterTemplateParams {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterTemplateStatus)
+	out := new(ClusterTemplateParams)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Config) DeepCopyInto(out *Config) {
+func (in *ClusterTemplatePool) DeepCopyInto(out *ClusterTemplatePool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
-func (in *Config) DeepCopy() *Config {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplatePool.
+func (in *ClusterTemplatePool) DeepCopy() *ClusterTemplatePool {
 	if in == nil {
 		return nil
 	}
-	out := new(Config)
+	out := new(ClusterTemplatePool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Config) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplatePool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -613,31 +879,31 @@ func (in *Config) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigList) DeepCopyInto(out *ConfigList) {
+func (in *ClusterTemplatePoolList) DeepCopyInto(out *ClusterTemplatePoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Config, len(*in))
+		*out = make([]ClusterTemplatePool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigList.
-func (in *ConfigList) DeepCopy() *ConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplatePoolList.
+func (in *ClusterTemplatePoolList) DeepCopy() *ClusterTemplatePoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigList)
+	out := new(ClusterTemplatePoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ConfigList) DeepCopyObject() runtime.Object {
+func (in *ClusterTemplatePoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -645,36 +911,905 @@ func (in *ConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
+func (in *ClusterTemplatePoolSpec) DeepCopyInto(out *ClusterTemplatePoolSpec) {
 	*out = *in
-	if in.LoginAttemptTimeoutOverride != nil {
-		in, out := &in.LoginAttemptTimeoutOverride, &out.LoginAttemptTimeoutOverride
-		*out = new(metav1.Duration)
-		**out = **in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
-func (in *ConfigSpec) DeepCopy() *ConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplatePoolSpec.
+func (in *ClusterTemplatePoolSpec) DeepCopy() *ClusterTemplatePoolSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ConfigSpec)
+	out := new(ClusterTemplatePoolSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Parameter) DeepCopyInto(out *Parameter) {
+func (in *ClusterTemplatePoolStatus) DeepCopyInto(out *ClusterTemplatePoolStatus) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
-func (in *Parameter) DeepCopy() *Parameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplatePoolStatus.
+func (in *ClusterTemplatePoolStatus) DeepCopy() *ClusterTemplatePoolStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Parameter)
+	out := new(ClusterTemplatePoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuota) DeepCopyInto(out *ClusterTemplateQuota) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuota.
+func (in *ClusterTemplateQuota) DeepCopy() *ClusterTemplateQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateQuota) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaList) DeepCopyInto(out *ClusterTemplateQuotaList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateQuota, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaList.
+func (in *ClusterTemplateQuotaList) DeepCopy() *ClusterTemplateQuotaList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateQuotaList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaSpec) DeepCopyInto(out *ClusterTemplateQuotaSpec) {
+	*out = *in
+	if in.AllowedTemplates != nil {
+		in, out := &in.AllowedTemplates, &out.AllowedTemplates
+		*out = make([]AllowedTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.HibernatedCostPercentage != nil {
+		in, out := &in.HibernatedCostPercentage, &out.HibernatedCostPercentage
+		*out = new(int)
+		**out = **in
+	}
+	if in.RequesterLimits != nil {
+		in, out := &in.RequesterLimits, &out.RequesterLimits
+		*out = make([]RequesterLimit, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Billing != nil {
+		in, out := &in.Billing, &out.Billing
+		*out = new(Billing)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaSpec.
+func (in *ClusterTemplateQuotaSpec) DeepCopy() *ClusterTemplateQuotaSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateQuotaStatus) DeepCopyInto(out *ClusterTemplateQuotaStatus) {
+	*out = *in
+	if in.TemplateInstances != nil {
+		in, out := &in.TemplateInstances, &out.TemplateInstances
+		*out = make([]AllowedTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Requesters != nil {
+		in, out := &in.Requesters, &out.Requesters
+		*out = make([]RequesterStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Reservations != nil {
+		in, out := &in.Reservations, &out.Reservations
+		*out = make([]QuotaReservation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Billing != nil {
+		in, out := &in.Billing, &out.Billing
+		*out = new(BillingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Queue != nil {
+		in, out := &in.Queue, &out.Queue
+		*out = make([]QueuedInstance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateQuotaStatus.
+func (in *ClusterTemplateQuotaStatus) DeepCopy() *ClusterTemplateQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSetup) DeepCopyInto(out *ClusterTemplateSetup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetup.
+func (in *ClusterTemplateSetup) DeepCopy() *ClusterTemplateSetup {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSetup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateSetup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSetupList) DeepCopyInto(out *ClusterTemplateSetupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterTemplateSetup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupList.
+func (in *ClusterTemplateSetupList) DeepCopy() *ClusterTemplateSetupList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSetupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterTemplateSetupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSetupSpec) DeepCopyInto(out *ClusterTemplateSetupSpec) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupSpec.
+func (in *ClusterTemplateSetupSpec) DeepCopy() *ClusterTemplateSetupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSetupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSetupStatus) DeepCopyInto(out *ClusterTemplateSetupStatus) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetupSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSetupStatus.
+func (in *ClusterTemplateSetupStatus) DeepCopy() *ClusterTemplateSetupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSetupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateSpec) DeepCopyInto(out *ClusterTemplateSpec) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(int)
+		**out = **in
+	}
+	if in.CostPerHour != nil {
+		in, out := &in.CostPerHour, &out.CostPerHour
+		*out = new(int)
+		**out = **in
+	}
+	if in.ClusterProvider != nil {
+		in, out := &in.ClusterProvider, &out.ClusterProvider
+		*out = new(ClusterProviderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]ClusterTemplateVersion, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ClusterTemplateParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Upgrades != nil {
+		in, out := &in.Upgrades, &out.Upgrades
+		*out = new(ClusterTemplateUpgrades)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateSpec.
+func (in *ClusterTemplateSpec) DeepCopy() *ClusterTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateStatus) DeepCopyInto(out *ClusterTemplateStatus) {
+	*out = *in
+	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetupSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]ClusterTemplateVersionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EffectiveSpec != nil {
+		in, out := &in.EffectiveSpec, &out.EffectiveSpec
+		*out = new(ClusterTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateStatus.
+func (in *ClusterTemplateStatus) DeepCopy() *ClusterTemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateUpgradeVersion) DeepCopyInto(out *ClusterTemplateUpgradeVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateUpgradeVersion.
+func (in *ClusterTemplateUpgradeVersion) DeepCopy() *ClusterTemplateUpgradeVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateUpgradeVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateUpgrades) DeepCopyInto(out *ClusterTemplateUpgrades) {
+	*out = *in
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]ClusterTemplateUpgradeVersion, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateUpgrades.
+func (in *ClusterTemplateUpgrades) DeepCopy() *ClusterTemplateUpgrades {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateUpgrades)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateVersion) DeepCopyInto(out *ClusterTemplateVersion) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateVersion.
+func (in *ClusterTemplateVersion) DeepCopy() *ClusterTemplateVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterTemplateVersionStatus) DeepCopyInto(out *ClusterTemplateVersionStatus) {
+	*out = *in
+	in.ClusterDefinition.DeepCopyInto(&out.ClusterDefinition)
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]ClusterSetupSchema, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterTemplateVersionStatus.
+func (in *ClusterTemplateVersionStatus) DeepCopy() *ClusterTemplateVersionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterTemplateVersionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeStatus) DeepCopyInto(out *ClusterUpgradeStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeStatus.
+func (in *ClusterUpgradeStatus) DeepCopy() *ClusterUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeTarget) DeepCopyInto(out *ClusterUpgradeTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeTarget.
+func (in *ClusterUpgradeTarget) DeepCopy() *ClusterUpgradeTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Config) DeepCopyInto(out *Config) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Config.
+func (in *Config) DeepCopy() *Config {
+	if in == nil {
+		return nil
+	}
+	out := new(Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Config) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigList) DeepCopyInto(out *ConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Config, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigList.
+func (in *ConfigList) DeepCopy() *ConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigSpec) DeepCopyInto(out *ConfigSpec) {
+	*out = *in
+	if in.LoginAttemptTimeoutOverride != nil {
+		in, out := &in.LoginAttemptTimeoutOverride, &out.LoginAttemptTimeoutOverride
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.NotificationAllowedNetworks != nil {
+		in, out := &in.NotificationAllowedNetworks, &out.NotificationAllowedNetworks
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigSpec.
+func (in *ConfigSpec) DeepCopy() *ConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EmailNotification) DeepCopyInto(out *EmailNotification) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EmailNotification.
+func (in *EmailNotification) DeepCopy() *EmailNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(EmailNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceApproval) DeepCopyInto(out *InstanceApproval) {
+	*out = *in
+	in.ApprovedAt.DeepCopyInto(&out.ApprovedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceApproval.
+func (in *InstanceApproval) DeepCopy() *InstanceApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodePoolInfo) DeepCopyInto(out *NodePoolInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodePoolInfo.
+func (in *NodePoolInfo) DeepCopy() *NodePoolInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(NodePoolInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationDelivery) DeepCopyInto(out *NotificationDelivery) {
+	*out = *in
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	in.CreatedAt.DeepCopyInto(&out.CreatedAt)
+	if in.LastAttempt != nil {
+		in, out := &in.LastAttempt, &out.LastAttempt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationDelivery.
+func (in *NotificationDelivery) DeepCopy() *NotificationDelivery {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationDelivery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Parameter) DeepCopyInto(out *Parameter) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ParameterValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
+func (in *Parameter) DeepCopy() *Parameter {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ParameterValueSource) DeepCopyInto(out *ParameterValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ParameterValueSource.
+func (in *ParameterValueSource) DeepCopy() *ParameterValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ParameterValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhaseTransition) DeepCopyInto(out *PhaseTransition) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhaseTransition.
+func (in *PhaseTransition) DeepCopy() *PhaseTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PhaseTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerSchedule) DeepCopyInto(out *PowerSchedule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerSchedule.
+func (in *PowerSchedule) DeepCopy() *PowerSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PowerStateTransition) DeepCopyInto(out *PowerStateTransition) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PowerStateTransition.
+func (in *PowerStateTransition) DeepCopy() *PowerStateTransition {
+	if in == nil {
+		return nil
+	}
+	out := new(PowerStateTransition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueuedInstance) DeepCopyInto(out *QueuedInstance) {
+	*out = *in
+	in.QueuedAt.DeepCopyInto(&out.QueuedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueuedInstance.
+func (in *QueuedInstance) DeepCopy() *QueuedInstance {
+	if in == nil {
+		return nil
+	}
+	out := new(QueuedInstance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuotaReservation) DeepCopyInto(out *QuotaReservation) {
+	*out = *in
+	in.ReservedAt.DeepCopyInto(&out.ReservedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuotaReservation.
+func (in *QuotaReservation) DeepCopy() *QuotaReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(QuotaReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequesterLimit) DeepCopyInto(out *RequesterLimit) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedTemplates != nil {
+		in, out := &in.AllowedTemplates, &out.AllowedTemplates
+		*out = make([]RequesterTemplateLimit, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequesterLimit.
+func (in *RequesterLimit) DeepCopy() *RequesterLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RequesterLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequesterStatus) DeepCopyInto(out *RequesterStatus) {
+	*out = *in
+	if in.TemplateInstances != nil {
+		in, out := &in.TemplateInstances, &out.TemplateInstances
+		*out = make([]AllowedTemplateStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequesterStatus.
+func (in *RequesterStatus) DeepCopy() *RequesterStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RequesterStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequesterTemplateLimit) DeepCopyInto(out *RequesterTemplateLimit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequesterTemplateLimit.
+func (in *RequesterTemplateLimit) DeepCopy() *RequesterTemplateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RequesterTemplateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TemplateSnapshot) DeepCopyInto(out *TemplateSnapshot) {
+	*out = *in
+	if in.ClusterSetup != nil {
+		in, out := &in.ClusterSetup, &out.ClusterSetup
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cost != nil {
+		in, out := &in.Cost, &out.Cost
+		*out = new(int)
+		**out = **in
+	}
+	if in.CostPerHour != nil {
+		in, out := &in.CostPerHour, &out.CostPerHour
+		*out = new(int)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Revisions != nil {
+		in, out := &in.Revisions, &out.Revisions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]ClusterTemplateParameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClusterProvider != nil {
+		in, out := &in.ClusterProvider, &out.ClusterProvider
+		*out = new(ClusterProviderConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TemplateSnapshot.
+func (in *TemplateSnapshot) DeepCopy() *TemplateSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(TemplateSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookNotification) DeepCopyInto(out *WebhookNotification) {
+	*out = *in
+	if in.HMACSecretRef != nil {
+		in, out := &in.HMACSecretRef, &out.HMACSecretRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookNotification.
+func (in *WebhookNotification) DeepCopy() *WebhookNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookNotification)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -56,6 +56,29 @@ func GetApplicationHealth(application *argo.Application, includeResourceHealth b
 	return ApplicationHealthy, "Application is synced"
 }
 
+// IsSyncedWithParameters returns true if the application was compared and synced with the given helm parameters
+func IsSyncedWithParameters(application *argo.Application, params []argo.HelmParameter) bool {
+	if application.Status.Sync.Status != argo.SyncStatusCodeSynced {
+		return false
+	}
+	helm := application.Status.Sync.ComparedTo.Source.Helm
+	for _, param := range params {
+		found := false
+		if helm != nil {
+			for _, comparedParam := range helm.Parameters {
+				if comparedParam.Name == param.Name && comparedParam.Value == param.Value {
+					found = true
+					break
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func getOperationMsg(application *argo.Application) string {
 	if application.Status.OperationState != nil &&
 		application.Status.OperationState.Message != "" {
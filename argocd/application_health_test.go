@@ -135,4 +135,30 @@ var _ = Describe("Application health", func() {
 		Expect(status).Should(Equal(ApplicationHealthy))
 		Expect(msg).Should(Equal("Application is synced"))
 	})
+	It("Synced with parameters", func() {
+		app := &argo.Application{
+			Status: argo.ApplicationStatus{
+				Sync: argo.SyncStatus{
+					Status: argo.SyncStatusCodeSynced,
+					ComparedTo: argo.ComparedTo{
+						Source: argo.ApplicationSource{
+							Helm: &argo.ApplicationSourceHelm{
+								Parameters: []argo.HelmParameter{
+									{
+										Name:  "foo",
+										Value: "bar",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(IsSyncedWithParameters(app, []argo.HelmParameter{{Name: "foo", Value: "bar"}})).Should(BeTrue())
+		Expect(IsSyncedWithParameters(app, []argo.HelmParameter{{Name: "foo", Value: "baz"}})).Should(BeFalse())
+
+		app.Status.Sync.Status = argo.SyncStatusCodeOutOfSync
+		Expect(IsSyncedWithParameters(app, []argo.HelmParameter{{Name: "foo", Value: "bar"}})).Should(BeFalse())
+	})
 })
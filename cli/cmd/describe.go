@@ -90,12 +90,20 @@ func templateToDescription(ct v1alpha1.ClusterTemplate) string {
 	}
 	descriptionResult := markdown.Render(description, 80, 6)
 
+	spec := ct.GetEffectiveSpec()
+	cost := 0
+	if spec.Cost != nil {
+		cost = *spec.Cost
+	}
 	result := fmt.Sprintf(
 		"Name: %s\nDescription:\n\n%s\nCost: %d\n",
 		ct.Name,
 		string(descriptionResult),
-		ct.Spec.Cost,
+		cost,
 	)
+	if ct.Spec.BaseTemplate != "" {
+		result = result + fmt.Sprintf("Base template: %s\n", ct.Spec.BaseTemplate)
+	}
 
 	properties := "Properties:"
 	cdValues := ct.Status.ClusterDefinition.Values
@@ -134,6 +142,15 @@ func templateToDescription(ct v1alpha1.ClusterTemplate) string {
 			}
 		}
 	}
+	for _, version := range spec.Versions {
+		result = result + fmt.Sprintf("Version: %s\n", version.Name)
+		if version.Revision != "" {
+			result = result + fmt.Sprintf("\tRevision: %s\n", version.Revision)
+		}
+		if len(version.ClusterSetup) > 0 {
+			result = result + fmt.Sprintf("\tCluster Setup: %s\n", strings.Join(version.ClusterSetup, ", "))
+		}
+	}
 	if properties == "Properties:" {
 		return result
 	}
@@ -3,11 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/duration"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,25 +59,225 @@ func (sv *InstancesOptions) run(k8sClient client.Client, args []string) error {
 	}
 
 	w := tabwriter.NewWriter(sv.Out, 10, 1, 5, ' ', 0)
-	fs := "%s\t%s\t%s\t%s\t%s\n"
-	fmt.Fprintf(w, fs, "NAME", "PHASE", "REQUESTER", "TEMPLATE", "AGE")
+	fs := "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fs, "NAME", "PHASE", "IN PHASE", "REQUESTER", "TEMPLATE", "VERSION", "PLATFORM", "AGE")
 	for _, cti := range ctis.Items {
-		requester := "-"
-		if cti.Annotations != nil {
-			req, ok := cti.Annotations[v1alpha1.CTIRequesterAnnotation]
-			if ok {
-				requester = req
-			}
-		}
-
 		age := "<unknown>"
 		timestamp := cti.CreationTimestamp
 		if !timestamp.IsZero() {
 			age = duration.HumanDuration(time.Since(timestamp.Time))
 		}
 
-		fmt.Fprintf(w, fs, cti.Name, cti.Status.Phase, requester, cti.Spec.ClusterTemplateRef, age)
+		fmt.Fprintf(
+			w,
+			fs,
+			cti.Name,
+			cti.Status.Phase,
+			getTimeInPhase(cti),
+			getRequester(cti),
+			cti.Spec.ClusterTemplateRef,
+			getClusterVersion(cti.Status.ClusterInfo),
+			getClusterPlatform(cti.Status.ClusterInfo),
+			age,
+		)
 	}
 
 	return w.Flush()
 }
+
+func getRequester(cti v1alpha1.ClusterTemplateInstance) string {
+	if requester, ok := cti.Annotations[v1alpha1.CTIRequesterAnnotation]; ok {
+		return requester
+	}
+	return "-"
+}
+
+// Returns the OpenShift version of the cluster, the Kubernetes version for other clusters
+func getClusterVersion(info *v1alpha1.ClusterInfo) string {
+	if info == nil {
+		return "-"
+	}
+	if info.OpenShiftVersion != "" {
+		return info.OpenShiftVersion
+	}
+	if info.KubernetesVersion != "" {
+		return info.KubernetesVersion
+	}
+	return "-"
+}
+
+func getClusterPlatform(info *v1alpha1.ClusterInfo) string {
+	if info == nil || info.Platform == "" {
+		return "-"
+	}
+	if info.Region != "" {
+		return info.Platform + "/" + info.Region
+	}
+	return info.Platform
+}
+
+// Returns the time since the instance entered its current phase
+func getTimeInPhase(cti v1alpha1.ClusterTemplateInstance) string {
+	durations := cti.GetPhaseDurations(time.Now())
+	if len(durations) == 0 {
+		return "-"
+	}
+	return duration.HumanDuration(durations[len(durations)-1])
+}
+
+type InstanceDescribeOptions struct {
+	configFlags *genericclioptions.ConfigFlags
+	genericclioptions.IOStreams
+	Namespace string
+}
+
+func NewInstanceDescribeOptions(namespace string, streams genericclioptions.IOStreams) *InstanceDescribeOptions {
+	return &InstanceDescribeOptions{
+		configFlags: genericclioptions.NewConfigFlags(true),
+		IOStreams:   streams,
+		Namespace:   namespace,
+	}
+}
+
+func NewCmdInstanceDescribe(
+	k8sClient client.Client,
+	namespace string,
+	streams genericclioptions.IOStreams,
+) *cobra.Command {
+	o := NewInstanceDescribeOptions(namespace, streams)
+	cmd := &cobra.Command{
+		Use:          "instance [instance-name(s)]",
+		Short:        "View cluster template instance(s) details and phase history",
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("at least one instance name is required")
+			}
+			if err := o.run(k8sClient, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	return cmd
+}
+
+func (id *InstanceDescribeOptions) run(k8sClient client.Client, args []string) error {
+	result := ""
+	for index, instanceName := range args {
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		if err := k8sClient.Get(
+			context.TODO(),
+			types.NamespacedName{Namespace: id.Namespace, Name: instanceName},
+			cti,
+		); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return err
+			}
+			result = result + fmt.Sprintf("Cluster template instance '%s' not found\n", instanceName)
+		} else {
+			description, err := instanceToDescription(*cti, time.Now())
+			if err != nil {
+				return err
+			}
+			result = result + description
+		}
+		if index != len(args)-1 {
+			result = result + "\n\n"
+		}
+	}
+	_, err := fmt.Fprintln(id.Out, result)
+	return err
+}
+
+func instanceToDescription(cti v1alpha1.ClusterTemplateInstance, now time.Time) (string, error) {
+	result := fmt.Sprintf(
+		"Name: %s\nTemplate: %s\nRequester: %s\nPhase: %s\nMessage: %s\n",
+		cti.Name,
+		cti.Spec.ClusterTemplateRef,
+		getRequester(cti),
+		cti.Status.Phase,
+		cti.Status.Message,
+	)
+	if info := cti.Status.ClusterInfo; info != nil {
+		clusterInfo, err := clusterInfoToDescription(info)
+		if err != nil {
+			return "", err
+		}
+		result = result + clusterInfo
+	}
+	if len(cti.Status.History) == 0 {
+		return result, nil
+	}
+
+	// The oldest transitions may be removed from the history already
+	if cti.Status.History[0].Phase == v1alpha1.PendingPhase {
+		for _, transition := range cti.Status.History {
+			if transition.Phase == v1alpha1.ReadyPhase {
+				result = result + fmt.Sprintf(
+					"Provisioned in: %s\n",
+					duration.HumanDuration(transition.Timestamp.Sub(cti.Status.History[0].Timestamp.Time)),
+				)
+				break
+			}
+		}
+	}
+
+	durations := cti.GetPhaseDurations(now)
+
+	history := &strings.Builder{}
+	w := tabwriter.NewWriter(history, 10, 1, 5, ' ', 0)
+	fs := "\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fs, "PHASE", "REASON", "STARTED", "DURATION")
+	for index, transition := range cti.Status.History {
+		reason := transition.Reason
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(
+			w,
+			fs,
+			transition.Phase,
+			reason,
+			transition.Timestamp.UTC().Format(time.RFC3339),
+			duration.HumanDuration(durations[index]),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return result + "History:\n" + history.String(), nil
+}
+
+func clusterInfoToDescription(info *v1alpha1.ClusterInfo) (string, error) {
+	controlPlaneType := info.ControlPlaneType
+	if controlPlaneType == "" {
+		controlPlaneType = "-"
+	}
+	result := fmt.Sprintf(
+		"Version: %s\nPlatform: %s\nControl plane: %s\n",
+		getClusterVersion(info),
+		getClusterPlatform(info),
+		controlPlaneType,
+	)
+	if len(info.NodePools) == 0 {
+		return result, nil
+	}
+
+	nodePools := &strings.Builder{}
+	w := tabwriter.NewWriter(nodePools, 10, 1, 5, ' ', 0)
+	fs := "\t%s\t%s\t%d\n"
+	fmt.Fprintf(w, "\t%s\t%s\t%s\n", "NAME", "SIZE", "REPLICAS")
+	for _, nodePool := range info.NodePools {
+		size := nodePool.Size
+		if size == "" {
+			size = "-"
+		}
+		fmt.Fprintf(w, fs, nodePool.Name, size, nodePool.Replicas)
+	}
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return result + "Node pools:\n" + nodePools.String(), nil
+}
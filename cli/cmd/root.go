@@ -37,6 +37,7 @@ func NewCmdRoot(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.AddCommand(NewCmdTemplates(k8sClient, ns, streams))
 	cmd.AddCommand(NewCmdTemplateDescribe(k8sClient, streams))
 	cmd.AddCommand(NewCmdListInstances(k8sClient, ns, streams))
+	cmd.AddCommand(NewCmdInstanceDescribe(k8sClient, ns, streams))
 	cmd.AddCommand(NewCmdInstallOperator(k8sClient, streams))
 	cmd.AddCommand(NewCmdUninstallOperator(k8sClient, streams))
 	return cmd
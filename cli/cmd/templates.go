@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
@@ -59,10 +60,20 @@ func (sv *TemplatesOptions) run(k8sClient client.Client, args []string) error {
 	}
 
 	w := tabwriter.NewWriter(sv.Out, 10, 1, 5, ' ', 0)
-	fsHeader := "%s\t%s\t%s\t%s\n"
-	fs := "%s\t%t\t%s\t%s\n"
-	fmt.Fprintf(w, fsHeader, "NAME", "ALLOWED", "USED/MAX", "COST/BUDGET")
+	fsHeader := "%s\t%s\t%s\t%s\t%s\n"
+	fs := "%s\t%t\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, fsHeader, "NAME", "ALLOWED", "USED/MAX", "COST/BUDGET", "VERSIONS")
 	for _, ct := range cts.Items {
+		spec := ct.GetEffectiveSpec()
+		versions := "-"
+		if len(spec.Versions) > 0 {
+			versionNames := []string{}
+			for _, version := range spec.Versions {
+				versionNames = append(versionNames, version.Name)
+			}
+			versions = strings.Join(versionNames, ",")
+		}
+
 		ctq := ctqs.Items[0]
 		allowed := false
 		max := "-"
@@ -82,14 +93,18 @@ func (sv *TemplatesOptions) run(k8sClient client.Client, args []string) error {
 		}
 
 		if !allowed {
-			fmt.Fprintf(w, fs, ct.Name, allowed, "-", "-")
+			fmt.Fprintf(w, fs, ct.Name, allowed, "-", "-", versions)
 		} else {
 			budget := "-"
 			if ctq.Spec.Budget != 0 {
 				budget = fmt.Sprint(ctq.Spec.Budget)
 			}
+			cost := "-"
+			if spec.Cost != nil {
+				cost = fmt.Sprint(*spec.Cost)
+			}
 
-			fmt.Fprintf(w, fs, ct.Name, allowed, fmt.Sprint(used)+"/"+fmt.Sprint(max), fmt.Sprint(ct.Spec.Cost)+"/"+budget)
+			fmt.Fprintf(w, fs, ct.Name, allowed, fmt.Sprint(used)+"/"+fmt.Sprint(max), cost+"/"+budget, versions)
 		}
 
 	}
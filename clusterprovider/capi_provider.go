@@ -0,0 +1,164 @@
+package clusterprovider
+
+import (
+	"context"
+	"errors"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const CAPIClusterProviderName = "CAPICluster"
+
+func init() {
+	Register(ProviderDefinition{
+		Name:     CAPIClusterProviderName,
+		Resource: newResourceType(v1alpha1.CAPIClusterGVK),
+		Watches: []Watch{
+			{
+				ResourceType: newResourceType(v1alpha1.CAPIClusterGVK),
+				Object:       &capiv1beta1.Cluster{},
+			},
+		},
+		New: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			return CAPIClusterProvider{
+				ClusterName:      resource.Name,
+				ClusterNamespace: resource.Namespace,
+			}
+		},
+	})
+}
+
+type CAPIClusterProvider struct {
+	ClusterName      string
+	ClusterNamespace string
+}
+
+func (c CAPIClusterProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	cluster := &capiv1beta1.Cluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: c.ClusterName, Namespace: c.ClusterNamespace},
+		cluster,
+	); err != nil {
+		return false, "", err
+	}
+
+	for _, conditionType := range []capiv1beta1.ConditionType{
+		capiv1beta1.InfrastructureReadyCondition,
+		capiv1beta1.ControlPlaneReadyCondition,
+	} {
+		condition := getCAPICondition(cluster, conditionType)
+		if condition == nil {
+			return false, "Not available", nil
+		}
+		if condition.Status != corev1.ConditionTrue {
+			msg := condition.Message
+			if msg == "" {
+				msg = condition.Reason
+			}
+			if msg == "" {
+				return false, "Not available", nil
+			}
+			return false, "Not available - " + msg, nil
+		}
+	}
+
+	// CAPI stores the admin kubeconfig of the cluster in the '<cluster-name>-kubeconfig' secret
+	kubeconfigSecret := corev1.Secret{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cluster.Name + "-kubeconfig", Namespace: cluster.Namespace},
+		&kubeconfigSecret,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, "Waiting for kubeconfig secret", nil
+		}
+		return false, "", err
+	}
+
+	kubeconfigBytes, ok := kubeconfigSecret.Data["value"]
+	if !ok {
+		return false, "", errors.New("unexpected kubeconfig format")
+	}
+
+	if err := CreateClusterSecrets(
+		ctx,
+		k8sClient,
+		kubeconfigBytes,
+		[]byte(""),
+		[]byte(""),
+		templateInstance,
+	); err != nil {
+		return false, "", err
+	}
+	return true, "Available", nil
+}
+
+// The platform and the control plane type are the kinds of the infrastructure and control plane
+// resources of the cluster, the MachineDeployments of the cluster are its node pools
+func (c CAPIClusterProvider) GetClusterInfo(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.ClusterInfo, error) {
+	cluster := &capiv1beta1.Cluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: c.ClusterName, Namespace: c.ClusterNamespace},
+		cluster,
+	); err != nil {
+		return nil, err
+	}
+
+	info := &v1alpha1.ClusterInfo{}
+	if cluster.Spec.Topology != nil {
+		info.KubernetesVersion = cluster.Spec.Topology.Version
+	}
+	if cluster.Spec.InfrastructureRef != nil {
+		info.Platform = cluster.Spec.InfrastructureRef.Kind
+	}
+	if cluster.Spec.ControlPlaneRef != nil {
+		info.ControlPlaneType = cluster.Spec.ControlPlaneRef.Kind
+	}
+
+	machineDeployments := &capiv1beta1.MachineDeploymentList{}
+	if err := k8sClient.List(
+		ctx,
+		machineDeployments,
+		client.InNamespace(c.ClusterNamespace),
+		client.MatchingLabels{capiv1beta1.ClusterLabelName: c.ClusterName},
+	); err != nil {
+		return nil, err
+	}
+	for _, machineDeployment := range machineDeployments.Items {
+		if info.KubernetesVersion == "" && machineDeployment.Spec.Template.Spec.Version != nil {
+			info.KubernetesVersion = *machineDeployment.Spec.Template.Spec.Version
+		}
+		info.NodePools = append(info.NodePools, v1alpha1.NodePoolInfo{
+			Name:     machineDeployment.Name,
+			Replicas: machineDeployment.Status.Replicas,
+		})
+	}
+	return info, nil
+}
+
+func getCAPICondition(
+	cluster *capiv1beta1.Cluster,
+	conditionType capiv1beta1.ConditionType,
+) *capiv1beta1.Condition {
+	for i := range cluster.Status.Conditions {
+		if cluster.Status.Conditions[i].Type == conditionType {
+			return &cluster.Status.Conditions[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package clusterprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GenericClusterProvider reads the status of the cluster from an arbitrary resource as configured by
+// the clusterProvider of the template
+type GenericClusterProvider struct {
+	Config    v1alpha1.ClusterProviderConfig
+	Name      string
+	Namespace string
+}
+
+// GetGenericClusterProvider returns the generic provider of the resource which matches the config,
+// nil if the application doesn't contain such resource
+func GetGenericClusterProvider(
+	application argo.Application,
+	config v1alpha1.ClusterProviderConfig,
+) ClusterProvider {
+	for _, obj := range application.Status.Resources {
+		if obj.Group == config.Group && obj.Version == config.Version && obj.Kind == config.Kind {
+			providerLog.Info("Cluster provider: Generic " + config.Kind)
+			return GenericClusterProvider{
+				Config:    config,
+				Name:      obj.Name,
+				Namespace: obj.Namespace,
+			}
+		}
+	}
+	providerLog.Info("Cluster provider: resource not found", "kind", config.Kind)
+	return nil
+}
+
+func (g GenericClusterProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   g.Config.Group,
+		Version: g.Config.Version,
+		Kind:    g.Config.Kind,
+	})
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: g.Name, Namespace: g.Namespace},
+		obj,
+	); err != nil {
+		return false, "", err
+	}
+
+	ready, msg, err := g.isReady(obj)
+	if err != nil || !ready {
+		return false, msg, err
+	}
+
+	kubeconfigSecret, err := g.getSecret(ctx, k8sClient, obj, g.Config.KubeconfigSecretRef)
+	if err != nil {
+		return false, "", err
+	}
+	if kubeconfigSecret == nil {
+		return false, "Waiting for kubeconfig secret", nil
+	}
+	kubeconfigKey := g.Config.KubeconfigSecretRef.Key
+	if kubeconfigKey == "" {
+		kubeconfigKey = "kubeconfig"
+	}
+	kubeconfigBytes, ok := kubeconfigSecret.Data[kubeconfigKey]
+	if !ok {
+		return false, "", errors.New("unexpected kubeconfig format")
+	}
+
+	username := []byte("")
+	password := []byte("")
+	if secretRef := g.Config.AdminCredentialsSecretRef; secretRef != nil {
+		adminSecret, err := g.getSecret(ctx, k8sClient, obj, *secretRef)
+		if err != nil {
+			return false, "", err
+		}
+		if adminSecret == nil {
+			return false, "Waiting for admin credentials secret", nil
+		}
+		passwordKey := secretRef.Key
+		if passwordKey == "" {
+			passwordKey = "password"
+		}
+		password, ok = adminSecret.Data[passwordKey]
+		if !ok {
+			return false, "", errors.New("unexpected admin credentials format")
+		}
+		username = []byte("kubeadmin")
+		if secretRef.UsernameKey != "" {
+			username, ok = adminSecret.Data[secretRef.UsernameKey]
+			if !ok {
+				return false, "", errors.New("unexpected admin credentials format")
+			}
+		}
+	}
+
+	if err := CreateClusterSecrets(
+		ctx,
+		k8sClient,
+		kubeconfigBytes,
+		username,
+		password,
+		templateInstance,
+	); err != nil {
+		return false, "", err
+	}
+	return true, "Available", nil
+}
+
+func (g GenericClusterProvider) isReady(obj *unstructured.Unstructured) (bool, string, error) {
+	if g.Config.ReadyJSONPath != "" {
+		value, err := evaluateJSONPath(obj, g.Config.ReadyJSONPath)
+		if err != nil {
+			return false, "", err
+		}
+		readyValue := g.Config.ReadyValue
+		if readyValue == "" {
+			readyValue = "true"
+		}
+		if value != readyValue {
+			return false, "Not available", nil
+		}
+		return true, "", nil
+	}
+
+	conditions, _, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil {
+		return false, "", err
+	}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["type"] != g.Config.ReadyCondition {
+			continue
+		}
+		if condition["status"] == string(corev1.ConditionTrue) {
+			return true, "", nil
+		}
+		msg, _ := condition["message"].(string)
+		if msg == "" {
+			msg, _ = condition["reason"].(string)
+		}
+		if msg == "" {
+			return false, "Not available", nil
+		}
+		return false, "Not available - " + msg, nil
+	}
+	return false, "Not available", nil
+}
+
+// Returns nil if the secret doesn't exist yet
+func (g GenericClusterProvider) getSecret(
+	ctx context.Context,
+	k8sClient client.Client,
+	obj *unstructured.Unstructured,
+	secretRef v1alpha1.ClusterProviderSecretRef,
+) (*corev1.Secret, error) {
+	name, err := evaluateJSONPath(obj, secretRef.Name)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, nil
+	}
+
+	// Only the secrets next to the resource of the cluster are read
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: name, Namespace: obj.GetNamespace()},
+		secret,
+	); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Fields which don't exist (yet) evaluate to an empty string
+func evaluateJSONPath(obj *unstructured.Unstructured, template string) (string, error) {
+	jp := jsonpath.New("").AllowMissingKeys(true)
+	if err := jp.Parse(template); err != nil {
+		return "", fmt.Errorf("invalid JSONPath %q - %q", template, err)
+	}
+	buf := &bytes.Buffer{}
+	if err := jp.Execute(buf, obj.Object); err != nil {
+		return "", fmt.Errorf("failed to evaluate JSONPath %q - %q", template, err)
+	}
+	return buf.String(), nil
+}
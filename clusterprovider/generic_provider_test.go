@@ -0,0 +1,264 @@
+package clusterprovider
+
+import (
+	"os"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/scheme"
+	kubeClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Generic cluster provider", func() {
+	cti := v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cti",
+			Namespace: "bar",
+		},
+	}
+
+	conditionConfig := v1alpha1.ClusterProviderConfig{
+		Group:          "controlplane.cluster.x-k8s.io",
+		Version:        "v1alpha1",
+		Kind:           "KamajiControlPlane",
+		ReadyCondition: "Ready",
+		KubeconfigSecretRef: v1alpha1.ClusterProviderSecretRef{
+			Name: "{.metadata.name}-admin-kubeconfig",
+			Key:  "admin.conf",
+		},
+	}
+
+	jsonPathConfig := v1alpha1.ClusterProviderConfig{
+		Group:         "infrastructure.cluster.x-k8s.io",
+		Version:       "v1alpha1",
+		Kind:          "VCluster",
+		ReadyJSONPath: "{.status.ready}",
+		KubeconfigSecretRef: v1alpha1.ClusterProviderSecretRef{
+			Name: "vc-{.metadata.name}",
+			Key:  "config",
+		},
+		AdminCredentialsSecretRef: &v1alpha1.ClusterProviderSecretRef{
+			Name:        "{.status.adminSecret}",
+			UsernameKey: "username",
+		},
+	}
+
+	getObject := func(
+		config v1alpha1.ClusterProviderConfig,
+		status map[string]interface{},
+	) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": config.Group + "/" + config.Version,
+			"kind":       config.Kind,
+			"metadata": map[string]interface{}{
+				"name":      "foo",
+				"namespace": "bar",
+			},
+		}}
+		if status != nil {
+			obj.Object["status"] = status
+		}
+		return obj
+	}
+
+	It("Detects the configured resource", func() {
+		app := argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:      "HostedCluster",
+						Version:   "v1beta1",
+						Group:     "hypershift.openshift.io",
+						Name:      "foo",
+						Namespace: "bar",
+					},
+					{
+						Kind:      "KamajiControlPlane",
+						Version:   "v1alpha1",
+						Group:     "controlplane.cluster.x-k8s.io",
+						Name:      "kamaji",
+						Namespace: "bar",
+					},
+				},
+			},
+		}
+		Expect(GetGenericClusterProvider(app, conditionConfig)).Should(Equal(GenericClusterProvider{
+			Config:    conditionConfig,
+			Name:      "kamaji",
+			Namespace: "bar",
+		}))
+		Expect(GetGenericClusterProvider(app, jsonPathConfig)).Should(BeNil())
+
+		provider, err := GetInstanceClusterProvider(app, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider).Should(BeAssignableToTypeOf(HostedClusterProvider{}))
+
+		// The provider is configured only by the template snapshot
+		instance := cti.DeepCopy()
+		instance.Annotations = map[string]string{
+			v1alpha1.ClusterProviderAnnotation: `{"group":"infrastructure.cluster.x-k8s.io",` +
+				`"version":"v1alpha1","kind":"VCluster","readyJSONPath":"{.status.ready}",` +
+				`"kubeconfigSecretRef":{"name":"vc-{.metadata.name}"}}`,
+		}
+		provider, err = GetInstanceClusterProvider(app, *instance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider).Should(BeAssignableToTypeOf(HostedClusterProvider{}))
+
+		instance.Status.TemplateSnapshot = &v1alpha1.TemplateSnapshot{
+			ClusterProvider: &jsonPathConfig,
+		}
+		_, err = GetInstanceClusterProvider(app, *instance)
+		Expect(err).Should(HaveOccurred())
+
+		instance.Status.TemplateSnapshot.ClusterProvider = &conditionConfig
+		provider, err = GetInstanceClusterProvider(app, *instance)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(provider).Should(BeAssignableToTypeOf(GenericClusterProvider{}))
+	})
+
+	It("Returns not ready until the condition is true", func() {
+		provider := GenericClusterProvider{Config: conditionConfig, Name: "foo", Namespace: "bar"}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme)
+		ready, msg, err := provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).Should(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal(""))
+
+		client = fake.NewFakeClientWithScheme(scheme.Scheme, getObject(conditionConfig, nil))
+		ready, msg, err = provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available"))
+
+		client = fake.NewFakeClientWithScheme(scheme.Scheme, getObject(conditionConfig, map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "False", "reason": "foo"},
+			},
+		}))
+		ready, msg, err = provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available - foo"))
+	})
+
+	It("Returns ready once the condition is true and kubeconfig exists", func() {
+		provider := GenericClusterProvider{Config: conditionConfig, Name: "foo", Namespace: "bar"}
+		obj := getObject(conditionConfig, map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Ready", "status": "True"},
+			},
+		})
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, obj)
+		ready, msg, err := provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Waiting for kubeconfig secret"))
+
+		kubeconfigFile, err := os.ReadFile("../testutils/kubeconfig_mock.yaml")
+		Expect(err).ToNot(HaveOccurred())
+		kubeconfigSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-admin-kubeconfig",
+				Namespace: "bar",
+			},
+			Data: map[string][]byte{
+				"admin.conf": kubeconfigFile,
+			},
+		}
+		client = fake.NewFakeClientWithScheme(scheme.Scheme, obj, kubeconfigSecret)
+		ready, msg, err = provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+		Expect(msg).Should(Equal("Available"))
+
+		secret := &corev1.Secret{}
+		err = client.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+			secret,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data["kubeconfig"]).Should(Equal(kubeconfigFile))
+	})
+
+	It("Evaluates JSONPath and reads admin credentials", func() {
+		provider := GenericClusterProvider{Config: jsonPathConfig, Name: "foo", Namespace: "bar"}
+
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, getObject(jsonPathConfig, map[string]interface{}{
+			"ready": false,
+		}))
+		ready, msg, err := provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Not available"))
+
+		obj := getObject(jsonPathConfig, map[string]interface{}{
+			"ready":       true,
+			"adminSecret": "foo-admin",
+		})
+		kubeconfigSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "vc-foo",
+				Namespace: "bar",
+			},
+			Data: map[string][]byte{
+				"config": []byte("kubeconfig"),
+			},
+		}
+		// Secrets outside of the namespace of the resource are not read
+		otherSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-admin",
+				Namespace: "other",
+			},
+			Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte("secret"),
+			},
+		}
+		client = fake.NewFakeClientWithScheme(scheme.Scheme, obj, kubeconfigSecret, otherSecret)
+		ready, msg, err = provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeFalse())
+		Expect(msg).Should(Equal("Waiting for admin credentials secret"))
+
+		adminSecret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-admin",
+				Namespace: "bar",
+			},
+			Data: map[string][]byte{
+				"username": []byte("admin"),
+				"password": []byte("secret"),
+			},
+		}
+		client = fake.NewFakeClientWithScheme(scheme.Scheme, obj, kubeconfigSecret, adminSecret)
+		ready, _, err = provider.GetClusterStatus(ctx, client, cti)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(ready).Should(BeTrue())
+
+		secret := &corev1.Secret{}
+		err = client.Get(
+			ctx,
+			kubeClient.ObjectKey{Name: cti.GetKubeadminPassRef(), Namespace: cti.Namespace},
+			secret,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(secret.Data["username"]).Should(Equal([]byte("admin")))
+		Expect(secret.Data["password"]).Should(Equal([]byte("secret")))
+	})
+
+	It("Does not support power state", func() {
+		var provider ClusterProvider = GenericClusterProvider{Config: conditionConfig}
+		_, ok := provider.(PowerStateProvider)
+		Expect(ok).Should(BeFalse())
+	})
+})
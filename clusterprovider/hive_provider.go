@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -11,6 +12,48 @@ import (
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 )
 
+const (
+	ClusterDeploymentProviderName = "ClusterDeployment"
+	ClusterClaimProviderName      = "ClusterClaim"
+	// Label which hive sets to the version of the installed cluster
+	hiveVersionLabel = "hive.openshift.io/version-major-minor-patch"
+)
+
+func init() {
+	Register(ProviderDefinition{
+		Name:     ClusterDeploymentProviderName,
+		Resource: newResourceType(v1alpha1.ClusterDeploymentGVK),
+		Watches: []Watch{
+			{
+				ResourceType: newResourceType(v1alpha1.ClusterDeploymentGVK),
+				Object:       &hivev1.ClusterDeployment{},
+			},
+		},
+		New: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			return ClusterDeploymentProvider{
+				ClusterDeploymentName:      resource.Name,
+				ClusterDeploymentNamespace: resource.Namespace,
+			}
+		},
+	})
+	Register(ProviderDefinition{
+		Name:     ClusterClaimProviderName,
+		Resource: newResourceType(v1alpha1.ClusterClaimGVK),
+		Watches: []Watch{
+			{
+				ResourceType: newResourceType(v1alpha1.ClusterClaimGVK),
+				Object:       &hivev1.ClusterClaim{},
+			},
+		},
+		New: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			return ClusterClaimProvider{
+				ClusterClaimName:      resource.Name,
+				ClusterClaimNamespace: resource.Namespace,
+			}
+		},
+	})
+}
+
 type ClusterDeploymentProvider struct {
 	ClusterDeploymentName      string
 	ClusterDeploymentNamespace string
@@ -42,6 +85,47 @@ func (cd ClusterDeploymentProvider) GetClusterStatus(
 	return false, "Not available", nil
 }
 
+func (cd ClusterDeploymentProvider) SetPowerState(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+	powerState v1alpha1.PowerState,
+) (bool, string, error) {
+	return setCDPowerState(
+		ctx,
+		k8sClient,
+		cd.ClusterDeploymentName,
+		cd.ClusterDeploymentNamespace,
+		powerState,
+	)
+}
+
+func (cd ClusterDeploymentProvider) GetPowerStateFields() []ResourceField {
+	return []ResourceField{
+		{
+			Group:       v1alpha1.ClusterDeploymentGVK.Group,
+			Kind:        "ClusterDeployment",
+			JSONPointer: "/spec/powerState",
+		},
+	}
+}
+
+func (cd ClusterDeploymentProvider) GetClusterInfo(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.ClusterInfo, error) {
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cd.ClusterDeploymentName, Namespace: cd.ClusterDeploymentNamespace},
+		&clusterDeployment,
+	); err != nil {
+		return nil, err
+	}
+	return getCDClusterInfo(ctx, k8sClient, clusterDeployment)
+}
+
 type ClusterClaimProvider struct {
 	ClusterClaimName      string
 	ClusterClaimNamespace string
@@ -83,6 +167,173 @@ func (cc ClusterClaimProvider) GetClusterStatus(
 	return createCDSecrets(ctx, k8sClient, clusterDeployment, templateInstance)
 }
 
+func (cc ClusterClaimProvider) SetPowerState(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+	powerState v1alpha1.PowerState,
+) (bool, string, error) {
+	clusterClaim := hivev1.ClusterClaim{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cc.ClusterClaimName, Namespace: cc.ClusterClaimNamespace},
+		&clusterClaim,
+	); err != nil {
+		return false, "", err
+	}
+
+	if clusterClaim.Spec.Namespace == "" {
+		return false, "", errors.New("cluster claim is not assigned to a cluster")
+	}
+
+	return setCDPowerState(
+		ctx,
+		k8sClient,
+		clusterClaim.Spec.Namespace,
+		clusterClaim.Spec.Namespace,
+		powerState,
+	)
+}
+
+// ClusterDeployment of the claim is created by the ClusterPool, not by the cluster definition
+func (cc ClusterClaimProvider) GetPowerStateFields() []ResourceField {
+	return nil
+}
+
+func (cc ClusterClaimProvider) GetClusterInfo(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.ClusterInfo, error) {
+	clusterClaim := hivev1.ClusterClaim{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: cc.ClusterClaimName, Namespace: cc.ClusterClaimNamespace},
+		&clusterClaim,
+	); err != nil {
+		return nil, err
+	}
+
+	if clusterClaim.Spec.Namespace == "" {
+		return nil, errors.New("cluster claim is not assigned to a cluster")
+	}
+
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: clusterClaim.Spec.Namespace, Namespace: clusterClaim.Spec.Namespace},
+		&clusterDeployment,
+	); err != nil {
+		return nil, err
+	}
+	return getCDClusterInfo(ctx, k8sClient, clusterDeployment)
+}
+
+// Hive stops or starts the machines of the cluster according to the power state of the ClusterDeployment
+func setCDPowerState(
+	ctx context.Context,
+	k8sClient client.Client,
+	name string,
+	namespace string,
+	powerState v1alpha1.PowerState,
+) (bool, string, error) {
+	clusterDeployment := hivev1.ClusterDeployment{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: name, Namespace: namespace},
+		&clusterDeployment,
+	); err != nil {
+		return false, "", err
+	}
+
+	desiredPowerState := hivev1.ClusterPowerStateRunning
+	if powerState == v1alpha1.HibernatingPowerState {
+		desiredPowerState = hivev1.ClusterPowerStateHibernating
+	}
+
+	if clusterDeployment.Spec.PowerState != desiredPowerState {
+		clusterDeployment.Spec.PowerState = desiredPowerState
+		if err := k8sClient.Update(ctx, &clusterDeployment); err != nil {
+			return false, "", err
+		}
+		return false, "Power state " + string(desiredPowerState) + " requested", nil
+	}
+
+	if clusterDeployment.Status.PowerState != desiredPowerState {
+		if clusterDeployment.Status.PowerState == "" {
+			return false, "Waiting for power state " + string(desiredPowerState), nil
+		}
+		return false, "Waiting for power state " + string(desiredPowerState) + " - " +
+			string(clusterDeployment.Status.PowerState), nil
+	}
+	return true, string(desiredPowerState), nil
+}
+
+// Hive labels the ClusterDeployment with the version, platform and region of the installed cluster,
+// the install version and the platform spec are used until the labels are set
+func getCDClusterInfo(
+	ctx context.Context,
+	k8sClient client.Client,
+	clusterDeployment hivev1.ClusterDeployment,
+) (*v1alpha1.ClusterInfo, error) {
+	info := &v1alpha1.ClusterInfo{
+		OpenShiftVersion: clusterDeployment.Labels[hiveVersionLabel],
+		Platform:         clusterDeployment.Labels[hivev1.HiveClusterPlatformLabel],
+		Region:           clusterDeployment.Labels[hivev1.HiveClusterRegionLabel],
+		ControlPlaneType: v1alpha1.StandaloneControlPlaneType,
+	}
+	if info.OpenShiftVersion == "" && clusterDeployment.Status.InstallVersion != nil {
+		info.OpenShiftVersion = *clusterDeployment.Status.InstallVersion
+	}
+	platform := clusterDeployment.Spec.Platform
+	switch {
+	case platform.AWS != nil:
+		setPlatform(info, "aws", platform.AWS.Region)
+	case platform.Azure != nil:
+		setPlatform(info, "azure", platform.Azure.Region)
+	case platform.GCP != nil:
+		setPlatform(info, "gcp", platform.GCP.Region)
+	}
+
+	machinePools := &hivev1.MachinePoolList{}
+	if err := k8sClient.List(
+		ctx,
+		machinePools,
+		&client.ListOptions{Namespace: clusterDeployment.Namespace},
+	); err != nil {
+		return nil, err
+	}
+	for _, machinePool := range machinePools.Items {
+		if machinePool.Spec.ClusterDeploymentRef.Name != clusterDeployment.Name {
+			continue
+		}
+		size := ""
+		switch {
+		case machinePool.Spec.Platform.AWS != nil:
+			size = machinePool.Spec.Platform.AWS.InstanceType
+		case machinePool.Spec.Platform.Azure != nil:
+			size = machinePool.Spec.Platform.Azure.InstanceType
+		case machinePool.Spec.Platform.GCP != nil:
+			size = machinePool.Spec.Platform.GCP.InstanceType
+		}
+		info.NodePools = append(info.NodePools, v1alpha1.NodePoolInfo{
+			Name:     machinePool.Spec.Name,
+			Size:     size,
+			Replicas: machinePool.Status.Replicas,
+		})
+	}
+	return info, nil
+}
+
+func setPlatform(info *v1alpha1.ClusterInfo, platform string, region string) {
+	if info.Platform == "" {
+		info.Platform = platform
+	}
+	if info.Region == "" {
+		info.Region = region
+	}
+}
+
 func getCDKubePassRef(clusterDeployment hivev1.ClusterDeployment) string {
 	if clusterDeployment.Spec.ClusterMetadata != nil {
 		if clusterDeployment.Spec.ClusterMetadata.AdminPasswordSecretRef != nil {
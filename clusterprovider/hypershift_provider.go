@@ -2,15 +2,53 @@ package clusterprovider
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	configv1 "github.com/openshift/api/config/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/v1beta1"
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+const HostedClusterProviderName = "HostedCluster"
+
+func init() {
+	Register(ProviderDefinition{
+		Name:     HostedClusterProviderName,
+		Resource: newResourceType(v1alpha1.HostedClusterGVK, "v1alpha1"),
+		Watches: []Watch{
+			{
+				ResourceType: newResourceType(v1alpha1.HostedClusterGVK, "v1alpha1"),
+				Object:       &hypershiftv1beta1.HostedCluster{},
+			},
+			{
+				ResourceType: newResourceType(v1alpha1.NodePoolGVK, "v1alpha1"),
+				Object:       &hypershiftv1beta1.NodePool{},
+			},
+		},
+		New: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+			nodePools := []string{}
+			for _, obj := range application.Status.Resources {
+				if obj.Kind == "NodePool" {
+					nodePools = append(nodePools, obj.Name)
+				}
+			}
+			return HostedClusterProvider{
+				HostedClusterName:      resource.Name,
+				HostedClusterNamespace: resource.Namespace,
+				NodePoolNames:          nodePools,
+			}
+		},
+	})
+}
+
 type HostedClusterProvider struct {
 	HostedClusterName      string
 	HostedClusterNamespace string
@@ -143,6 +181,211 @@ func (hc HostedClusterProvider) GetClusterStatus(
 	return true, "Available", nil
 }
 
+// NodePoolScalingAnnotation stores the scaling of a NodePool before it was scaled to zero, so it can
+// be restored when the cluster is resumed
+const NodePoolScalingAnnotation = "clustertemplateinstance.openshift.io/hibernated-scaling"
+
+type nodePoolScaling struct {
+	Replicas    *int32                                 `json:"replicas,omitempty"`
+	AutoScaling *hypershiftv1beta1.NodePoolAutoScaling `json:"autoScaling,omitempty"`
+}
+
+// HyperShift control plane keeps running on the hub, hibernation scales the NodePools of the cluster
+// to zero and resume restores their original scaling
+func (hc HostedClusterProvider) SetPowerState(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+	powerState v1alpha1.PowerState,
+) (bool, string, error) {
+	nodePools := &hypershiftv1beta1.NodePoolList{}
+	if err := k8sClient.List(ctx, nodePools, &client.ListOptions{Namespace: hc.HostedClusterNamespace}); err != nil {
+		return false, "", err
+	}
+
+	allScaled := true
+	for index := range nodePools.Items {
+		nodePool := &nodePools.Items[index]
+		if nodePool.Spec.ClusterName != hc.HostedClusterName {
+			continue
+		}
+		scaling, scaledDown := nodePool.Annotations[NodePoolScalingAnnotation]
+		if powerState == v1alpha1.HibernatingPowerState {
+			if !scaledDown {
+				scalingJSON, err := json.Marshal(nodePoolScaling{
+					Replicas:    nodePool.Spec.Replicas,
+					AutoScaling: nodePool.Spec.AutoScaling,
+				})
+				if err != nil {
+					return false, "", err
+				}
+				if nodePool.Annotations == nil {
+					nodePool.Annotations = map[string]string{}
+				}
+				nodePool.Annotations[NodePoolScalingAnnotation] = string(scalingJSON)
+				nodePool.Spec.Replicas = pointer.Int32(0)
+				nodePool.Spec.AutoScaling = nil
+				if err := k8sClient.Update(ctx, nodePool); err != nil {
+					return false, "", err
+				}
+				allScaled = false
+				continue
+			}
+			if nodePool.Status.Replicas != 0 {
+				allScaled = false
+			}
+		} else {
+			if scaledDown {
+				previousScaling := nodePoolScaling{}
+				if err := json.Unmarshal([]byte(scaling), &previousScaling); err != nil {
+					return false, "", fmt.Errorf(
+						"failed to read scaling of nodepool '%s' - %q",
+						nodePool.Name,
+						err,
+					)
+				}
+				nodePool.Spec.Replicas = previousScaling.Replicas
+				nodePool.Spec.AutoScaling = previousScaling.AutoScaling
+				delete(nodePool.Annotations, NodePoolScalingAnnotation)
+				if err := k8sClient.Update(ctx, nodePool); err != nil {
+					return false, "", err
+				}
+				allScaled = false
+				continue
+			}
+			if nodePool.Spec.Replicas != nil && nodePool.Status.Replicas < *nodePool.Spec.Replicas {
+				allScaled = false
+			}
+		}
+	}
+
+	if !allScaled {
+		return false, "Waiting for nodepools to scale", nil
+	}
+	return true, string(powerState), nil
+}
+
+func (hc HostedClusterProvider) GetPowerStateFields() []ResourceField {
+	return []ResourceField{
+		{Group: v1alpha1.NodePoolGVK.Group, Kind: "NodePool", JSONPointer: "/spec/replicas"},
+		{Group: v1alpha1.NodePoolGVK.Group, Kind: "NodePool", JSONPointer: "/spec/autoScaling"},
+	}
+}
+
+func (hc HostedClusterProvider) GetClusterInfo(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.ClusterInfo, error) {
+	hostedCluster := &hypershiftv1beta1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return nil, err
+	}
+
+	info := &v1alpha1.ClusterInfo{
+		OpenShiftVersion: getHostedClusterVersion(*hostedCluster),
+		Platform:         string(hostedCluster.Spec.Platform.Type),
+		ControlPlaneType: v1alpha1.HostedControlPlaneType,
+	}
+	if hostedCluster.Spec.Platform.AWS != nil {
+		info.Region = hostedCluster.Spec.Platform.AWS.Region
+	} else if hostedCluster.Spec.Platform.Azure != nil {
+		info.Region = hostedCluster.Spec.Platform.Azure.Location
+	}
+
+	nodePools := &hypershiftv1beta1.NodePoolList{}
+	if err := k8sClient.List(ctx, nodePools, &client.ListOptions{Namespace: hc.HostedClusterNamespace}); err != nil {
+		return nil, err
+	}
+	for _, nodePool := range nodePools.Items {
+		if nodePool.Spec.ClusterName != hc.HostedClusterName {
+			continue
+		}
+		size := ""
+		if nodePool.Spec.Platform.AWS != nil {
+			size = nodePool.Spec.Platform.AWS.InstanceType
+		} else if nodePool.Spec.Platform.Azure != nil {
+			size = nodePool.Spec.Platform.Azure.VMSize
+		}
+		info.NodePools = append(info.NodePools, v1alpha1.NodePoolInfo{
+			Name:     nodePool.Name,
+			Size:     size,
+			Replicas: nodePool.Status.Replicas,
+		})
+	}
+	return info, nil
+}
+
+// The upgrade starts once the release image of the HostedCluster was updated, it finishes once the
+// version history contains the release as completed update
+func (hc HostedClusterProvider) GetUpgradeStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+	upgrade v1alpha1.ClusterUpgradeStatus,
+) (bool, string, error) {
+	hostedCluster := &hypershiftv1beta1.HostedCluster{}
+	if err := k8sClient.Get(
+		ctx,
+		client.ObjectKey{Name: hc.HostedClusterName, Namespace: hc.HostedClusterNamespace},
+		hostedCluster,
+	); err != nil {
+		return false, "", err
+	}
+
+	if hostedCluster.Spec.Release.Image != upgrade.ReleaseImage {
+		return false, "Waiting for the hosted cluster to request release " + upgrade.Version, nil
+	}
+	if condition := meta.FindStatusCondition(
+		hostedCluster.Status.Conditions,
+		string(hypershiftv1beta1.ValidReleaseImage),
+	); condition != nil && condition.Status == metav1.ConditionFalse {
+		return false, "", &UpgradeFailedError{
+			Msg: "Invalid release image - " + condition.Message,
+		}
+	}
+
+	if hostedCluster.Status.Version != nil {
+		// History is ordered from the latest update
+		for _, update := range hostedCluster.Status.Version.History {
+			if update.Image != upgrade.ReleaseImage && update.Version != upgrade.Version {
+				continue
+			}
+			if update.State == configv1.CompletedUpdate {
+				return true, "Upgraded to " + update.Version, nil
+			}
+			break
+		}
+	}
+
+	if condition := meta.FindStatusCondition(
+		hostedCluster.Status.Conditions,
+		string(hypershiftv1beta1.ClusterVersionFailing),
+	); condition != nil && condition.Status == metav1.ConditionTrue {
+		return false, "", &UpgradeFailedError{
+			Msg: "Failed to upgrade to " + upgrade.Version + " - " + condition.Message,
+		}
+	}
+	return false, "Upgrading to " + upgrade.Version, nil
+}
+
+// Returns the latest completed version of the history, the desired version if none completed yet
+func getHostedClusterVersion(hostedCluster hypershiftv1beta1.HostedCluster) string {
+	if hostedCluster.Status.Version == nil {
+		return ""
+	}
+	for _, update := range hostedCluster.Status.Version.History {
+		if update.State == configv1.CompletedUpdate {
+			return update.Version
+		}
+	}
+	return hostedCluster.Status.Version.Desired.Version
+}
+
 func getKubeAdminRef(hostedCluster hypershiftv1beta1.HostedCluster) string {
 	if hostedCluster.Status.KubeadminPassword != nil {
 		return hostedCluster.Status.KubeadminPassword.Name
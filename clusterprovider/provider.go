@@ -2,6 +2,8 @@ package clusterprovider
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
@@ -16,6 +18,8 @@ var (
 	providerLog = logf.Log.WithName("cluster-provider")
 )
 
+// ClusterProvider reports the status of the cluster, optional lifecycle hooks are implemented via
+// PowerStateProvider, DeletionProvider, ClusterInfoProvider and UpgradeProvider
 type ClusterProvider interface {
 	GetClusterStatus(
 		ctx context.Context,
@@ -24,58 +28,163 @@ type ClusterProvider interface {
 	) (bool, string, error)
 }
 
+// PowerStateProvider is implemented by providers which can hibernate and resume the cluster
+type PowerStateProvider interface {
+	// Requests the power state of the cluster. Returns true once the cluster reached the power state
+	// and a message describing the progress.
+	SetPowerState(
+		ctx context.Context,
+		k8sClient client.Client,
+		templateInstance v1alpha1.ClusterTemplateInstance,
+		powerState v1alpha1.PowerState,
+	) (bool, string, error)
+	// Returns the fields of the cluster definition resources which SetPowerState modifies. ArgoCD
+	// has to ignore their differences, otherwise it reverts the power state.
+	GetPowerStateFields() []ResourceField
+}
+
+// ResourceField is a field of the resources of the given kind, addressed by JSON pointer
+type ResourceField struct {
+	Group       string
+	Kind        string
+	JSONPointer string
+}
+
+// DeletionProvider is implemented by providers which clean up the cluster before its cluster
+// definition application is deleted
+type DeletionProvider interface {
+	// Returns true once the cleanup finished
+	DeleteCluster(
+		ctx context.Context,
+		k8sClient client.Client,
+		templateInstance v1alpha1.ClusterTemplateInstance,
+	) (bool, error)
+}
+
+// ClusterInfoProvider is implemented by providers which report facts about the ready cluster
+type ClusterInfoProvider interface {
+	GetClusterInfo(
+		ctx context.Context,
+		k8sClient client.Client,
+		templateInstance v1alpha1.ClusterTemplateInstance,
+	) (*v1alpha1.ClusterInfo, error)
+}
+
+// UpgradeProvider is implemented by providers which report the progress of the cluster upgrade
+type UpgradeProvider interface {
+	// Returns true once the cluster runs the release of the upgrade and a message describing the
+	// progress. UpgradeFailedError is returned if the cluster failed to upgrade.
+	GetUpgradeStatus(
+		ctx context.Context,
+		k8sClient client.Client,
+		templateInstance v1alpha1.ClusterTemplateInstance,
+		upgrade v1alpha1.ClusterUpgradeStatus,
+	) (bool, string, error)
+}
+
+type UpgradeFailedError struct {
+	Msg string
+}
+
+func (u *UpgradeFailedError) Error() string {
+	return u.Msg
+}
+
+// CheckIgnoreDifferences returns an error if the application doesn't ignore differences of the
+// fields or its sync doesn't respect the ignored differences, ArgoCD would revert the fields then
+func CheckIgnoreDifferences(application argo.Application, fields []ResourceField) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	for _, field := range fields {
+		if !isDifferenceIgnored(application, field) {
+			return fmt.Errorf(
+				"application '%s' has to ignore differences of '%s' of %s",
+				application.Name,
+				field.JSONPointer,
+				field.Kind,
+			)
+		}
+	}
+	if application.Spec.SyncPolicy == nil ||
+		!application.Spec.SyncPolicy.SyncOptions.HasOption(respectIgnoreDifferencesOption) {
+		return fmt.Errorf(
+			"application '%s' has to set sync option '%s'",
+			application.Name,
+			respectIgnoreDifferencesOption,
+		)
+	}
+	return nil
+}
+
+const respectIgnoreDifferencesOption = "RespectIgnoreDifferences=true"
+
+// The field is ignored also when any of its parents is ignored
+func isDifferenceIgnored(application argo.Application, field ResourceField) bool {
+	jqPath := strings.ReplaceAll(field.JSONPointer, "/", ".")
+	for _, ignored := range application.Spec.IgnoreDifferences {
+		if ignored.Group != field.Group || ignored.Kind != field.Kind {
+			continue
+		}
+		for _, pointer := range ignored.JSONPointers {
+			if field.JSONPointer == pointer || strings.HasPrefix(field.JSONPointer, pointer+"/") {
+				return true
+			}
+		}
+		for _, expression := range ignored.JQPathExpressions {
+			if jqPath == expression || strings.HasPrefix(jqPath, expression+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetClusterProvider returns the registered provider of the first resource of the application which
+// matches any provider, nil if there is none
 func GetClusterProvider(application argo.Application) ClusterProvider {
 	for _, obj := range application.Status.Resources {
-		switch obj.Kind {
-		case v1alpha1.HostedClusterGVK.Resource:
-			if obj.Group == v1alpha1.HostedClusterGVK.Group {
-				providerLog.Info("Cluster provider: HostedCluster")
-				if obj.Version != v1alpha1.HostedClusterGVK.Version {
-					providerLog.Info("Unknown version: ", "version", obj.Version)
-					return nil
-				}
-				nodePools := []string{}
-				for _, obj := range application.Status.Resources {
-					if obj.Kind == "NodePool" {
-						nodePools = append(nodePools, obj.Name)
-					}
-				}
-				return HostedClusterProvider{
-					HostedClusterName:      obj.Name,
-					HostedClusterNamespace: obj.Namespace,
-					NodePoolNames:          nodePools,
-				}
+		for _, definition := range registry {
+			if obj.Group != definition.Resource.Group || obj.Kind != definition.Resource.Kind {
+				continue
 			}
-		case v1alpha1.ClusterDeploymentGVK.Resource:
-			if obj.Group == v1alpha1.ClusterDeploymentGVK.Group {
-				providerLog.Info("Cluster provider: ClusterDeployment")
-				if obj.Version != v1alpha1.ClusterDeploymentGVK.Version {
-					providerLog.Info("Unknown version: ", "version", obj.Version)
-					return nil
-				}
-				return ClusterDeploymentProvider{
-					ClusterDeploymentName:      obj.Name,
-					ClusterDeploymentNamespace: obj.Namespace,
-				}
-			}
-		case v1alpha1.ClusterClaimGVK.Resource:
-			if obj.Group == v1alpha1.ClusterClaimGVK.Group {
-				providerLog.Info("Cluster provider: ClusterClaim")
-				if obj.Version != v1alpha1.ClusterClaimGVK.Version {
-					providerLog.Info("Unknown version: ", "version", obj.Version)
-					return nil
-				}
-				return ClusterClaimProvider{
-					ClusterClaimName:      obj.Name,
-					ClusterClaimNamespace: obj.Namespace,
-				}
+			if !definition.Resource.Matches(obj) {
+				providerLog.Info("Unknown version: ", "kind", obj.Kind, "version", obj.Version)
+				continue
 			}
+			providerLog.Info("Cluster provider: " + definition.Name)
+			return definition.New(obj, application)
 		}
 	}
 	providerLog.Info("Cluster provider: Unknown")
 	return nil
 }
 
+// GetInstanceClusterProvider returns the generic provider when the template of the instance configures
+// one, otherwise the provider is detected from the kinds of the application resources
+func GetInstanceClusterProvider(
+	application argo.Application,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (ClusterProvider, error) {
+	config, err := templateInstance.GetClusterProviderConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return GetClusterProvider(application), nil
+	}
+	provider := GetGenericClusterProvider(application, *config)
+	if provider == nil {
+		return nil, fmt.Errorf(
+			"%s of %s/%s not found among the resources of the application",
+			config.Kind,
+			config.Group,
+			config.Version,
+		)
+	}
+	return provider, nil
+}
+
 func CreateClusterSecrets(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -8,7 +8,9 @@ import (
 	"github.com/kubernetes-client/go-base/config/api"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	configv1 "github.com/openshift/api/config/v1"
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
+	hiveaws "github.com/openshift/hive/apis/hive/v1/aws"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/v1beta1"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	"gopkg.in/yaml.v3"
@@ -16,6 +18,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	kubeClient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -204,8 +208,681 @@ var _ = Describe("Test cluster providers", func() {
 		}
 		provider = GetClusterProvider(app)
 		Expect(provider).Should(BeNil())
+
+		app = argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:      "Cluster",
+						Version:   "v1beta1",
+						Group:     "cluster.x-k8s.io",
+						Name:      "foo",
+						Namespace: "bar",
+					},
+				},
+			},
+		}
+		provider = GetClusterProvider(app)
+		Expect(provider).Should(Equal(CAPIClusterProvider{
+			ClusterName:      "foo",
+			ClusterNamespace: "bar",
+		}))
+
+		app = argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:      "Cluster",
+						Version:   "v1alpha4",
+						Group:     "cluster.x-k8s.io",
+						Name:      "foo",
+						Namespace: "bar",
+					},
+				},
+			},
+		}
+		provider = GetClusterProvider(app)
+		Expect(provider).Should(BeNil())
+	})
+
+	Context("Test CAPI Cluster provider", func() {
+		capiProvider := CAPIClusterProvider{
+			ClusterName:      "foo",
+			ClusterNamespace: "bar",
+		}
+		getCluster := func(controlPlaneReady corev1.ConditionStatus) *capiv1beta1.Cluster {
+			return &capiv1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Status: capiv1beta1.ClusterStatus{
+					Conditions: capiv1beta1.Conditions{
+						{
+							Type:   capiv1beta1.InfrastructureReadyCondition,
+							Status: corev1.ConditionTrue,
+						},
+						{
+							Type:   capiv1beta1.ControlPlaneReadyCondition,
+							Status: controlPlaneReady,
+							Reason: "foo",
+						},
+					},
+				},
+			}
+		}
+
+		It("Returns not ready and err when Cluster does not exist", func() {
+			client := fake.NewFakeClientWithScheme(scheme.Scheme)
+
+			ready, msg, err := capiProvider.GetClusterStatus(ctx, client, cti)
+			Expect(err).Should(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+			Expect(msg).Should(Equal(""))
+		})
+		It("Returns not ready when control plane is not ready", func() {
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getCluster(corev1.ConditionFalse),
+			)
+
+			ready, msg, err := capiProvider.GetClusterStatus(ctx, client, cti)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+			Expect(msg).Should(Equal("Not available - foo"))
+		})
+		It("Returns not ready when kubeconfig secret is missing", func() {
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getCluster(corev1.ConditionTrue),
+			)
+
+			ready, msg, err := capiProvider.GetClusterStatus(ctx, client, cti)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+			Expect(msg).Should(Equal("Waiting for kubeconfig secret"))
+		})
+		It("Returns err when kubeconfig secret has incorrect format", func() {
+			kubeconfigSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-kubeconfig",
+					Namespace: "bar",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getCluster(corev1.ConditionTrue),
+				kubeconfigSecret,
+			)
+
+			ready, msg, err := capiProvider.GetClusterStatus(ctx, client, cti)
+			Expect(err).To(HaveOccurred())
+			Expect(ready).Should(BeFalse())
+			Expect(msg).Should(Equal(""))
+		})
+		It("Returns ready and creates kubeconfig secret", func() {
+			kubeconfigFile, err := os.ReadFile("../testutils/kubeconfig_mock.yaml")
+			Expect(err).ToNot(HaveOccurred())
+			kubeconfigSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-kubeconfig",
+					Namespace: "bar",
+				},
+				Data: map[string][]byte{
+					"value": kubeconfigFile,
+				},
+			}
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getCluster(corev1.ConditionTrue),
+				kubeconfigSecret,
+			)
+
+			ready, msg, err := capiProvider.GetClusterStatus(ctx, client, cti)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ready).Should(BeTrue())
+			Expect(msg).Should(Equal("Available"))
+
+			secret := &corev1.Secret{}
+			err = client.Get(
+				ctx,
+				kubeClient.ObjectKey{Name: cti.GetKubeconfigRef(), Namespace: cti.Namespace},
+				secret,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secret.Data["kubeconfig"]).Should(Equal(kubeconfigFile))
+
+			err = client.Get(
+				ctx,
+				kubeClient.ObjectKey{Name: cti.GetKubeadminPassRef(), Namespace: cti.Namespace},
+				secret,
+			)
+			Expect(err).To(HaveOccurred())
+		})
+		It("Does not support power state", func() {
+			var provider ClusterProvider = capiProvider
+			_, ok := provider.(PowerStateProvider)
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("Power state", func() {
+		It("Hibernates and resumes ClusterDeployment", func() {
+			clusterDeployment := &hivev1.ClusterDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, clusterDeployment)
+
+			done, msg, err := clusterDeploymentProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			Expect(msg).Should(Equal("Power state Hibernating requested"))
+
+			err = client.Get(ctx, kubeClient.ObjectKeyFromObject(clusterDeployment), clusterDeployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterDeployment.Spec.PowerState).Should(Equal(hivev1.ClusterPowerStateHibernating))
+
+			clusterDeployment.Status.PowerState = hivev1.ClusterPowerStateStopping
+			Expect(client.Update(ctx, clusterDeployment)).Should(Succeed())
+			done, msg, err = clusterDeploymentProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			Expect(msg).Should(Equal("Waiting for power state Hibernating - Stopping"))
+
+			clusterDeployment.Status.PowerState = hivev1.ClusterPowerStateHibernating
+			Expect(client.Update(ctx, clusterDeployment)).Should(Succeed())
+			done, _, err = clusterDeploymentProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeTrue())
+
+			done, _, err = clusterDeploymentProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.RunningPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			err = client.Get(ctx, kubeClient.ObjectKeyFromObject(clusterDeployment), clusterDeployment)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterDeployment.Spec.PowerState).Should(Equal(hivev1.ClusterPowerStateRunning))
+		})
+
+		It("Fails to hibernate unassigned ClusterClaim", func() {
+			clusterClaim := &hivev1.ClusterClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, clusterClaim)
+
+			done, _, err := clusterClaimProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).To(HaveOccurred())
+			Expect(done).Should(BeFalse())
+		})
+
+		It("Scales HostedCluster nodepools to zero and back", func() {
+			nodePool := &hypershiftv1beta1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "np1",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.NodePoolSpec{
+					ClusterName: "foo",
+					Replicas:    pointer.Int32(3),
+				},
+				Status: hypershiftv1beta1.NodePoolStatus{
+					Replicas: 3,
+				},
+			}
+			otherNodePool := &hypershiftv1beta1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "np2",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.NodePoolSpec{
+					ClusterName: "other",
+					Replicas:    pointer.Int32(2),
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, nodePool, otherNodePool)
+
+			done, msg, err := hypershiftProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			Expect(msg).Should(Equal("Waiting for nodepools to scale"))
+
+			err = client.Get(ctx, kubeClient.ObjectKeyFromObject(nodePool), nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*nodePool.Spec.Replicas).Should(Equal(int32(0)))
+			Expect(nodePool.Annotations).Should(HaveKey(NodePoolScalingAnnotation))
+
+			err = client.Get(ctx, kubeClient.ObjectKeyFromObject(otherNodePool), otherNodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*otherNodePool.Spec.Replicas).Should(Equal(int32(2)))
+
+			nodePool.Status.Replicas = 0
+			Expect(client.Update(ctx, nodePool)).Should(Succeed())
+			done, _, err = hypershiftProvider.SetPowerState(
+				ctx,
+				client,
+				cti,
+				v1alpha1.HibernatingPowerState,
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeTrue())
+
+			done, _, err = hypershiftProvider.SetPowerState(ctx, client, cti, v1alpha1.RunningPowerState)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+
+			err = client.Get(ctx, kubeClient.ObjectKeyFromObject(nodePool), nodePool)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*nodePool.Spec.Replicas).Should(Equal(int32(3)))
+			Expect(nodePool.Annotations).ShouldNot(HaveKey(NodePoolScalingAnnotation))
+
+			nodePool.Status.Replicas = 3
+			Expect(client.Update(ctx, nodePool)).Should(Succeed())
+			done, _, err = hypershiftProvider.SetPowerState(ctx, client, cti, v1alpha1.RunningPowerState)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeTrue())
+		})
+
+		It("Requires the application to ignore differences of power state fields", func() {
+			app := argo.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "cluster",
+				},
+			}
+			fields := hypershiftProvider.GetPowerStateFields()
+			err := CheckIgnoreDifferences(app, fields)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(Equal(
+				"application 'cluster' has to ignore differences of '/spec/replicas' of NodePool",
+			))
+
+			app.Spec.IgnoreDifferences = []argo.ResourceIgnoreDifferences{
+				{
+					Group:        "hypershift.openshift.io",
+					Kind:         "NodePool",
+					JSONPointers: []string{"/spec/replicas"},
+				},
+			}
+			err = CheckIgnoreDifferences(app, fields)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(ContainSubstring("'/spec/autoScaling' of NodePool"))
+
+			app.Spec.IgnoreDifferences[0].JQPathExpressions = []string{".spec.autoScaling"}
+			err = CheckIgnoreDifferences(app, fields)
+			Expect(err).Should(HaveOccurred())
+			Expect(err.Error()).Should(Equal(
+				"application 'cluster' has to set sync option 'RespectIgnoreDifferences=true'",
+			))
+
+			app.Spec.SyncPolicy = &argo.SyncPolicy{
+				SyncOptions: argo.SyncOptions{"RespectIgnoreDifferences=true"},
+			}
+			Expect(CheckIgnoreDifferences(app, fields)).Should(Succeed())
+
+			app.Spec.IgnoreDifferences = []argo.ResourceIgnoreDifferences{
+				{
+					Group:        "hive.openshift.io",
+					Kind:         "ClusterDeployment",
+					JSONPointers: []string{"/spec"},
+				},
+			}
+			Expect(CheckIgnoreDifferences(app, clusterDeploymentProvider.GetPowerStateFields())).Should(Succeed())
+			Expect(CheckIgnoreDifferences(app, fields)).ShouldNot(Succeed())
+			Expect(CheckIgnoreDifferences(argo.Application{}, clusterClaimProvider.GetPowerStateFields())).
+				Should(Succeed())
+		})
 	})
 
+	Context("Cluster info", func() {
+		It("Reports HostedCluster version, platform and nodepools", func() {
+			hostedCluster := &hypershiftv1beta1.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.HostedClusterSpec{
+					Platform: hypershiftv1beta1.PlatformSpec{
+						Type: hypershiftv1beta1.AWSPlatform,
+						AWS: &hypershiftv1beta1.AWSPlatformSpec{
+							Region: "us-east-1",
+						},
+					},
+				},
+				Status: hypershiftv1beta1.HostedClusterStatus{
+					Version: &hypershiftv1beta1.ClusterVersionStatus{
+						Desired: configv1.Release{Version: "4.12.1"},
+						History: []configv1.UpdateHistory{
+							{State: configv1.PartialUpdate, Version: "4.12.1"},
+							{State: configv1.CompletedUpdate, Version: "4.12.0"},
+						},
+					},
+				},
+			}
+			nodePool := &hypershiftv1beta1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "np1",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.NodePoolSpec{
+					ClusterName: "foo",
+					Platform: hypershiftv1beta1.NodePoolPlatform{
+						AWS: &hypershiftv1beta1.AWSNodePoolPlatform{
+							InstanceType: "m5.large",
+						},
+					},
+				},
+				Status: hypershiftv1beta1.NodePoolStatus{
+					Replicas: 2,
+				},
+			}
+			otherNodePool := &hypershiftv1beta1.NodePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "np2",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.NodePoolSpec{
+					ClusterName: "other",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, hostedCluster, nodePool, otherNodePool)
+
+			info, err := hypershiftProvider.GetClusterInfo(ctx, client, cti)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*info).Should(Equal(v1alpha1.ClusterInfo{
+				OpenShiftVersion: "4.12.0",
+				Platform:         "AWS",
+				Region:           "us-east-1",
+				ControlPlaneType: v1alpha1.HostedControlPlaneType,
+				NodePools: []v1alpha1.NodePoolInfo{
+					{Name: "np1", Size: "m5.large", Replicas: 2},
+				},
+			}))
+		})
+
+		It("Reports ClusterDeployment version, platform and machinepools", func() {
+			clusterDeployment := &hivev1.ClusterDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+					Labels: map[string]string{
+						hiveVersionLabel: "4.11.9",
+					},
+				},
+				Spec: hivev1.ClusterDeploymentSpec{
+					Platform: hivev1.Platform{
+						AWS: &hiveaws.Platform{Region: "eu-west-1"},
+					},
+				},
+			}
+			machinePool := &hivev1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-worker",
+					Namespace: "bar",
+				},
+				Spec: hivev1.MachinePoolSpec{
+					ClusterDeploymentRef: corev1.LocalObjectReference{Name: "foo"},
+					Name:                 "worker",
+					Platform: hivev1.MachinePoolPlatform{
+						AWS: &hiveaws.MachinePoolPlatform{InstanceType: "m5.xlarge"},
+					},
+				},
+				Status: hivev1.MachinePoolStatus{
+					Replicas: 3,
+				},
+			}
+			otherMachinePool := &hivev1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "other-worker",
+					Namespace: "bar",
+				},
+				Spec: hivev1.MachinePoolSpec{
+					ClusterDeploymentRef: corev1.LocalObjectReference{Name: "other"},
+					Name:                 "worker",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				clusterDeployment,
+				machinePool,
+				otherMachinePool,
+			)
+
+			info, err := clusterDeploymentProvider.GetClusterInfo(ctx, client, cti)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*info).Should(Equal(v1alpha1.ClusterInfo{
+				OpenShiftVersion: "4.11.9",
+				Platform:         "aws",
+				Region:           "eu-west-1",
+				ControlPlaneType: v1alpha1.StandaloneControlPlaneType,
+				NodePools: []v1alpha1.NodePoolInfo{
+					{Name: "worker", Size: "m5.xlarge", Replicas: 3},
+				},
+			}))
+		})
+
+		It("Fails to report info of unassigned ClusterClaim", func() {
+			clusterClaim := &hivev1.ClusterClaim{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, clusterClaim)
+
+			_, err := clusterClaimProvider.GetClusterInfo(ctx, client, cti)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Reports CAPI Cluster version and machine deployments", func() {
+			cluster := &capiv1beta1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Spec: capiv1beta1.ClusterSpec{
+					InfrastructureRef: &corev1.ObjectReference{Kind: "AWSCluster"},
+					ControlPlaneRef:   &corev1.ObjectReference{Kind: "KubeadmControlPlane"},
+				},
+			}
+			machineDeployment := &capiv1beta1.MachineDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo-md-0",
+					Namespace: "bar",
+					Labels: map[string]string{
+						capiv1beta1.ClusterLabelName: "foo",
+					},
+				},
+				Spec: capiv1beta1.MachineDeploymentSpec{
+					ClusterName: "foo",
+					Template: capiv1beta1.MachineTemplateSpec{
+						Spec: capiv1beta1.MachineSpec{
+							ClusterName: "foo",
+							Version:     pointer.String("v1.25.3"),
+						},
+					},
+				},
+				Status: capiv1beta1.MachineDeploymentStatus{
+					Replicas: 2,
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, cluster, machineDeployment)
+
+			info, err := CAPIClusterProvider{
+				ClusterName:      "foo",
+				ClusterNamespace: "bar",
+			}.GetClusterInfo(ctx, client, cti)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*info).Should(Equal(v1alpha1.ClusterInfo{
+				KubernetesVersion: "v1.25.3",
+				Platform:          "AWSCluster",
+				ControlPlaneType:  "KubeadmControlPlane",
+				NodePools: []v1alpha1.NodePoolInfo{
+					{Name: "foo-md-0", Replicas: 2},
+				},
+			}))
+		})
+
+		It("Is not reported by generic provider", func() {
+			var provider ClusterProvider = GenericClusterProvider{}
+			_, ok := provider.(ClusterInfoProvider)
+			Expect(ok).Should(BeFalse())
+		})
+	})
+
+	Context("Upgrade", func() {
+		upgrade := v1alpha1.ClusterUpgradeStatus{
+			Version:      "4.12.2",
+			ReleaseImage: "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64",
+			Parameter:    "releaseImage",
+		}
+		getUpgradingHostedCluster := func(
+			releaseImage string,
+			history []configv1.UpdateHistory,
+			conditions []metav1.Condition,
+		) *hypershiftv1beta1.HostedCluster {
+			return &hypershiftv1beta1.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "bar",
+				},
+				Spec: hypershiftv1beta1.HostedClusterSpec{
+					Release: hypershiftv1beta1.Release{Image: releaseImage},
+				},
+				Status: hypershiftv1beta1.HostedClusterStatus{
+					Version: &hypershiftv1beta1.ClusterVersionStatus{
+						History: history,
+					},
+					Conditions: conditions,
+				},
+			}
+		}
+
+		It("Waits for the HostedCluster to request the release", func() {
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getUpgradingHostedCluster(
+					"quay.io/openshift-release-dev/ocp-release:4.12.1-x86_64",
+					[]configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: "4.12.1"}},
+					nil,
+				),
+			)
+			done, msg, err := hypershiftProvider.GetUpgradeStatus(ctx, client, cti, upgrade)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			Expect(msg).Should(Equal("Waiting for the hosted cluster to request release 4.12.2"))
+		})
+
+		It("Reports progress until the release is completed", func() {
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getUpgradingHostedCluster(
+					upgrade.ReleaseImage,
+					[]configv1.UpdateHistory{
+						{State: configv1.PartialUpdate, Version: "4.12.2", Image: upgrade.ReleaseImage},
+						{State: configv1.CompletedUpdate, Version: "4.12.1"},
+					},
+					nil,
+				),
+			)
+			done, msg, err := hypershiftProvider.GetUpgradeStatus(ctx, client, cti, upgrade)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeFalse())
+			Expect(msg).Should(Equal("Upgrading to 4.12.2"))
+
+			client = fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getUpgradingHostedCluster(
+					upgrade.ReleaseImage,
+					[]configv1.UpdateHistory{
+						{State: configv1.CompletedUpdate, Version: "4.12.2", Image: upgrade.ReleaseImage},
+						{State: configv1.CompletedUpdate, Version: "4.12.1"},
+					},
+					nil,
+				),
+			)
+			done, msg, err = hypershiftProvider.GetUpgradeStatus(ctx, client, cti, upgrade)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(done).Should(BeTrue())
+			Expect(msg).Should(Equal("Upgraded to 4.12.2"))
+		})
+
+		It("Returns UpgradeFailedError when the upgrade failed", func() {
+			client := fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getUpgradingHostedCluster(
+					upgrade.ReleaseImage,
+					nil,
+					[]metav1.Condition{{
+						Type:    string(hypershiftv1beta1.ValidReleaseImage),
+						Status:  metav1.ConditionFalse,
+						Message: "image not found",
+					}},
+				),
+			)
+			_, _, err := hypershiftProvider.GetUpgradeStatus(ctx, client, cti, upgrade)
+			Expect(err).Should(HaveOccurred())
+			Expect(err).Should(BeAssignableToTypeOf(&UpgradeFailedError{}))
+			Expect(err.Error()).Should(Equal("Invalid release image - image not found"))
+
+			client = fake.NewFakeClientWithScheme(
+				scheme.Scheme,
+				getUpgradingHostedCluster(
+					upgrade.ReleaseImage,
+					[]configv1.UpdateHistory{
+						{State: configv1.PartialUpdate, Version: "4.12.2", Image: upgrade.ReleaseImage},
+					},
+					[]metav1.Condition{{
+						Type:    string(hypershiftv1beta1.ClusterVersionFailing),
+						Status:  metav1.ConditionTrue,
+						Message: "operator degraded",
+					}},
+				),
+			)
+			_, _, err = hypershiftProvider.GetUpgradeStatus(ctx, client, cti, upgrade)
+			Expect(err).Should(BeAssignableToTypeOf(&UpgradeFailedError{}))
+			Expect(err.Error()).Should(Equal("Failed to upgrade to 4.12.2 - operator degraded"))
+		})
+
+		It("Is not reported by generic provider", func() {
+			var provider ClusterProvider = GenericClusterProvider{}
+			_, ok := provider.(UpgradeProvider)
+			Expect(ok).Should(BeFalse())
+		})
+	})
 })
 
 func testProvider(
@@ -0,0 +1,90 @@
+package clusterprovider
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"golang.org/x/exp/slices"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ResourceType identifies a resource of a platform among the resources of the ArgoCD application
+type ResourceType struct {
+	Group string
+	Kind  string
+	// Versions in which the resource is recognized. The provider reads the resource in the first
+	// version, so the CRD has to serve it
+	Versions []string
+}
+
+// Matches returns true if the resource of the application is of this type in any supported version
+func (t ResourceType) Matches(resource argo.ResourceStatus) bool {
+	return resource.Group == t.Group && resource.Kind == t.Kind &&
+		slices.Contains(t.Versions, resource.Version)
+}
+
+// newResourceType returns the type of the GVR, the additional versions are recognized as well
+func newResourceType(gvr schema.GroupVersionResource, additionalVersions ...string) ResourceType {
+	return ResourceType{
+		Group:    gvr.Group,
+		Kind:     gvr.Resource,
+		Versions: append([]string{gvr.Version}, additionalVersions...),
+	}
+}
+
+// GVR returns the type in the version read by the provider. Following the convention of the
+// operator, Resource holds the kind.
+func (t ResourceType) GVR() schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    t.Group,
+		Resource: t.Kind,
+		Version:  t.Versions[0],
+	}
+}
+
+// Watch is a resource which the instance controller watches while the provider is enabled. Changes
+// of the resource reconcile the instances whose day1 application contains it.
+type Watch struct {
+	ResourceType
+	Object client.Object
+}
+
+// ProviderDefinition registers a cluster provider
+type ProviderDefinition struct {
+	// Unique name of the provider
+	Name string
+	// Resource which represents the cluster. The provider is enabled once its CRD is installed
+	Resource ResourceType
+	// Resources which are watched by the instance controller
+	Watches []Watch
+	// New returns the provider of the cluster resource of the application
+	New func(resource argo.ResourceStatus, application argo.Application) ClusterProvider
+}
+
+var registry = []ProviderDefinition{}
+
+// Register adds the provider to the registry, a provider with the same name is replaced
+func Register(definition ProviderDefinition) {
+	for i := range registry {
+		if registry[i].Name == definition.Name {
+			registry[i] = definition
+			return
+		}
+	}
+	registry = append(registry, definition)
+}
+
+// GetProviders returns the registered providers in the order of registration
+func GetProviders() []ProviderDefinition {
+	return slices.Clone(registry)
+}
+
+// GetProvider returns the registered provider of the given name, nil if it isn't registered
+func GetProvider(name string) *ProviderDefinition {
+	for i := range registry {
+		if registry[i].Name == name {
+			definition := registry[i]
+			return &definition
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package clusterprovider
+
+import (
+	"context"
+
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type fakeProvider struct {
+	name string
+}
+
+func (p fakeProvider) GetClusterStatus(
+	ctx context.Context,
+	k8sClient client.Client,
+	templateInstance v1alpha1.ClusterTemplateInstance,
+) (bool, string, error) {
+	return true, "Available", nil
+}
+
+var _ = Describe("Cluster provider registry", func() {
+	It("Contains the built-in providers", func() {
+		names := []string{}
+		for _, provider := range GetProviders() {
+			names = append(names, provider.Name)
+		}
+		Expect(names).Should(ContainElements(
+			HostedClusterProviderName,
+			ClusterDeploymentProviderName,
+			ClusterClaimProviderName,
+			CAPIClusterProviderName,
+		))
+		Expect(GetProvider(HostedClusterProviderName).Resource.GVR()).
+			Should(Equal(v1alpha1.HostedClusterGVK))
+		Expect(GetProvider("foo")).Should(BeNil())
+	})
+
+	It("Recognizes all supported versions", func() {
+		resource := argo.ResourceStatus{
+			Kind:    "HostedCluster",
+			Group:   "hypershift.openshift.io",
+			Version: "v1alpha1",
+		}
+		Expect(GetProvider(HostedClusterProviderName).Resource.Matches(resource)).Should(BeTrue())
+		resource.Version = "v1beta1"
+		Expect(GetProvider(HostedClusterProviderName).Resource.Matches(resource)).Should(BeTrue())
+		resource.Version = "v1alpha2"
+		Expect(GetProvider(HostedClusterProviderName).Resource.Matches(resource)).Should(BeFalse())
+	})
+
+	It("Detects registered provider", func() {
+		defer func(providers []ProviderDefinition) {
+			registry = providers
+		}(GetProviders())
+
+		Register(ProviderDefinition{
+			Name: "test",
+			Resource: ResourceType{
+				Group:    "test.io",
+				Kind:     "TestCluster",
+				Versions: []string{"v1", "v1beta1"},
+			},
+			New: func(resource argo.ResourceStatus, application argo.Application) ClusterProvider {
+				return fakeProvider{name: resource.Name}
+			},
+		})
+
+		app := argo.Application{
+			Status: argo.ApplicationStatus{
+				Resources: []argo.ResourceStatus{
+					{
+						Kind:    "HostedCluster",
+						Version: "v1alpha2",
+						Group:   "hypershift.openshift.io",
+						Name:    "foo",
+					},
+					{
+						Kind:    "TestCluster",
+						Version: "v1beta1",
+						Group:   "test.io",
+						Name:    "bar",
+					},
+				},
+			},
+		}
+		Expect(GetClusterProvider(app)).Should(Equal(fakeProvider{name: "bar"}))
+	})
+})
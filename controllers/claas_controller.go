@@ -32,6 +32,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
 )
 
 var (
@@ -42,8 +43,8 @@ var (
 type CLaaSReconciler struct {
 	Manager ctrl.Manager
 	client.Client
-	enableHypershift     bool
-	enableHive           bool
+	// Names of the cluster providers whose CRDs are installed
+	enabledProviders     map[string]bool
 	enableConsolePlugin  bool
 	enableManagedCluster bool
 	enableKlusterlet     bool
@@ -65,53 +66,27 @@ func (r *CLaaSReconciler) Reconcile(
 	}
 
 	//restart controller if needed
-
-	if !r.enableHypershift && isCRDSupported(crd, v1alpha1.HostedClusterGVK) {
-		r.enableHypershift = true
-		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(
-			r.Manager,
-			r.enableHypershift,
-			r.enableHive,
-			r.enableManagedCluster,
-			r.enableKlusterlet,
-		)
-	}
-
-	if !r.enableHive && isCRDSupported(crd, v1alpha1.ClusterDeploymentGVK) {
-		r.enableHive = true
-		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(
-			r.Manager,
-			r.enableHypershift,
-			r.enableHive,
-			r.enableManagedCluster,
-			r.enableKlusterlet,
-		)
+	restart := false
+	for _, provider := range clusterprovider.GetProviders() {
+		if !r.enabledProviders[provider.Name] && isCRDSupported(crd, provider.Resource.GVR()) {
+			r.enabledProviders[provider.Name] = true
+			restart = true
+		}
 	}
 
 	if !r.enableManagedCluster && isCRDSupported(crd, v1alpha1.ManagedClusterGVK) {
 		r.enableManagedCluster = true
-		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(
-			r.Manager,
-			r.enableHypershift,
-			r.enableHive,
-			r.enableManagedCluster,
-			r.enableKlusterlet,
-		)
+		restart = true
 	}
 
 	if !r.enableKlusterlet && isCRDSupported(crd, v1alpha1.KlusterletAddonGVK) {
 		r.enableKlusterlet = true
+		restart = true
+	}
+
+	if restart {
 		ctiControllerCancel()
-		ctiControllerCancel = StartCTIController(
-			r.Manager,
-			r.enableHypershift,
-			r.enableHive,
-			r.enableManagedCluster,
-			r.enableKlusterlet,
-		)
+		ctiControllerCancel = r.startCTIController()
 	}
 
 	if !r.enableConsolePlugin && isCRDSupported(crd, v1alpha1.ConsolePluginGVK) {
@@ -132,21 +107,17 @@ func (r *CLaaSReconciler) Reconcile(
 func (r *CLaaSReconciler) SetupWithManager() error {
 	client := r.Manager.GetClient()
 	scheme := r.Manager.GetScheme()
-	r.enableHypershift = isCRDAvailable(client, v1alpha1.HostedClusterGVK)
-	r.enableHive = isCRDAvailable(client, v1alpha1.ClusterDeploymentGVK)
+	r.enabledProviders = map[string]bool{}
+	for _, provider := range clusterprovider.GetProviders() {
+		r.enabledProviders[provider.Name] = isCRDAvailable(client, provider.Resource.GVR())
+	}
 	r.enableConsolePlugin = isCRDAvailable(client, v1alpha1.ConsolePluginGVK)
 	r.enableManagedCluster = isCRDAvailable(client, v1alpha1.ManagedClusterGVK)
 	r.enableKlusterlet = isCRDAvailable(client, v1alpha1.KlusterletAddonGVK)
 
-	ctiControllerCancel = StartCTIController(
-		r.Manager,
-		r.enableHypershift,
-		r.enableHive,
-		r.enableManagedCluster,
-		r.enableKlusterlet,
-	)
+	ctiControllerCancel = r.startCTIController()
 
-	if r.enableHypershift {
+	if r.enabledProviders[clusterprovider.HostedClusterProviderName] {
 		if err := (&HypershiftTemplateReconciler{
 			Client: client,
 			Scheme: scheme,
@@ -187,6 +158,21 @@ func (r *CLaaSReconciler) SetupWithManager() error {
 		Complete(r)
 }
 
+func (r *CLaaSReconciler) startCTIController() context.CancelFunc {
+	providers := []clusterprovider.ProviderDefinition{}
+	for _, provider := range clusterprovider.GetProviders() {
+		if r.enabledProviders[provider.Name] {
+			providers = append(providers, provider)
+		}
+	}
+	return StartCTIController(
+		r.Manager,
+		providers,
+		r.enableManagedCluster,
+		r.enableKlusterlet,
+	)
+}
+
 func isCRDAvailable(client client.Client, gvk schema.GroupVersionResource) bool {
 	_, err := client.RESTMapper().KindFor(gvk)
 
@@ -10,10 +10,12 @@ import (
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/v1beta1"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ocmv1 "open-cluster-management.io/api/cluster/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/envtest"
@@ -35,8 +37,8 @@ var _ = Describe("CLaaS controller", func() {
 	})
 	It("Can create CLaaS reconciler", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -46,8 +48,8 @@ var _ = Describe("CLaaS controller", func() {
 		startTestEnv([]string{
 			filepath.Join("..", "testutils", "testcrds", "optional", "hive"),
 		})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeTrue())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeTrue())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -57,8 +59,8 @@ var _ = Describe("CLaaS controller", func() {
 		startTestEnv([]string{
 			filepath.Join("..", "testutils", "testcrds", "optional", "hypershift"),
 		})
-		Expect(claasReconciler.enableHypershift).Should(BeTrue())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeTrue())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -68,8 +70,8 @@ var _ = Describe("CLaaS controller", func() {
 		startTestEnv([]string{
 			filepath.Join("..", "testutils", "testcrds", "optional", "ocm"),
 		})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeTrue())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -79,8 +81,8 @@ var _ = Describe("CLaaS controller", func() {
 		startTestEnv([]string{
 			filepath.Join("..", "testutils", "testcrds", "optional", "acm"),
 		})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeTrue())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -90,8 +92,8 @@ var _ = Describe("CLaaS controller", func() {
 		startTestEnv([]string{
 			filepath.Join("..", "testutils", "testcrds", "optional", "console"),
 		})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeTrue())
@@ -99,8 +101,8 @@ var _ = Describe("CLaaS controller", func() {
 	})
 	It("enables hypershift dynamically", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -133,16 +135,16 @@ var _ = Describe("CLaaS controller", func() {
 		Expect(err).Should(BeNil())
 
 		Eventually(func() bool {
-			return claasReconciler.enableHypershift
+			return claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]
 		}, timeout, interval).Should(BeTrue())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
 	})
 	It("enables hive dynamically", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -175,16 +177,53 @@ var _ = Describe("CLaaS controller", func() {
 		Expect(err).Should(BeNil())
 
 		Eventually(func() bool {
-			return claasReconciler.enableHive
+			return claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]
 		}, timeout, interval).Should(BeTrue())
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
 	})
+	It("enables capi dynamically", func() {
+		startTestEnv([]string{})
+		Expect(claasReconciler.enabledProviders[clusterprovider.CAPIClusterProviderName]).Should(BeFalse())
+		Expect(ctiControllerCancel).ShouldNot(BeNil())
+
+		err := claasK8sClient.Create(claasCtx, &apiextensions.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "clusters.cluster.x-k8s.io",
+			},
+			Spec: apiextensions.CustomResourceDefinitionSpec{
+				Scope: apiextensions.NamespaceScoped,
+				Group: v1alpha1.CAPIClusterGVK.Group,
+				Versions: []apiextensions.CustomResourceDefinitionVersion{
+					{
+						Name:    v1alpha1.CAPIClusterGVK.Version,
+						Storage: true,
+						Schema: &apiextensions.CustomResourceValidation{
+							OpenAPIV3Schema: &apiextensions.JSONSchemaProps{
+								Type: "object",
+							},
+						},
+					},
+				},
+				Names: apiextensions.CustomResourceDefinitionNames{
+					Kind:   v1alpha1.CAPIClusterGVK.Resource,
+					Plural: "clusters",
+				},
+			},
+		})
+		Expect(err).Should(BeNil())
+
+		Eventually(func() bool {
+			return claasReconciler.enabledProviders[clusterprovider.CAPIClusterProviderName]
+		}, timeout, interval).Should(BeTrue())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
+	})
 	It("enables managedcluster dynamically", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -219,14 +258,14 @@ var _ = Describe("CLaaS controller", func() {
 		Eventually(func() bool {
 			return claasReconciler.enableManagedCluster
 		}, timeout, interval).Should(BeTrue())
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
 	})
 	It("enables klusterlet dynamically", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableKlusterlet).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
@@ -261,15 +300,15 @@ var _ = Describe("CLaaS controller", func() {
 		Eventually(func() bool {
 			return claasReconciler.enableKlusterlet
 		}, timeout, interval).Should(BeTrue())
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
 	})
 	It("enables console plugin dynamically", func() {
 		startTestEnv([]string{})
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
 		Expect(claasReconciler.enableConsolePlugin).Should(BeFalse())
 
@@ -302,9 +341,9 @@ var _ = Describe("CLaaS controller", func() {
 		Eventually(func() bool {
 			return claasReconciler.enableConsolePlugin
 		}, timeout, interval).Should(BeTrue())
-		Expect(claasReconciler.enableHive).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.ClusterDeploymentProviderName]).Should(BeFalse())
 		Expect(claasReconciler.enableManagedCluster).Should(BeFalse())
-		Expect(claasReconciler.enableHypershift).Should(BeFalse())
+		Expect(claasReconciler.enabledProviders[clusterprovider.HostedClusterProviderName]).Should(BeFalse())
 	})
 })
 
@@ -330,6 +369,8 @@ func startTestEnv(crds []string) {
 	Expect(err).NotTo(HaveOccurred())
 	err = hivev1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = capiv1beta1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 	err = argo.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
 	err = ocmv1.AddToScheme(scheme.Scheme)
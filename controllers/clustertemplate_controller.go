@@ -12,6 +12,9 @@ import (
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 type RepoEntry struct {
@@ -29,7 +32,7 @@ type ClusterTemplateReconciler struct {
 }
 
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;
 
 func (r *ClusterTemplateReconciler) Reconcile(
@@ -43,40 +46,134 @@ func (r *ClusterTemplateReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
-	appSet := &argo.ApplicationSet{}
-	err = r.Get(
-		ctx,
-		types.NamespacedName{Name: clusterTemplate.Spec.ClusterDefinition, Namespace: ArgoCDNamespace},
-		appSet,
-	)
+	effectiveSpec, err := v1alpha1.ResolveEffectiveSpec(ctx, r.Client, clusterTemplate)
 	if err != nil {
-		errors = multierror.Append(errors, err)
+		clusterTemplate.Status.EffectiveSpec = nil
 		clusterTemplate.Status.ClusterDefinition.Error = pointer.String(err.Error())
-	} else {
-		clusterTemplate.Status.ClusterDefinition.Error = nil
+		errors = multierror.Append(errors, err)
+		err = r.Client.Status().Update(ctx, clusterTemplate)
+		errors = multierror.Append(errors, err)
+		return ctrl.Result{}, errors.ErrorOrNil()
+	}
+	clusterTemplate.Status.EffectiveSpec = effectiveSpec
+
+	clusterDefinitionStatus, err := r.getClusterDefinitionSchema(
+		ctx,
+		clusterTemplate.Status.ClusterDefinition,
+		effectiveSpec.ClusterDefinition,
+		"",
+	)
+	errors = multierror.Append(errors, err)
+	clusterTemplate.Status.ClusterDefinition = clusterDefinitionStatus
+
+	clusterSetupStatus, err := r.getClusterSetupSchemas(ctx, effectiveSpec.ClusterSetup)
+	errors = multierror.Append(errors, err)
+	clusterTemplate.Status.ClusterSetup = clusterSetupStatus
+
+	versionsStatus := []v1alpha1.ClusterTemplateVersionStatus{}
+	for _, templateVersion := range effectiveSpec.Versions {
+		version, _ := clusterTemplate.GetVersion(templateVersion.Name)
+		versionStatus := v1alpha1.ClusterTemplateVersionStatus{
+			Name: version.Name,
+		}
+		if previous := clusterTemplate.GetVersionStatus(version.Name); previous != nil {
+			versionStatus.ClusterDefinition = previous.ClusterDefinition
+		}
 
-		cdValues, cdParams, cdSchema, err := r.getValuesParamsAndSchema(
+		versionStatus.ClusterDefinition, err = r.getClusterDefinitionSchema(
 			ctx,
-			appSet.Spec.Template.Spec,
+			versionStatus.ClusterDefinition,
+			version.ClusterDefinition,
+			version.Revision,
 		)
-		if err != nil {
-			errors = multierror.Append(errors, err)
-			clusterTemplate.Status.ClusterDefinition.Error = pointer.String(err.Error())
-		} else {
-			clusterTemplate.Status.ClusterDefinition.Values = cdValues
-			clusterTemplate.Status.ClusterDefinition.Params = cdParams
-			clusterTemplate.Status.ClusterDefinition.Schema = cdSchema
-			clusterTemplate.Status.ClusterDefinition.Error = nil
+		errors = multierror.Append(errors, err)
+
+		versionStatus.ClusterSetup, err = r.getClusterSetupSchemas(ctx, version.ClusterSetup)
+		errors = multierror.Append(errors, err)
+		versionsStatus = append(versionsStatus, versionStatus)
+	}
+	clusterTemplate.Status.Versions = versionsStatus
+
+	err = r.Client.Status().Update(ctx, clusterTemplate)
+	errors = multierror.Append(errors, err)
+	return ctrl.Result{}, errors.ErrorOrNil()
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterTemplate{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplate{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapBaseTemplateToTemplates),
+		).
+		Complete(r)
+}
+
+// Templates which are derived from the changed template have to resolve their effective spec again
+func (r *ClusterTemplateReconciler) mapBaseTemplateToTemplates(obj client.Object) []reconcile.Request {
+	templates := &v1alpha1.ClusterTemplateList{}
+	if err := r.List(context.TODO(), templates); err != nil {
+		return []reconcile.Request{}
+	}
+	requests := []reconcile.Request{}
+	for _, template := range templates.Items {
+		if template.Spec.BaseTemplate == obj.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: template.Name},
+			})
 		}
 	}
+	return requests
+}
+
+// Returns values, params and schema of the cluster definition. If the revision is not empty,
+// it overrides the targetRevision of the applicationset.
+func (r *ClusterTemplateReconciler) getClusterDefinitionSchema(
+	ctx context.Context,
+	clusterDefinitionStatus v1alpha1.ClusterDefinitionSchema,
+	clusterDefinition string,
+	revision string,
+) (v1alpha1.ClusterDefinitionSchema, error) {
+	appSet := &argo.ApplicationSet{}
+	err := r.Get(
+		ctx,
+		types.NamespacedName{Name: clusterDefinition, Namespace: ArgoCDNamespace},
+		appSet,
+	)
+	if err != nil {
+		clusterDefinitionStatus.Error = pointer.String(err.Error())
+		return clusterDefinitionStatus, err
+	}
+
+	appSpec := appSet.Spec.Template.Spec
+	if revision != "" {
+		appSpec.Source.TargetRevision = revision
+	}
+	cdValues, cdParams, cdSchema, err := r.getValuesParamsAndSchema(ctx, appSpec)
+	if err != nil {
+		clusterDefinitionStatus.Error = pointer.String(err.Error())
+		return clusterDefinitionStatus, err
+	}
+	clusterDefinitionStatus.Values = cdValues
+	clusterDefinitionStatus.Params = cdParams
+	clusterDefinitionStatus.Schema = cdSchema
+	clusterDefinitionStatus.Error = nil
+	return clusterDefinitionStatus, nil
+}
 
+func (r *ClusterTemplateReconciler) getClusterSetupSchemas(
+	ctx context.Context,
+	clusterSetup []string,
+) ([]v1alpha1.ClusterSetupSchema, error) {
+	var errors *multierror.Error
 	clusterSetupStatus := []v1alpha1.ClusterSetupSchema{}
-	for _, setup := range clusterTemplate.Spec.ClusterSetup {
+	for _, setup := range clusterSetup {
 		css := v1alpha1.ClusterSetupSchema{}
 		css.Name = setup
 
 		appSet := &argo.ApplicationSet{}
-		err = r.Get(
+		err := r.Get(
 			ctx,
 			types.NamespacedName{Name: setup, Namespace: ArgoCDNamespace},
 			appSet,
@@ -104,18 +201,7 @@ func (r *ClusterTemplateReconciler) Reconcile(
 		}
 		clusterSetupStatus = append(clusterSetupStatus, css)
 	}
-	clusterTemplate.Status.ClusterSetup = clusterSetupStatus
-
-	err = r.Client.Status().Update(ctx, clusterTemplate)
-	errors = multierror.Append(errors, err)
-	return ctrl.Result{}, errors.ErrorOrNil()
-}
-
-// SetupWithManager sets up the controller with the Manager.
-func (r *ClusterTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.ClusterTemplate{}).
-		Complete(r)
+	return clusterSetupStatus, errors.ErrorOrNil()
 }
 
 func (r *ClusterTemplateReconciler) getValuesParamsAndSchema(
@@ -226,4 +226,67 @@ var _ = Describe("ClusterTemplate controller", func() {
 		}, timeout, interval).Should(BeTrue())
 	})
 
+	It("Should resolve effective spec of derived template", func() {
+		cost := 2
+		ct.Spec.ClusterSetup = []string{"foo"}
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+		derived := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-derived",
+			},
+			Spec: v1alpha1.ClusterTemplateSpec{
+				BaseTemplate: ct.Name,
+				Cost:         &cost,
+				Parameters: []v1alpha1.ClusterTemplateParameter{{
+					Name:  "bar",
+					Value: "qux",
+				}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, derived)).Should(Succeed())
+		defer testutils.DeleteResource(ctx, derived, k8sClient)
+
+		Eventually(func() bool {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(derived), foundCT)
+			if err != nil || foundCT.Status.EffectiveSpec == nil {
+				return false
+			}
+
+			spec := foundCT.Status.EffectiveSpec
+			return spec.ClusterDefinition == "foo" &&
+				len(spec.ClusterSetup) == 1 &&
+				*spec.Cost == cost &&
+				len(spec.Parameters) == 1 &&
+				len(foundCT.Status.ClusterDefinition.Values) > 0
+		}, timeout, interval).Should(BeTrue())
+	})
+
+	It("Should set error for ClusterDefinition in case of base template cycle", func() {
+		ct.Spec.BaseTemplate = "foo-derived"
+		Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+		derived := &v1alpha1.ClusterTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo-derived",
+			},
+			Spec: v1alpha1.ClusterTemplateSpec{
+				BaseTemplate: ct.Name,
+			},
+		}
+		Expect(k8sClient.Create(ctx, derived)).Should(Succeed())
+		defer testutils.DeleteResource(ctx, derived, k8sClient)
+
+		Eventually(func() bool {
+			foundCT := &v1alpha1.ClusterTemplate{}
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(derived), foundCT)
+			if err != nil {
+				return false
+			}
+
+			if foundCT.Status.ClusterDefinition.Error != nil {
+				return strings.Contains(*foundCT.Status.ClusterDefinition.Error, "cycle detected")
+			}
+			return false
+		}, timeout, interval).Should(BeTrue())
+	})
 })
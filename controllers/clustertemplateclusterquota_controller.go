@@ -0,0 +1,219 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+// ClusterTemplateClusterQuotaReconciler reconciles a ClusterTemplateClusterQuota object
+type ClusterTemplateClusterQuotaReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatequotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+
+func (r *ClusterTemplateClusterQuotaReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	quota := &v1alpha1.ClusterTemplateClusterQuota{}
+	if err := r.Get(ctx, req.NamespacedName, quota); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&quota.Spec.NamespaceSelector)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("invalid namespace selector - %q", err)
+	}
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	clusterTemplateList := &v1alpha1.ClusterTemplateList{}
+	if err := r.List(ctx, clusterTemplateList); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := v1alpha1.ClusterTemplateClusterQuotaStatus{
+		TemplateInstances: []v1alpha1.AllowedTemplateStatus{},
+		Namespaces:        []v1alpha1.ClusterQuotaNamespaceStatus{},
+	}
+	for _, template := range quota.Spec.AllowedTemplates {
+		status.TemplateInstances = append(status.TemplateInstances, v1alpha1.AllowedTemplateStatus{
+			Name: template.Name,
+		})
+	}
+
+	selectedInstances := []v1alpha1.ClusterTemplateInstance{}
+	for _, namespace := range namespaces.Items {
+		instances, err := r.getStartedInstances(ctx, namespace.Name)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		selectedInstances = append(selectedInstances, instances...)
+
+		namespaceStatus := v1alpha1.ClusterQuotaNamespaceStatus{
+			Namespace:         namespace.Name,
+			TemplateInstances: []v1alpha1.AllowedTemplateStatus{},
+		}
+		for i, template := range quota.Spec.AllowedTemplates {
+			count := 0
+			templateCost := getTemplateCost(clusterTemplateList.Items, template.Name)
+			for _, instance := range instances {
+				if instance.Spec.ClusterTemplateRef == template.Name {
+					count++
+					namespaceStatus.BudgetSpent += getInstanceCost(&instance, templateCost)
+				}
+			}
+			namespaceStatus.TemplateInstances = append(
+				namespaceStatus.TemplateInstances,
+				v1alpha1.AllowedTemplateStatus{
+					Name:  template.Name,
+					Count: count,
+				},
+			)
+			status.TemplateInstances[i].Count += count
+		}
+		status.BudgetSpent += namespaceStatus.BudgetSpent
+		status.Namespaces = append(status.Namespaces, namespaceStatus)
+	}
+
+	reservations, requeueAfter := getPendingReservations(quota.Status.Reservations, selectedInstances)
+	status.Reservations = reservations
+
+	quota.Status = status
+	if err := r.Status().Update(ctx, quota); err != nil {
+		return ctrl.Result{}, fmt.Errorf(
+			"failed to update status of clustertemplateclusterquota %q: %w",
+			req.NamespacedName,
+			err,
+		)
+	}
+
+	if requeueAfter != nil {
+		return ctrl.Result{RequeueAfter: *requeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// Returns the instances of the namespace which are not queued by its quotas, queued instances
+// are counted once they are started
+func (r *ClusterTemplateClusterQuotaReconciler) getStartedInstances(
+	ctx context.Context,
+	namespace string,
+) ([]v1alpha1.ClusterTemplateInstance, error) {
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.List(ctx, instances, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	quotas := &v1alpha1.ClusterTemplateQuotaList{}
+	if err := r.List(ctx, quotas, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	started := []v1alpha1.ClusterTemplateInstance{}
+	for _, instance := range instances.Items {
+		queued := false
+		for _, quota := range quotas.Items {
+			if quota.IsQueued(instance.Namespace, instance.Name) {
+				queued = true
+				break
+			}
+		}
+		if !queued {
+			started = append(started, instance)
+		}
+	}
+	return started, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplateClusterQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	mapNamespaceToQuotas := func(namespace client.Object) []reconcile.Request {
+		quotas := &v1alpha1.ClusterTemplateClusterQuotaList{}
+		if err := r.List(context.Background(), quotas); err != nil {
+			return []reconcile.Request{}
+		}
+
+		reply := make([]reconcile.Request, 0, len(quotas.Items))
+		for _, quota := range quotas.Items {
+			reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name: quota.Name,
+			}})
+		}
+		return reply
+	}
+
+	mapInstanceToQuotas := func(instance client.Object) []reconcile.Request {
+		namespace := &corev1.Namespace{}
+		if err := r.Get(
+			context.Background(),
+			client.ObjectKey{Name: instance.GetNamespace()},
+			namespace,
+		); err != nil {
+			return []reconcile.Request{}
+		}
+		quotas := &v1alpha1.ClusterTemplateClusterQuotaList{}
+		if err := r.List(context.Background(), quotas); err != nil {
+			return []reconcile.Request{}
+		}
+
+		reply := []reconcile.Request{}
+		for _, quota := range quotas.Items {
+			if selected, err := quota.SelectsNamespace(namespace); err == nil && selected {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name: quota.Name,
+				}})
+			}
+		}
+		return reply
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterTemplateClusterQuota{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplateInstance{}},
+			handler.EnqueueRequestsFromMapFunc(mapInstanceToQuotas)).
+		Watches(
+			&source.Kind{Type: &corev1.Namespace{}},
+			handler.EnqueueRequestsFromMapFunc(mapNamespaceToQuotas)).
+		Complete(r)
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("ClusterTemplateClusterQuota controller", func() {
+	Context("ClusterTemplateClusterQuota Status", func() {
+		ct := &v1alpha1.ClusterTemplate{}
+		ctcq := &v1alpha1.ClusterTemplateClusterQuota{}
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		appset := &argo.ApplicationSet{}
+		cost := 2
+
+		BeforeEach(func() {
+			ct = testutils.GetCTWithCost(false, &cost, false)
+			Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+
+			appset = testutils.GetAppset()
+			Expect(k8sClient.Create(ctx, appset)).Should(Succeed())
+
+			cti = testutils.GetCTI()
+
+			ctcq = &v1alpha1.ClusterTemplateClusterQuota{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "team-quota",
+				},
+				Spec: v1alpha1.ClusterTemplateClusterQuotaSpec{
+					NamespaceSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"kubernetes.io/metadata.name": cti.Namespace,
+						},
+					},
+					AllowedTemplates: []v1alpha1.ClusterQuotaAllowedTemplate{
+						{
+							Name: ct.Name,
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ctcq)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			testutils.DeleteResource(ctx, ctcq, k8sClient)
+			testutils.DeleteResource(ctx, cti, k8sClient)
+			testutils.DeleteResource(ctx, ct, k8sClient)
+			testutils.DeleteResource(ctx, appset, k8sClient)
+		})
+
+		It("Should aggregate instances of the selected namespaces", func() {
+			Expect(k8sClient.Create(ctx, cti)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ctcq), ctcq)
+				if err != nil {
+					return false
+				}
+				return ctcq.Status.BudgetSpent == cost
+			}, timeout, interval).Should(BeTrue())
+			Expect(ctcq.Status.TemplateInstances).Should(Equal([]v1alpha1.AllowedTemplateStatus{
+				{
+					Name:  ct.Name,
+					Count: 1,
+				},
+			}))
+			Expect(len(ctcq.Status.Namespaces)).Should(Equal(1))
+			Expect(ctcq.Status.Namespaces[0].Namespace).Should(Equal(cti.Namespace))
+			Expect(ctcq.Status.Namespaces[0].BudgetSpent).Should(Equal(cost))
+		})
+	})
+})
@@ -20,9 +20,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/kubernetes-client/go-base/config/api"
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,29 +39,41 @@ import (
 
 	"github.com/stolostron/cluster-templates-operator/clusterprovider"
 	"github.com/stolostron/cluster-templates-operator/clustersetup"
+	"github.com/stolostron/cluster-templates-operator/metrics"
 	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
-	hivev1 "github.com/openshift/hive/apis/hive/v1"
-	hypershiftv1beta1 "github.com/openshift/hypershift/api/v1beta1"
 	agent "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ocmv1 "open-cluster-management.io/api/cluster/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var (
 	CTIlog = logf.Log.WithName("cti-controller")
+	// Interval of checking the progress of hibernation or resume of a cluster
+	powerStateRequeueInterval = 30 * time.Second
+	// Interval of checking the status of clusters of the generic cluster provider, their resources are
+	// not watched
+	genericProviderRequeueInterval = 30 * time.Second
+	// Interval of checking the progress of the cleanup of the cluster provider during deletion
+	clusterDeletionRequeueInterval = 10 * time.Second
+	// Interval of refreshing the cluster info of ready instances
+	clusterInfoRefreshInterval = 10 * time.Minute
 )
 
 type realClock struct{}
@@ -71,11 +86,16 @@ type Clock interface {
 
 type ClusterTemplateInstanceReconciler struct {
 	client.Client
-	Scheme               *runtime.Scheme
-	EnableHypershift     bool
-	EnableHive           bool
+	Scheme *runtime.Scheme
+	// Cluster providers whose CRDs are installed, the resources of the providers are watched
+	Providers            []clusterprovider.ProviderDefinition
 	EnableManagedCluster bool
 	EnableKlusterlet     bool
+	// Uncached reader of the quotas. The admission webhook queues the instance right before it is
+	// created, so the cached quotas may not include the instance yet.
+	APIReader client.Reader
+	// Records events of the instance, the events are not recorded if nil
+	Recorder record.EventRecorder
 	Clock
 }
 
@@ -86,17 +106,31 @@ type ClusterTemplateInstanceReconciler struct {
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatesetup,verbs=get;list;watch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatequotas,verbs=get;list;watch
-// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedclusters;nodepools,verbs=get;list;watch
-// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterclaims;clusterdeployments,verbs=get;list;watch
-// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatequotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstanceapprovals,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatenotifications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatenotifications/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=hostedclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hypershift.openshift.io,resources=nodepools,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterclaims,verbs=get;list;watch
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=clusterdeployments,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=hive.openshift.io,resources=machinepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinedeployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings;roles,verbs=get;list;watch;create;update;delete
-// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclustersets/join,verbs=create
 // +kubebuilder:rbac:groups=register.open-cluster-management.io,resources=managedclusters/accept,verbs=update
 // +kubebuilder:rbac:groups=agent.open-cluster-management.io,resources=klusterletaddonconfigs,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 func (r *ClusterTemplateInstanceReconciler) Reconcile(
 	ctx context.Context,
@@ -118,40 +152,105 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 	if len(clusterTemplateInstance.Status.Conditions) == 0 {
 		clusterTemplateInstance.Status.Phase = v1alpha1.PendingPhase
 		clusterTemplateInstance.Status.Message = v1alpha1.PendingMessage
+		clusterTemplateInstance.RecordPhase("", clusterTemplateInstance.CreationTimestamp)
 	}
 	clusterTemplateInstance.SetDefaultConditions()
+	previousStatus := clusterTemplateInstance.Status.DeepCopy()
 
 	if clusterTemplateInstance.GetDeletionTimestamp() != nil {
 		return r.delete(ctx, clusterTemplateInstance)
 	}
 
+	// Pooled cluster is handed over to the claiming instance, the pool removes the instance afterwards
+	if _, claimed := clusterTemplateInstance.Annotations[v1alpha1.CTIPoolClaimAnnotation]; claimed {
+		return ctrl.Result{}, nil
+	}
+
 	// Check if CTI should be auto-removed:
 	requeueAfter, err := r.autoDelete(ctx, clusterTemplateInstance)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	var clusterTemplate client.Object
-	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
-		clusterTemplate = &v1alpha1.ClusterTemplateSetup{}
-	} else {
-		clusterTemplate = &v1alpha1.ClusterTemplate{}
+	// Check if the power schedule requests a power state change:
+	powerScheduleRequeue, err := r.schedulePowerState(ctx, clusterTemplateInstance)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	if err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, clusterTemplate); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.FailedPhase
-		clusterTemplateInstance.Status.Message = fmt.Sprintf("failed to fetch ClusterTemplate - %q", err)
-		if updErr := r.Status().Update(ctx, clusterTemplateInstance); updErr != nil {
-			return ctrl.Result{}, fmt.Errorf(
-				"failed to update status of clustertemplateinstance %q: %w",
-				req.NamespacedName,
-				updErr,
+	if powerScheduleRequeue != nil && (requeueAfter == nil || *powerScheduleRequeue < *requeueAfter) {
+		requeueAfter = powerScheduleRequeue
+	}
+
+	// Instances queued by a quota are not provisioned until the quota starts them
+	if clusterTemplateInstance.Status.TemplateSnapshot == nil {
+		queuedBy, err := r.getQueuingQuota(ctx, clusterTemplateInstance)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to check quota queues - %q", err)
+		}
+		if queuedBy != "" {
+			return r.waitForProvisioning(
+				ctx,
+				clusterTemplateInstance,
+				v1alpha1.QueuedPhase,
+				fmt.Sprintf("Waiting in the queue of quota %q", queuedBy),
+				requeueAfter,
 			)
 		}
+	}
 
-		return ctrl.Result{}, err
+	// Instances of templates which require approval are not provisioned until they are approved
+	if clusterTemplateInstance.Status.TemplateSnapshot == nil && clusterTemplateInstance.Status.Approval == nil {
+		approved, err := r.checkApproval(ctx, clusterTemplateInstance)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to check approval - %q", err)
+		}
+		if !approved {
+			return r.waitForProvisioning(
+				ctx,
+				clusterTemplateInstance,
+				v1alpha1.PendingApprovalPhase,
+				"Waiting for approval",
+				requeueAfter,
+			)
+		}
+	}
+
+	// Adopt a pre-provisioned cluster of a pool, the template snapshot of the pooled instance is taken over
+	if clusterTemplateInstance.Status.TemplateSnapshot == nil {
+		if err := r.claimPooledCluster(ctx, clusterTemplateInstance); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to claim pooled cluster - %q", err)
+		}
+	}
+
+	// The template is resolved only once, later changes of the template don't affect the instance
+	// unless the rebase is explicitly requested
+	_, rebase := clusterTemplateInstance.Annotations[v1alpha1.CTIRebaseAnnotation]
+	if clusterTemplateInstance.Status.TemplateSnapshot == nil || rebase {
+		if err := r.snapshotTemplate(ctx, clusterTemplateInstance); err != nil {
+			clusterTemplateInstance.Status.Phase = v1alpha1.FailedPhase
+			clusterTemplateInstance.Status.Message = fmt.Sprintf("failed to fetch ClusterTemplate - %q", err)
+			r.recordPhase(clusterTemplateInstance, previousStatus)
+			if updErr := r.Status().Update(ctx, clusterTemplateInstance); updErr != nil {
+				return ctrl.Result{}, fmt.Errorf(
+					"failed to update status of clustertemplateinstance %q: %w",
+					req.NamespacedName,
+					updErr,
+				)
+			}
+
+			return ctrl.Result{}, err
+		}
 	}
 
-	err = r.reconcile(ctx, clusterTemplateInstance, clusterTemplate)
+	err = r.reconcile(ctx, clusterTemplateInstance, clusterTemplateInstance.Status.TemplateSnapshot)
+	if err == nil {
+		clusterInfoRequeue := r.reconcileClusterInfo(ctx, clusterTemplateInstance)
+		if clusterInfoRequeue != nil && (requeueAfter == nil || *clusterInfoRequeue < *requeueAfter) {
+			requeueAfter = clusterInfoRequeue
+		}
+	}
+	r.recordPhase(clusterTemplateInstance, previousStatus)
+	notifyErr := r.notifyPhaseChange(ctx, clusterTemplateInstance, previousStatus.Phase)
 	if updErr := r.Status().Update(ctx, clusterTemplateInstance); updErr != nil {
 		return ctrl.Result{}, fmt.Errorf(
 			"failed to update status of clustertemplateinstance %q: %w",
@@ -159,10 +258,37 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 			updErr,
 		)
 	}
+	r.recordStatusEvents(clusterTemplateInstance, previousStatus)
+	if notifyErr != nil && err == nil {
+		err = fmt.Errorf("failed to notify phase change - %q", notifyErr)
+	}
+	metrics.ObservePhase(clusterTemplateInstance, r.Now())
+	metrics.ObserveProvisioning(clusterTemplateInstance, previousStatus.Phase, r.Now())
+	if rebase {
+		delete(clusterTemplateInstance.Annotations, v1alpha1.CTIRebaseAnnotation)
+		if updErr := r.Update(ctx, clusterTemplateInstance); updErr != nil {
+			return ctrl.Result{}, updErr
+		}
+	}
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Cluster providers don't report every step of the power state transition
+	phase := clusterTemplateInstance.Status.Phase
+	if phase == v1alpha1.HibernatingPhase || phase == v1alpha1.ResumingPhase {
+		if requeueAfter == nil || *requeueAfter > powerStateRequeueInterval {
+			requeueAfter = &powerStateRequeueInterval
+		}
+	}
+
+	if snapshot := clusterTemplateInstance.Status.TemplateSnapshot; snapshot != nil &&
+		snapshot.ClusterProvider != nil && phase == v1alpha1.ClusterInstallingPhase {
+		if requeueAfter == nil || *requeueAfter > genericProviderRequeueInterval {
+			requeueAfter = &genericProviderRequeueInterval
+		}
+	}
+
 	if requeueAfter != nil {
 		return ctrl.Result{RequeueAfter: *requeueAfter}, nil
 	}
@@ -170,44 +296,383 @@ func (r *ClusterTemplateInstanceReconciler) Reconcile(
 	return ctrl.Result{}, nil
 }
 
+// Records the reason why the instance is not provisioned yet
+func (r *ClusterTemplateInstanceReconciler) waitForProvisioning(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	phase v1alpha1.Phase,
+	message string,
+	requeueAfter *time.Duration,
+) (ctrl.Result, error) {
+	previousPhase := cti.Status.Phase
+	cti.Status.Phase = phase
+	cti.Status.Message = message
+	cti.RecordPhase("", metav1.NewTime(r.Now()))
+	if err := r.Status().Update(ctx, cti); err != nil {
+		return ctrl.Result{}, fmt.Errorf(
+			"failed to update status of clustertemplateinstance %q: %w",
+			client.ObjectKeyFromObject(cti),
+			err,
+		)
+	}
+	if previousPhase != phase {
+		r.recordEvent(cti, corev1.EventTypeNormal, string(phase), message)
+	}
+	metrics.ObservePhase(cti, r.Now())
+	if requeueAfter != nil {
+		return ctrl.Result{RequeueAfter: *requeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// Records an event for every condition of the instance which changed its status or reason and
+// for the change of the phase
+func (r *ClusterTemplateInstanceReconciler) recordStatusEvents(
+	cti *v1alpha1.ClusterTemplateInstance,
+	previousStatus *v1alpha1.ClusterTemplateInstanceStatus,
+) {
+	for _, condition := range getChangedConditions(cti, previousStatus) {
+		eventType := corev1.EventTypeNormal
+		if condition.Status != metav1.ConditionTrue && isFailureReason(condition.Reason) {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recordEvent(
+			cti,
+			eventType,
+			condition.Reason,
+			fmt.Sprintf("%s: %s", condition.Type, condition.Message),
+		)
+	}
+
+	if cti.Status.Phase != previousStatus.Phase {
+		eventType := corev1.EventTypeNormal
+		if isFailureReason(string(cti.Status.Phase)) {
+			eventType = corev1.EventTypeWarning
+		}
+		r.recordEvent(cti, eventType, string(cti.Status.Phase), cti.Status.Message)
+	}
+}
+
+// Returns the conditions of the instance which changed their status or reason
+func getChangedConditions(
+	cti *v1alpha1.ClusterTemplateInstance,
+	previousStatus *v1alpha1.ClusterTemplateInstanceStatus,
+) []metav1.Condition {
+	changed := []metav1.Condition{}
+	for _, condition := range cti.Status.Conditions {
+		previous := meta.FindStatusCondition(previousStatus.Conditions, condition.Type)
+		if previous != nil && previous.Status == condition.Status && previous.Reason == condition.Reason {
+			continue
+		}
+		changed = append(changed, condition)
+	}
+	return changed
+}
+
+// Records the phase in the history of the instance. The reason of the latest provisioning step
+// which changed is recorded with the phase.
+func (r *ClusterTemplateInstanceReconciler) recordPhase(
+	cti *v1alpha1.ClusterTemplateInstance,
+	previousStatus *v1alpha1.ClusterTemplateInstanceStatus,
+) {
+	reason := ""
+	if changed := getChangedConditions(cti, previousStatus); len(changed) > 0 {
+		reason = changed[len(changed)-1].Reason
+	}
+	cti.RecordPhase(reason, metav1.NewTime(r.Now()))
+}
+
+func (r *ClusterTemplateInstanceReconciler) recordEvent(
+	cti *v1alpha1.ClusterTemplateInstance,
+	eventType string,
+	reason string,
+	message string,
+) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(cti, eventType, reason, message)
+}
+
+// Adds the Ready and Failed notifications of the changed phase. The phase is recorded as notified
+// once the notifications were added, otherwise they are retried by the next reconcile.
+func (r *ClusterTemplateInstanceReconciler) notifyPhaseChange(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	previousPhase v1alpha1.Phase,
+) error {
+	notifiedPhase := cti.Status.NotifiedPhase
+	if notifiedPhase == "" {
+		// Instances which were reconciled before the notified phase was recorded
+		notifiedPhase = previousPhase
+	}
+	if cti.Status.Phase == notifiedPhase {
+		cti.Status.NotifiedPhase = cti.Status.Phase
+		return nil
+	}
+	var event v1alpha1.NotificationEvent
+	switch {
+	case cti.Status.Phase == v1alpha1.ReadyPhase:
+		event = v1alpha1.ReadyNotificationEvent
+	case strings.HasSuffix(string(cti.Status.Phase), "Failed"):
+		event = v1alpha1.FailedNotificationEvent
+	}
+	if event != "" {
+		if _, err := r.notify(ctx, cti, event, nil); err != nil {
+			return err
+		}
+	}
+	cti.Status.NotifiedPhase = cti.Status.Phase
+	return nil
+}
+
+// Adds the Expiring notifications which are due and returns the time until the next one is due
+func (r *ClusterTemplateInstanceReconciler) notifyExpiration(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	expiresAt *metav1.Time,
+) (*time.Duration, error) {
+	return r.notify(ctx, cti, v1alpha1.ExpiringNotificationEvent, expiresAt)
+}
+
+// Adds a pending delivery of the event to the notifications in the namespace of the instance. The
+// Expiring event is added only once it is due, the time until the next due notification is
+// returned.
+func (r *ClusterTemplateInstanceReconciler) notify(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+	event v1alpha1.NotificationEvent,
+	expiresAt *metav1.Time,
+) (*time.Duration, error) {
+	notifications := &v1alpha1.ClusterTemplateNotificationList{}
+	if err := r.List(ctx, notifications, client.InNamespace(cti.Namespace)); err != nil {
+		return nil, err
+	}
+	now := r.Now()
+	var notifyAfter *time.Duration
+	for i := range notifications.Items {
+		ctn := &notifications.Items[i]
+		if !ctn.Notifies(event) {
+			continue
+		}
+		if expiresAt != nil {
+			if wait := expiresAt.Add(-ctn.GetExpiringBefore()).Sub(now); wait > 0 {
+				if notifyAfter == nil || wait < *notifyAfter {
+					notifyAfter = &wait
+				}
+				continue
+			}
+		}
+		delivery := v1alpha1.NotificationDelivery{
+			Instance:  cti.Name,
+			Template:  cti.Spec.ClusterTemplateRef,
+			Event:     event,
+			Phase:     cti.Status.Phase,
+			Message:   cti.Status.Message,
+			ExpiresAt: expiresAt,
+			CreatedAt: metav1.NewTime(now),
+		}
+		if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(ctn), ctn); err != nil {
+				return err
+			}
+			if !ctn.AddDelivery(delivery) {
+				return nil
+			}
+			CTIlog.Info("Notify CTI event", "name", cti.Name, "event", event, "notification", ctn.Name)
+			return r.Status().Update(ctx, ctn)
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return notifyAfter, nil
+}
+
+// Returns true if the condition reason or the phase reports a failure of the cluster
+func isFailureReason(reason string) bool {
+	return strings.HasSuffix(reason, "Failed") ||
+		strings.HasSuffix(reason, "Error") ||
+		strings.HasSuffix(reason, "Degraded")
+}
+
+// Returns true if the instance does not require approval or if it was approved. The approval is
+// recorded in the status of the instance.
+func (r *ClusterTemplateInstanceReconciler) checkApproval(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (bool, error) {
+	requiresApproval, err := cti.RequiresApproval(ctx, r.Client)
+	if err != nil {
+		return false, err
+	}
+	if !requiresApproval {
+		return true, nil
+	}
+	approval, err := cti.GetApproval(ctx, r.Client)
+	if err != nil || approval == nil {
+		return false, err
+	}
+	CTIlog.Info("CTI approved", "name", cti.Name, "approver", approval.Spec.Approver)
+	cti.Status.Approval = &v1alpha1.InstanceApproval{
+		ApprovedBy: approval.Spec.Approver,
+		ApprovedAt: approval.CreationTimestamp,
+	}
+	return true, nil
+}
+
+// Returns the name of the quota which keeps the instance in its queue, empty if the instance is
+// not queued
+func (r *ClusterTemplateInstanceReconciler) getQueuingQuota(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (string, error) {
+	var reader client.Reader = r.Client
+	if r.APIReader != nil {
+		reader = r.APIReader
+	}
+	quotas := &v1alpha1.ClusterTemplateQuotaList{}
+	if err := reader.List(ctx, quotas, client.InNamespace(cti.Namespace)); err != nil {
+		return "", err
+	}
+	for _, quota := range quotas.Items {
+		if quota.IsQueued(cti.Namespace, cti.Name) {
+			return quota.Name, nil
+		}
+	}
+	return "", nil
+}
+
 // Return the amount of time the reconcile should re-queued to check the delete time,
 // if time already passed remove the CTI.
 func (r *ClusterTemplateInstanceReconciler) autoDelete(ctx context.Context, cti *v1alpha1.ClusterTemplateInstance) (*time.Duration, error) {
-	ctqList := &v1alpha1.ClusterTemplateQuotaList{}
-	if err := r.List(ctx, ctqList); err != nil {
+	deleteAfter, maxLifetime, err := cti.GetLifetimeLimits(ctx, r.Client)
+	if err != nil {
 		return nil, err
 	}
 
-	var deleteAfter *time.Duration
-	for _, ctq := range ctqList.Items {
-		for _, allowedTemplate := range ctq.Spec.AllowedTemplates {
-			if cti.Spec.ClusterTemplateRef == allowedTemplate.Name {
-				if allowedTemplate.DeleteAfter != nil {
-					deleteAfter = &allowedTemplate.DeleteAfter.Duration
-				}
-				break
-			}
+	if extension, ok := cti.Annotations[v1alpha1.CTIExtendAnnotation]; ok {
+		if duration, err := time.ParseDuration(extension); err == nil && duration > 0 {
+			cti.ExtendLifetime(duration, deleteAfter, maxLifetime)
+			CTIlog.Info("Extend lifetime of CTI", "name", cti.Name, "extension", cti.Status.LifetimeExtension)
+		}
+		// Record the extension before the extension request is removed
+		cti.Status.ExpiresAt = cti.GetExpiresAt(deleteAfter, maxLifetime)
+		if err := r.Status().Update(ctx, cti); err != nil {
+			return nil, err
+		}
+		delete(cti.Annotations, v1alpha1.CTIExtendAnnotation)
+		if err := r.Update(ctx, cti); err != nil {
+			return nil, err
 		}
 	}
-	if deleteAfter == nil {
+
+	expiresAt := cti.GetExpiresAt(deleteAfter, maxLifetime)
+	cti.Status.ExpiresAt = expiresAt
+	if expiresAt == nil {
 		return nil, nil
 	}
 
+	notifyAfter, err := r.notifyExpiration(ctx, cti, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to notify expiration - %q", err)
+	}
+
 	now := r.Now()
-	createTimestamp := cti.CreationTimestamp
-	if now.After(createTimestamp.Add(*deleteAfter)) {
+	if !now.Before(expiresAt.Time) {
 		CTIlog.Info("Removing CTI as time to live expired", "name", cti.Name)
 		if err := r.Delete(ctx, cti); err != nil {
 			return nil, err
 		}
+		r.recordEvent(
+			cti,
+			corev1.EventTypeNormal,
+			"Expired",
+			fmt.Sprintf("Removing the instance as its lifetime expired at %s", expiresAt.UTC().Format(time.RFC3339)),
+		)
 	} else {
-		requeueAfter := createTimestamp.Add(*deleteAfter).Sub(now)
+		requeueAfter := expiresAt.Sub(now)
+		if notifyAfter != nil && *notifyAfter < requeueAfter {
+			requeueAfter = *notifyAfter
+		}
 		return &requeueAfter, nil
 	}
 
 	return nil, nil
 }
 
+// Return the amount of time the reconcile should re-queued to apply the next transition of the power schedule,
+// if the transition time already passed update the desired power state of the CTI.
+func (r *ClusterTemplateInstanceReconciler) schedulePowerState(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (*time.Duration, error) {
+	schedule, err := r.getPowerSchedule(ctx, cti)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		cti.Status.NextPowerStateTransition = nil
+		return nil, nil
+	}
+
+	now := r.Now()
+	transition := cti.Status.NextPowerStateTransition
+	if transition != nil && !now.Before(transition.Time.Time) && cti.GetPowerState() != transition.PowerState {
+		CTIlog.Info(
+			"Change power state as requested by power schedule",
+			"name",
+			cti.Namespace+"/"+cti.Name,
+			"powerState",
+			transition.PowerState,
+		)
+		status := cti.Status.DeepCopy()
+		cti.Spec.PowerState = transition.PowerState
+		if err := r.Update(ctx, cti); err != nil {
+			return nil, err
+		}
+		cti.Status = *status
+	}
+
+	next, err := schedule.NextTransition(now)
+	if err != nil {
+		// Invalid schedule of a quota should not block the instance
+		CTIlog.Error(err, "Failed to compute next power state transition", "name", cti.Namespace+"/"+cti.Name)
+		cti.Status.NextPowerStateTransition = nil
+		return nil, nil
+	}
+	cti.Status.NextPowerStateTransition = next
+	if next == nil {
+		return nil, nil
+	}
+	requeueAfter := next.Time.Sub(now)
+	return &requeueAfter, nil
+}
+
+// Power schedule of the instance takes precedence over the schedule defined by the quota
+func (r *ClusterTemplateInstanceReconciler) getPowerSchedule(
+	ctx context.Context,
+	cti *v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.PowerSchedule, error) {
+	if cti.Spec.KubeconfigSecretRef != nil {
+		return nil, nil
+	}
+	if cti.Spec.PowerSchedule != nil {
+		return cti.Spec.PowerSchedule, nil
+	}
+	ctqList := &v1alpha1.ClusterTemplateQuotaList{}
+	if err := r.List(ctx, ctqList, client.InNamespace(cti.Namespace)); err != nil {
+		return nil, err
+	}
+	for _, ctq := range ctqList.Items {
+		for _, allowedTemplate := range ctq.Spec.AllowedTemplates {
+			if cti.Spec.ClusterTemplateRef == allowedTemplate.Name && allowedTemplate.PowerSchedule != nil {
+				return allowedTemplate.PowerSchedule, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
 func (r *ClusterTemplateInstanceReconciler) delete(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
@@ -218,32 +683,26 @@ func (r *ClusterTemplateInstanceReconciler) delete(
 	) {
 		return ctrl.Result{}, nil
 	}
-	var clusterTemplate client.Object
-	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
-		clusterTemplate = &v1alpha1.ClusterTemplateSetup{}
-	} else {
-		clusterTemplate = &v1alpha1.ClusterTemplate{}
+	deleted, err := r.deleteCluster(ctx, clusterTemplateInstance)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-	if err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, clusterTemplate); err != nil {
-		if !apierrors.IsNotFound(err) {
-			return ctrl.Result{}, err
-		}
-	} else {
-		if ct, ok := clusterTemplate.(*v1alpha1.ClusterTemplate); ok {
-			err := clusterTemplateInstance.DeleteDay1Application(ctx, r.Client, ArgoCDNamespace, ct.Spec.ClusterDefinition)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-			err = clusterTemplateInstance.DeleteDay2Application(ctx, r.Client, ArgoCDNamespace, ct.Spec.ClusterSetup)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-		} else {
-			err = clusterTemplateInstance.DeleteDay2Application(ctx, r.Client, ArgoCDNamespace, clusterTemplate.(*v1alpha1.ClusterTemplateSetup).Spec.ClusterSetup)
+	if !deleted {
+		return ctrl.Result{RequeueAfter: clusterDeletionRequeueInterval}, nil
+	}
+	if snapshot := clusterTemplateInstance.Status.TemplateSnapshot; snapshot != nil {
+		if snapshot.ClusterDefinition != "" {
+			err := clusterTemplateInstance.DeleteDay1Application(ctx, r.Client, ArgoCDNamespace, snapshot.ClusterDefinition)
 			if err != nil {
 				return ctrl.Result{}, err
 			}
 		}
+		err := clusterTemplateInstance.DeleteDay2Application(ctx, r.Client, ArgoCDNamespace, snapshot.ClusterSetup)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	} else if err := r.deleteApplicationsOfTemplate(ctx, clusterTemplateInstance); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	// cleanup argocd secrets (ie new cluster)
@@ -306,14 +765,378 @@ func (r *ClusterTemplateInstanceReconciler) delete(
 		}
 	}
 
+	// The instance may be deleted before the quota counted it
+	if err := clusterTemplateInstance.ReleaseQuotaReservations(ctx, r.Client); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := clusterTemplateInstance.SettleBilling(ctx, r.Client); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	controllerutil.RemoveFinalizer(
 		clusterTemplateInstance,
 		v1alpha1.CTIFinalizer,
 	)
-	err := r.Update(ctx, clusterTemplateInstance)
+	err = r.Update(ctx, clusterTemplateInstance)
+	if err == nil {
+		metrics.ForgetInstance(clusterTemplateInstance)
+	}
 	return ctrl.Result{}, err
 }
 
+// Deletes the applications of instances which were created before the template snapshot was recorded
+// Runs the delete hook of the cluster provider before the cluster definition is deleted. Returns
+// true once the provider finished the cleanup.
+func (r *ClusterTemplateInstanceReconciler) deleteCluster(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (bool, error) {
+	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
+		return true, nil
+	}
+	if _, ok := clusterTemplateInstance.Annotations[v1alpha1.ClusterProviderExperimentalAnnotation]; ok {
+		return true, nil
+	}
+	application, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	provider, err := clusterprovider.GetInstanceClusterProvider(*application, *clusterTemplateInstance)
+	if err != nil {
+		// The instance can be deleted even when the cluster provider is not known
+		return true, nil
+	}
+	deletionProvider, ok := provider.(clusterprovider.DeletionProvider)
+	if !ok {
+		return true, nil
+	}
+	return deletionProvider.DeleteCluster(ctx, r.Client, *clusterTemplateInstance)
+}
+
+func (r *ClusterTemplateInstanceReconciler) deleteApplicationsOfTemplate(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	var clusterTemplate client.Object
+	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
+		clusterTemplate = &v1alpha1.ClusterTemplateSetup{}
+	} else {
+		clusterTemplate = &v1alpha1.ClusterTemplate{}
+	}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, clusterTemplate); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	_, clusterDefinition, clusterSetup := getClusterProperties(clusterTemplate)
+	if clusterDefinition != "" {
+		err := clusterTemplateInstance.DeleteDay1Application(ctx, r.Client, ArgoCDNamespace, clusterDefinition)
+		if err != nil {
+			return err
+		}
+	}
+	return clusterTemplateInstance.DeleteDay2Application(ctx, r.Client, ArgoCDNamespace, clusterSetup)
+}
+
+// Adopts a Ready cluster of a matching ClusterTemplatePool instead of installing a new one. The
+// cluster definition application, cluster secrets, ManagedCluster and ArgoCD cluster secret are
+// handed over from the pooled instance.
+func (r *ClusterTemplateInstanceReconciler) claimPooledCluster(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	pooled, err := r.findPooledInstance(ctx, clusterTemplateInstance)
+	if err != nil || pooled == nil {
+		return err
+	}
+
+	claimedBy := clusterTemplateInstance.Namespace + "/" + clusterTemplateInstance.Name
+	if pooled.Annotations[v1alpha1.CTIPoolClaimAnnotation] != claimedBy {
+		if pooled.Annotations == nil {
+			pooled.Annotations = map[string]string{}
+		}
+		pooled.Annotations[v1alpha1.CTIPoolClaimAnnotation] = claimedBy
+		// Fails with conflict if the pooled instance is claimed concurrently
+		if err := r.Update(ctx, pooled); err != nil {
+			return err
+		}
+	}
+	CTIlog.Info(
+		"Claim pooled cluster",
+		"name",
+		claimedBy,
+		"pooledInstance",
+		pooled.Namespace+"/"+pooled.Name,
+	)
+
+	snapshot := pooled.Status.TemplateSnapshot
+	if err := pooled.MoveDay1Application(
+		ctx,
+		r.Client,
+		ArgoCDNamespace,
+		snapshot.ClusterDefinition,
+		clusterTemplateInstance,
+	); err != nil {
+		return err
+	}
+	if err := r.moveClusterSecrets(ctx, pooled, clusterTemplateInstance); err != nil {
+		return err
+	}
+
+	if r.EnableManagedCluster {
+		mc, err := ocm.GetManagedCluster(ctx, r.Client, pooled)
+		if err != nil {
+			if _, ok := err.(*ocm.MCNotFoundError); !ok {
+				return err
+			}
+		}
+		if mc != nil {
+			mc.Labels[v1alpha1.CTINameLabel] = clusterTemplateInstance.Name
+			mc.Labels[v1alpha1.CTINamespaceLabel] = clusterTemplateInstance.Namespace
+			if err := r.Update(ctx, mc); err != nil {
+				return err
+			}
+		}
+	}
+
+	argoClusterSecrets := &corev1.SecretList{}
+	if err := r.List(
+		ctx,
+		argoClusterSecrets,
+		client.InNamespace(ArgoCDNamespace),
+		client.MatchingLabels{
+			v1alpha1.CTINameLabel:      pooled.Name,
+			v1alpha1.CTINamespaceLabel: pooled.Namespace,
+		},
+	); err != nil {
+		return err
+	}
+	for _, secret := range argoClusterSecrets.Items {
+		secret.Labels[v1alpha1.CTINameLabel] = clusterTemplateInstance.Name
+		secret.Labels[v1alpha1.CTINamespaceLabel] = clusterTemplateInstance.Namespace
+		if string(secret.Data["name"]) == pooled.Namespace+"/"+pooled.Name {
+			secret.Data["name"] = []byte(claimedBy)
+		}
+		if err := r.Update(ctx, &secret); err != nil {
+			return err
+		}
+	}
+
+	clusterTemplateInstance.Status.TemplateSnapshot = snapshot.DeepCopy()
+	clusterTemplateInstance.Status.Conditions = nil
+	for _, condition := range pooled.Status.Conditions {
+		meta.SetStatusCondition(&clusterTemplateInstance.Status.Conditions, condition)
+	}
+	clusterTemplateInstance.Status.ManagedCluster = pooled.Status.ManagedCluster
+	clusterTemplateInstance.Status.ConsoleURL = pooled.Status.ConsoleURL
+	clusterTemplateInstance.Status.PowerState = pooled.Status.PowerState
+	clusterTemplateInstance.Status.FirstLoginAttempt = pooled.Status.FirstLoginAttempt
+	return nil
+}
+
+// Returns the pooled instance which was already claimed by the instance or a Ready unclaimed one
+func (r *ClusterTemplateInstanceReconciler) findPooledInstance(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (*v1alpha1.ClusterTemplateInstance, error) {
+	pools := &v1alpha1.ClusterTemplatePoolList{}
+	if err := r.List(ctx, pools); err != nil {
+		return nil, err
+	}
+
+	claimedBy := clusterTemplateInstance.Namespace + "/" + clusterTemplateInstance.Name
+	var candidate *v1alpha1.ClusterTemplateInstance
+	for _, pool := range pools.Items {
+		if !pool.CanBeClaimedBy(clusterTemplateInstance) {
+			continue
+		}
+		ctis := &v1alpha1.ClusterTemplateInstanceList{}
+		if err := r.List(
+			ctx,
+			ctis,
+			client.InNamespace(PoolNamespace),
+			client.MatchingLabels{v1alpha1.CTIPoolLabel: pool.Name},
+		); err != nil {
+			return nil, err
+		}
+		for index := range ctis.Items {
+			cti := &ctis.Items[index]
+			if cti.GetDeletionTimestamp() != nil || cti.Status.TemplateSnapshot == nil {
+				continue
+			}
+			claim, claimed := cti.Annotations[v1alpha1.CTIPoolClaimAnnotation]
+			if claim == claimedBy {
+				return cti, nil
+			}
+			if !claimed && cti.Status.Phase == v1alpha1.PooledPhase && candidate == nil {
+				candidate = cti
+			}
+		}
+	}
+	return candidate, nil
+}
+
+// Moves kubeconfig and admin password secrets of the pooled instance to the claiming instance
+func (r *ClusterTemplateInstanceReconciler) moveClusterSecrets(
+	ctx context.Context,
+	pooled *v1alpha1.ClusterTemplateInstance,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	secretNames := map[string]string{
+		pooled.GetKubeconfigRef():    clusterTemplateInstance.GetKubeconfigRef(),
+		pooled.GetKubeadminPassRef(): clusterTemplateInstance.GetKubeadminPassRef(),
+	}
+	for pooledName, name := range secretNames {
+		pooledSecret := &corev1.Secret{}
+		if err := r.Get(ctx, types.NamespacedName{Name: pooledName, Namespace: pooled.Namespace}, pooledSecret); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: clusterTemplateInstance.Namespace,
+				OwnerReferences: []metav1.OwnerReference{
+					clusterTemplateInstance.GetOwnerReference(),
+				},
+			},
+			Data: pooledSecret.Data,
+		}
+		if err := r.Create(ctx, secret); err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+		if err := r.Delete(ctx, pooledSecret); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// Records the current template in the instance status. If the instance already has a snapshot,
+// the applications of the removed ApplicationSets are deleted and the remaining are updated.
+func (r *ClusterTemplateInstanceReconciler) snapshotTemplate(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	var clusterTemplate client.Object
+	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
+		clusterTemplate = &v1alpha1.ClusterTemplateSetup{}
+	} else {
+		clusterTemplate = &v1alpha1.ClusterTemplate{}
+	}
+	if err := r.Client.Get(ctx, client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef}, clusterTemplate); err != nil {
+		return err
+	}
+
+	if ct, ok := clusterTemplate.(*v1alpha1.ClusterTemplate); ok &&
+		ct.Spec.BaseTemplate != "" && ct.Status.EffectiveSpec == nil {
+		return fmt.Errorf("base template of cluster template '%s' is not resolved yet", ct.Name)
+	}
+
+	skipClusterRegistration, clusterDefinition, clusterSetup := getClusterProperties(clusterTemplate)
+	revision := ""
+	if ct, ok := clusterTemplate.(*v1alpha1.ClusterTemplate); ok {
+		version, err := ct.GetVersion(clusterTemplateInstance.Spec.ClusterTemplateVersion)
+		if err != nil {
+			return err
+		}
+		clusterDefinition = version.ClusterDefinition
+		clusterSetup = version.ClusterSetup
+		revision = version.Revision
+	}
+	snapshot := &v1alpha1.TemplateSnapshot{
+		Version:                 clusterTemplateInstance.Spec.ClusterTemplateVersion,
+		ClusterDefinition:       clusterDefinition,
+		ClusterSetup:            clusterSetup,
+		SkipClusterRegistration: skipClusterRegistration,
+		Labels:                  clusterTemplate.GetLabels(),
+		Revisions:               map[string]string{},
+		Timestamp:               metav1.Now(),
+	}
+	if ct, ok := clusterTemplate.(*v1alpha1.ClusterTemplate); ok {
+		spec := ct.GetEffectiveSpec()
+		snapshot.Cost = spec.Cost
+		snapshot.CostPerHour = spec.CostPerHour
+		snapshot.Parameters = spec.Parameters
+		snapshot.ClusterProvider = spec.ClusterProvider
+	}
+
+	appSetNames := clusterSetup
+	if clusterDefinition != "" {
+		appSetNames = append([]string{clusterDefinition}, clusterSetup...)
+	}
+	for _, appSetName := range appSetNames {
+		appSet := &argo.ApplicationSet{}
+		if err := r.Client.Get(
+			ctx,
+			types.NamespacedName{Name: appSetName, Namespace: ArgoCDNamespace},
+			appSet,
+		); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if revision := appSet.Spec.Template.Spec.Source.TargetRevision; revision != "" {
+			snapshot.Revisions[appSetName] = revision
+		}
+	}
+	// Revision of the template version takes precedence over the revision of the applicationset
+	if revision != "" {
+		snapshot.Revisions[clusterDefinition] = revision
+	}
+
+	previous := clusterTemplateInstance.Status.TemplateSnapshot
+	clusterTemplateInstance.Status.TemplateSnapshot = snapshot
+	if previous == nil {
+		return nil
+	}
+
+	CTIlog.Info(
+		"Rebase clustertemplateinstance to current template",
+		"name",
+		clusterTemplateInstance.Name,
+	)
+	if previous.ClusterDefinition != "" && previous.ClusterDefinition != clusterDefinition {
+		if err := clusterTemplateInstance.DeleteDay1Application(
+			ctx,
+			r.Client,
+			ArgoCDNamespace,
+			previous.ClusterDefinition,
+		); err != nil {
+			return err
+		}
+	}
+	removedSetup := []string{}
+	for _, setup := range previous.ClusterSetup {
+		if !slices.Contains(clusterSetup, setup) {
+			removedSetup = append(removedSetup, setup)
+		}
+	}
+	if len(removedSetup) > 0 {
+		if err := clusterTemplateInstance.DeleteDay2Application(
+			ctx,
+			r.Client,
+			ArgoCDNamespace,
+			removedSetup,
+		); err != nil {
+			return err
+		}
+	}
+	clusterTemplateInstance.SetParametersUpdatedCondition(
+		metav1.ConditionFalse,
+		v1alpha1.TemplateRebased,
+		"Template was rebased, applications are going to be updated",
+	)
+	return nil
+}
+
 func getClusterProperties(clusterTemplate client.Object) (bool, string, []string) {
 	var skipClusterRegistration bool
 	var clusterDefinition string
@@ -323,21 +1146,64 @@ func getClusterProperties(clusterTemplate client.Object) (bool, string, []string
 		skipClusterRegistration = clusterTemplate.(*v1alpha1.ClusterTemplateSetup).Spec.SkipClusterRegistration
 		clusterSetup = clusterTemplate.(*v1alpha1.ClusterTemplateSetup).Spec.ClusterSetup
 	case *v1alpha1.ClusterTemplate:
-		skipClusterRegistration = clusterTemplate.(*v1alpha1.ClusterTemplate).Spec.SkipClusterRegistration
-		clusterDefinition = clusterTemplate.(*v1alpha1.ClusterTemplate).Spec.ClusterDefinition
-		clusterSetup = clusterTemplate.(*v1alpha1.ClusterTemplate).Spec.ClusterSetup
+		spec := clusterTemplate.(*v1alpha1.ClusterTemplate).GetEffectiveSpec()
+		skipClusterRegistration = spec.SkipClusterRegistration
+		clusterDefinition = spec.ClusterDefinition
+		clusterSetup = spec.ClusterSetup
 	}
 
 	return skipClusterRegistration, clusterDefinition, clusterSetup
 }
 
+// Returns true if the cluster setup was already in error state, so its failure is counted once
+func isClusterSetupFailed(clusterTemplateInstance *v1alpha1.ClusterTemplateInstance, setupName string) bool {
+	if clusterTemplateInstance.Status.ClusterSetup == nil {
+		return false
+	}
+	for _, setup := range *clusterTemplateInstance.Status.ClusterSetup {
+		if setup.Name == setupName {
+			return setup.Status == argocd.ApplicationError
+		}
+	}
+	return false
+}
+
 func (r *ClusterTemplateInstanceReconciler) reconcile(
 	ctx context.Context,
 	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
-	clusterTemplate client.Object,
+	templateSnapshot *v1alpha1.TemplateSnapshot,
 ) error {
-	skipClusterRegistration, clusterDefinition, clusterSetup := getClusterProperties(clusterTemplate)
+	skipClusterRegistration := templateSnapshot.SkipClusterRegistration
+	clusterDefinition := templateSnapshot.ClusterDefinition
+	clusterSetup := templateSnapshot.ClusterSetup
+
+	// Resolved upgrade is applied together with the parameters of the cluster definition
+	if err := r.reconcileUpgrade(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ParametersUpdateFailedPhase
+		errMsg := fmt.Sprintf("failed to upgrade cluster - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileParametersUpdate(ctx, clusterTemplateInstance, clusterDefinition, clusterSetup); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ParametersUpdateFailedPhase
+		errMsg := fmt.Sprintf("failed to update parameters - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
 	if clusterTemplateInstance.Spec.KubeconfigSecretRef == nil {
+		// Hibernated cluster is not reachable, the remaining steps are skipped until it is resumed
+		skip, err := r.reconcilePowerState(ctx, clusterTemplateInstance)
+		if err != nil {
+			clusterTemplateInstance.Status.Phase = v1alpha1.PowerStateFailedPhase
+			errMsg := fmt.Sprintf("failed to change power state - %q", err)
+			clusterTemplateInstance.Status.Message = errMsg
+			return fmt.Errorf(errMsg)
+		}
+		if skip {
+			return nil
+		}
 		if err := r.reconcileClusterCreate(ctx, clusterTemplateInstance, clusterDefinition); err != nil {
 			clusterTemplateInstance.Status.Phase = v1alpha1.ClusterDefinitionFailedPhase
 			errMsg := fmt.Sprintf("failed to create cluster definition - %q", err)
@@ -376,76 +1242,346 @@ func (r *ClusterTemplateInstanceReconciler) reconcile(
 		); err != nil {
 			return err
 		}
-		clusterTemplateInstance.SetClusterInstallCondition(
-			metav1.ConditionTrue,
-			v1alpha1.ClusterInstalled,
-			"Cluster defined via secret",
-		)
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterInstalled,
+			"Cluster defined via secret",
+		)
+	}
+
+	//ACM integration
+
+	if err := r.reconcileCreateManagedCluster(ctx, clusterTemplateInstance, skipClusterRegistration, templateSnapshot.Labels); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ManagedClusterFailedPhase
+		errMsg := fmt.Sprintf("failed to create ManagedCluster - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileImportManagedCluster(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ManagedClusterImportFailedPhase
+		errMsg := fmt.Sprintf("failed to import ManagedCluster - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileCreateKlusterlet(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.KlusterletCreateFailedPhase
+		errMsg := fmt.Sprintf("failed to create Klusterlet - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileConsoleURL(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
+		return fmt.Errorf("failed to retrieve Console URL - %q", err)
+	}
+
+	//
+
+	if err := r.reconcileAddClusterToArgo(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
+		errMsg := fmt.Sprintf("failed to add cluster to argo - %q", err)
+		_, ok := err.(*clustersetup.LoginError)
+		if ok {
+			clusterTemplateInstance.Status.Phase = v1alpha1.ClusterLoginPendingPhase
+			clusterTemplateInstance.Status.Message = "Logging into the new cluster"
+		} else {
+			clusterTemplateInstance.Status.Phase = v1alpha1.ArgoClusterFailedPhase
+			clusterTemplateInstance.Status.Message = errMsg
+		}
+		return fmt.Errorf(errMsg)
+	}
+
+	// Pooled clusters wait for a claim, the cluster setup is created once the cluster is claimed
+	if clusterTemplateInstance.IsPooled() {
+		if meta.IsStatusConditionTrue(
+			clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.ArgoClusterAdded),
+		) {
+			clusterTemplateInstance.Status.Phase = v1alpha1.PooledPhase
+			clusterTemplateInstance.Status.Message = "Cluster is ready to be claimed"
+		}
+		return nil
+	}
+
+	if err := r.reconcileClusterSetupCreate(ctx, clusterTemplateInstance, clusterSetup); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupCreateFailedPhase
+		errMsg := fmt.Sprintf("failed to create cluster setup - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileClusterSetup(ctx, clusterTemplateInstance, clusterSetup); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupFailedPhase
+		errMsg := fmt.Sprintf("failed to reconcile cluster setup - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	if err := r.reconcileClusterCredentials(ctx, clusterTemplateInstance); err != nil {
+		clusterTemplateInstance.Status.Phase = v1alpha1.CredentialsFailedPhase
+		errMsg := fmt.Sprintf("failed to reconcile cluster credentials - %q", err)
+		clusterTemplateInstance.Status.Message = errMsg
+		return fmt.Errorf(errMsg)
+	}
+
+	return nil
+}
+
+// Translates the desired power state of the instance to the cluster provider. Returns true if
+// the cluster is hibernated or the power state transition is in progress.
+func (r *ClusterTemplateInstanceReconciler) reconcilePowerState(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) (bool, error) {
+	powerState := clusterTemplateInstance.GetPowerState()
+	if clusterTemplateInstance.Status.PowerState == "" {
+		clusterTemplateInstance.Status.PowerState = v1alpha1.RunningPowerState
+	}
+	if powerState == clusterTemplateInstance.Status.PowerState {
+		if clusterTemplateInstance.IsHibernated() {
+			clusterTemplateInstance.Status.Phase = v1alpha1.HibernatedPhase
+			clusterTemplateInstance.Status.Message = "Cluster is hibernated"
+			return true, nil
+		}
+		return false, nil
+	}
+
+	// Only installed clusters can be hibernated
+	if !meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterInstallSucceeded),
+	) {
+		return false, nil
+	}
+
+	if _, ok := clusterTemplateInstance.Annotations[v1alpha1.ClusterProviderExperimentalAnnotation]; ok {
+		return false, fmt.Errorf("power state is not supported by experimental provider")
+	}
+
+	application, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		return false, err
+	}
+	provider, err := clusterprovider.GetInstanceClusterProvider(*application, *clusterTemplateInstance)
+	if err != nil {
+		return false, err
+	}
+	if provider == nil {
+		return false, fmt.Errorf("unknown cluster provider - only Hive, Hypershift and Cluster API clusters are recognized")
+	}
+
+	powerStateProvider, ok := provider.(clusterprovider.PowerStateProvider)
+	if !ok {
+		return false, fmt.Errorf("power state is not supported by the cluster provider")
+	}
+	if err := clusterprovider.CheckIgnoreDifferences(
+		*application,
+		powerStateProvider.GetPowerStateFields(),
+	); err != nil {
+		return false, err
+	}
+
+	CTIlog.Info(
+		"Change power state to "+string(powerState),
+		"name",
+		clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+	)
+	done, msg, err := powerStateProvider.SetPowerState(ctx, r.Client, *clusterTemplateInstance, powerState)
+	if err != nil {
+		return false, err
+	}
+	if !done {
+		if powerState == v1alpha1.HibernatingPowerState {
+			clusterTemplateInstance.Status.Phase = v1alpha1.HibernatingPhase
+		} else {
+			clusterTemplateInstance.Status.Phase = v1alpha1.ResumingPhase
+		}
+		clusterTemplateInstance.Status.Message = msg
+		return true, nil
 	}
 
-	//ACM integration
+	clusterTemplateInstance.Status.PowerState = powerState
+	if clusterTemplateInstance.IsHibernated() {
+		clusterTemplateInstance.Status.Phase = v1alpha1.HibernatedPhase
+		clusterTemplateInstance.Status.Message = "Cluster is hibernated"
+		return true, nil
+	}
+	return false, nil
+}
 
-	if err := r.reconcileCreateManagedCluster(ctx, clusterTemplateInstance, skipClusterRegistration, clusterTemplate.GetLabels()); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.ManagedClusterFailedPhase
-		errMsg := fmt.Sprintf("failed to create ManagedCluster - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+// Refreshes the cluster info of a ready instance once it is older than the refresh interval and
+// returns the time until the next refresh, nil if the cluster provider doesn't report cluster info.
+// The info is informative only, failures are logged and don't affect the phase of the instance.
+func (r *ClusterTemplateInstanceReconciler) reconcileClusterInfo(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) *time.Duration {
+	if clusterTemplateInstance.Status.Phase != v1alpha1.ReadyPhase ||
+		clusterTemplateInstance.Spec.KubeconfigSecretRef != nil {
+		return nil
+	}
+	if _, ok := clusterTemplateInstance.Annotations[v1alpha1.ClusterProviderExperimentalAnnotation]; ok {
+		return nil
 	}
 
-	if err := r.reconcileImportManagedCluster(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.ManagedClusterImportFailedPhase
-		errMsg := fmt.Sprintf("failed to import ManagedCluster - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+	now := r.Now()
+	if info := clusterTemplateInstance.Status.ClusterInfo; info != nil {
+		if wait := info.LastUpdated.Add(clusterInfoRefreshInterval).Sub(now); wait > 0 {
+			return &wait
+		}
 	}
 
-	if err := r.reconcileCreateKlusterlet(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.KlusterletCreateFailedPhase
-		errMsg := fmt.Sprintf("failed to create Klusterlet - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+	application, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		CTIlog.Error(err, "failed to get cluster info", "name", clusterTemplateInstance.Name)
+		return &clusterInfoRefreshInterval
+	}
+	provider, err := clusterprovider.GetInstanceClusterProvider(*application, *clusterTemplateInstance)
+	if err != nil {
+		CTIlog.Error(err, "failed to get cluster info", "name", clusterTemplateInstance.Name)
+		return &clusterInfoRefreshInterval
+	}
+	infoProvider, ok := provider.(clusterprovider.ClusterInfoProvider)
+	if !ok {
+		return nil
 	}
 
-	if err := r.reconcileConsoleURL(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
-		return fmt.Errorf("failed to retrieve Console URL - %q", err)
+	info, err := infoProvider.GetClusterInfo(ctx, r.Client, *clusterTemplateInstance)
+	if err != nil {
+		CTIlog.Error(err, "failed to get cluster info", "name", clusterTemplateInstance.Name)
+		return &clusterInfoRefreshInterval
 	}
+	info.LastUpdated = metav1.NewTime(now)
+	clusterTemplateInstance.Status.ClusterInfo = info
+	return &clusterInfoRefreshInterval
+}
 
-	//
+// Resolves the requested upgrade against the upgrades allowed by the template and tracks the
+// progress of the resolved upgrade once the cluster is installed. Failures of the tracking are
+// reported by the ClusterUpgrade condition only.
+func (r *ClusterTemplateInstanceReconciler) reconcileUpgrade(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+) error {
+	if clusterTemplateInstance.Spec.KubeconfigSecretRef != nil || clusterTemplateInstance.Spec.Upgrade == nil {
+		return nil
+	}
 
-	if err := r.reconcileAddClusterToArgo(ctx, clusterTemplateInstance, skipClusterRegistration); err != nil {
-		errMsg := fmt.Sprintf("failed to add cluster to argo - %q", err)
-		_, ok := err.(*clustersetup.LoginError)
-		if ok {
-			clusterTemplateInstance.Status.Phase = v1alpha1.ClusterLoginPendingPhase
-			clusterTemplateInstance.Status.Message = "Logging into the new cluster"
-		} else {
-			clusterTemplateInstance.Status.Phase = v1alpha1.ArgoClusterFailedPhase
-			clusterTemplateInstance.Status.Message = errMsg
+	if clusterTemplateInstance.UpgradeRequired() {
+		clusterTemplate := &v1alpha1.ClusterTemplate{}
+		if err := r.Client.Get(
+			ctx,
+			client.ObjectKey{Name: clusterTemplateInstance.Spec.ClusterTemplateRef},
+			clusterTemplate,
+		); err != nil {
+			return err
+		}
+		upgrade, err := clusterTemplate.ResolveUpgrade(*clusterTemplateInstance.Spec.Upgrade)
+		if err != nil {
+			clusterTemplateInstance.SetClusterUpgradeCondition(
+				metav1.ConditionFalse,
+				v1alpha1.UpgradeNotAllowed,
+				err.Error(),
+			)
+			return nil
+		}
+		CTIlog.Info(
+			"Upgrade cluster to "+upgrade.Version,
+			"name",
+			clusterTemplateInstance.Namespace+"/"+clusterTemplateInstance.Name,
+		)
+		clusterTemplateInstance.Status.Upgrade = upgrade
+		clusterTemplateInstance.SetClusterUpgradeCondition(
+			metav1.ConditionFalse,
+			v1alpha1.UpgradePending,
+			"Waiting for the cluster to request release "+upgrade.Version,
+		)
+		// The upgrade may be resolved later than spec.upgrade was changed (ie once the template allows
+		// it), so the update of the existing cluster definition is requested explicitly
+		if meta.IsStatusConditionTrue(
+			clusterTemplateInstance.Status.Conditions,
+			string(v1alpha1.ClusterDefinitionCreated),
+		) {
+			clusterTemplateInstance.SetParametersUpdatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.UpgradeResolved,
+				"Upgrade was resolved, cluster definition is going to be updated",
+			)
 		}
-		return fmt.Errorf(errMsg)
 	}
 
-	if err := r.reconcileClusterSetupCreate(ctx, clusterTemplateInstance, clusterSetup); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupCreateFailedPhase
-		errMsg := fmt.Sprintf("failed to create cluster setup - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+	upgradeCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterUpgrade),
+	)
+	if upgradeCondition == nil ||
+		(upgradeCondition.Reason != string(v1alpha1.UpgradePending) &&
+			upgradeCondition.Reason != string(v1alpha1.ClusterUpgrading) &&
+			upgradeCondition.Reason != string(v1alpha1.ClusterUpgradeFailed)) {
+		return nil
 	}
-
-	if err := r.reconcileClusterSetup(ctx, clusterTemplateInstance, clusterSetup); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterSetupFailedPhase
-		errMsg := fmt.Sprintf("failed to reconcile cluster setup - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+	if !meta.IsStatusConditionTrue(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ClusterInstallSucceeded),
+	) {
+		return nil
 	}
 
-	if err := r.reconcileClusterCredentials(ctx, clusterTemplateInstance); err != nil {
-		clusterTemplateInstance.Status.Phase = v1alpha1.CredentialsFailedPhase
-		errMsg := fmt.Sprintf("failed to reconcile cluster credentials - %q", err)
-		clusterTemplateInstance.Status.Message = errMsg
-		return fmt.Errorf(errMsg)
+	upgrade := *clusterTemplateInstance.Status.Upgrade
+	if _, ok := clusterTemplateInstance.Annotations[v1alpha1.ClusterProviderExperimentalAnnotation]; ok {
+		clusterTemplateInstance.SetClusterUpgradeCondition(
+			metav1.ConditionUnknown,
+			v1alpha1.UpgradeProgressUnknown,
+			"Upgrade progress is not reported by experimental provider",
+		)
+		return nil
+	}
+	application, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+	if err != nil {
+		CTIlog.Error(err, "failed to get upgrade status", "name", clusterTemplateInstance.Name)
+		return nil
+	}
+	provider, err := clusterprovider.GetInstanceClusterProvider(*application, *clusterTemplateInstance)
+	if err != nil {
+		CTIlog.Error(err, "failed to get upgrade status", "name", clusterTemplateInstance.Name)
+		return nil
+	}
+	upgradeProvider, ok := provider.(clusterprovider.UpgradeProvider)
+	if !ok {
+		clusterTemplateInstance.SetClusterUpgradeCondition(
+			metav1.ConditionUnknown,
+			v1alpha1.UpgradeProgressUnknown,
+			"Upgrade progress is not reported by the cluster provider",
+		)
+		return nil
 	}
 
+	done, msg, err := upgradeProvider.GetUpgradeStatus(ctx, r.Client, *clusterTemplateInstance, upgrade)
+	if err != nil {
+		if _, failed := err.(*clusterprovider.UpgradeFailedError); failed {
+			clusterTemplateInstance.SetClusterUpgradeCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterUpgradeFailed,
+				err.Error(),
+			)
+			return nil
+		}
+		CTIlog.Error(err, "failed to get upgrade status", "name", clusterTemplateInstance.Name)
+		return nil
+	}
+	if done {
+		clusterTemplateInstance.SetClusterUpgradeCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ClusterUpgraded,
+			msg,
+		)
+	} else {
+		clusterTemplateInstance.SetClusterUpgradeCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterUpgrading,
+			msg,
+		)
+	}
 	return nil
 }
 
@@ -563,10 +1699,21 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterStatus(
 		return nil
 	}
 
-	provider := clusterprovider.GetClusterProvider(*application)
+	provider, err := clusterprovider.GetInstanceClusterProvider(*application, *clusterTemplateInstance)
+	if err != nil {
+		msg := fmt.Sprintf("Failed to detect cluster provider - %q", err)
+		clusterTemplateInstance.SetClusterInstallCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ClusterProviderDetectionFailed,
+			msg,
+		)
+		clusterTemplateInstance.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+		clusterTemplateInstance.Status.Message = msg
+		return nil
+	}
 
 	if provider == nil {
-		msg := "Unknown cluster provider - only Hive and Hypershift clusters are recognized"
+		msg := "Unknown cluster provider - only Hive, Hypershift and Cluster API clusters are recognized"
 		clusterTemplateInstance.SetClusterInstallCondition(
 			metav1.ConditionFalse,
 			v1alpha1.ClusterProviderDetectionFailed,
@@ -1117,6 +2264,9 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 
 		if status == argocd.ApplicationError {
 			errorSetups = append(errorSetups, setupName)
+			if !isClusterSetupFailed(clusterTemplateInstance, setupName) {
+				metrics.ClusterSetupFailures.WithLabelValues(setupName).Inc()
+			}
 		}
 
 		if status == argocd.ApplicationDegraded {
@@ -1163,20 +2313,201 @@ func (r *ClusterTemplateInstanceReconciler) reconcileClusterSetup(
 	return nil
 }
 
+// Propagates updated parameters to the generators of already created applications and tracks
+// the rollout until all applications are synced with the new parameters
+func (r *ClusterTemplateInstanceReconciler) reconcileParametersUpdate(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	clusterDefinition string,
+	clusterSetup []string,
+) error {
+	updateDay1 := clusterDefinition != "" &&
+		clusterTemplateInstance.PhaseCanExecute(v1alpha1.ClusterDefinitionCreated)
+	updateDay2 := len(clusterSetup) > 0 &&
+		clusterTemplateInstance.PhaseCanExecute(v1alpha1.ClusterSetupCreated)
+
+	if updateDay1 || updateDay2 {
+		parametersHash, err := clusterTemplateInstance.GetParametersHash(ctx, r.Client)
+		if err != nil {
+			clusterTemplateInstance.SetParametersUpdatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ParametersUpdateFailed,
+				fmt.Sprintf("Failed to resolve parameters - %q", err),
+			)
+			return err
+		}
+		if clusterTemplateInstance.ParametersUpdateRequired(parametersHash) {
+			CTIlog.Info(
+				"Update parameters of clustertemplateinstance",
+				"name",
+				clusterTemplateInstance.Name,
+			)
+			if updateDay1 {
+				if err := clusterTemplateInstance.CreateDay1Application(
+					ctx,
+					r.Client,
+					ArgoCDNamespace,
+					false,
+					clusterDefinition,
+				); err != nil {
+					clusterTemplateInstance.SetParametersUpdatedCondition(
+						metav1.ConditionFalse,
+						v1alpha1.ParametersUpdateFailed,
+						fmt.Sprintf("Failed to update cluster definition - %q", err),
+					)
+					return err
+				}
+			}
+			if updateDay2 {
+				if err := clusterTemplateInstance.CreateDay2Applications(
+					ctx,
+					r.Client,
+					ArgoCDNamespace,
+					clusterSetup,
+				); err != nil {
+					clusterTemplateInstance.SetParametersUpdatedCondition(
+						metav1.ConditionFalse,
+						v1alpha1.ParametersUpdateFailed,
+						fmt.Sprintf("Failed to update cluster setup - %q", err),
+					)
+					return err
+				}
+			}
+			clusterTemplateInstance.SetParametersUpdatedCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ParametersUpdating,
+				"Waiting for applications to sync updated parameters",
+			)
+		}
+		clusterTemplateInstance.Status.ParametersHash = parametersHash
+	}
+
+	parametersUpdatedCondition := meta.FindStatusCondition(
+		clusterTemplateInstance.Status.Conditions,
+		string(v1alpha1.ParametersUpdated),
+	)
+	if parametersUpdatedCondition == nil ||
+		parametersUpdatedCondition.Status == metav1.ConditionTrue {
+		return nil
+	}
+
+	appStatuses := map[string]argocd.ApplicationStatus{}
+	appMessages := map[string]string{}
+	if updateDay1 {
+		app, err := clusterTemplateInstance.GetDay1Application(ctx, r.Client, ArgoCDNamespace)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		status, msg, err := r.getParametersSyncStatus(ctx, clusterTemplateInstance, clusterDefinition, app, false)
+		if err != nil {
+			return err
+		}
+		appStatuses[clusterDefinition] = status
+		appMessages[clusterDefinition] = msg
+	}
+	if updateDay2 {
+		apps, err := clusterTemplateInstance.GetDay2Applications(ctx, r.Client, ArgoCDNamespace)
+		if err != nil {
+			return err
+		}
+		for _, setup := range clusterSetup {
+			var setupApp *argo.Application
+			for index := range apps.Items {
+				if apps.Items[index].Name == string(clusterTemplateInstance.UID)+"-"+setup {
+					setupApp = &apps.Items[index]
+				}
+			}
+			status, msg, err := r.getParametersSyncStatus(ctx, clusterTemplateInstance, setup, setupApp, true)
+			if err != nil {
+				return err
+			}
+			appStatuses[setup] = status
+			appMessages[setup] = msg
+		}
+	}
+
+	failedApps := []string{}
+	syncing := false
+	for appSet, status := range appStatuses {
+		if status == argocd.ApplicationError || status == argocd.ApplicationDegraded {
+			failedApps = append(failedApps, appSet+": "+appMessages[appSet])
+		}
+		if status == argocd.ApplicationSyncRunning {
+			syncing = true
+		}
+	}
+
+	if len(failedApps) > 0 {
+		sort.Strings(failedApps)
+		clusterTemplateInstance.SetParametersUpdatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ParametersSyncFailed,
+			fmt.Sprintf("Following applications failed to sync updated parameters - %v", failedApps),
+		)
+	} else if syncing {
+		clusterTemplateInstance.SetParametersUpdatedCondition(
+			metav1.ConditionFalse,
+			v1alpha1.ParametersUpdating,
+			"Waiting for applications to sync updated parameters",
+		)
+	} else {
+		clusterTemplateInstance.SetParametersUpdatedCondition(
+			metav1.ConditionTrue,
+			v1alpha1.ParametersApplied,
+			"Updated parameters applied",
+		)
+	}
+	return nil
+}
+
+func (r *ClusterTemplateInstanceReconciler) getParametersSyncStatus(
+	ctx context.Context,
+	clusterTemplateInstance *v1alpha1.ClusterTemplateInstance,
+	appSetName string,
+	app *argo.Application,
+	isDay2 bool,
+) (argocd.ApplicationStatus, string, error) {
+	if app == nil {
+		return argocd.ApplicationSyncRunning, "Waiting for application to be generated", nil
+	}
+	appSet := &argo.ApplicationSet{}
+	if err := r.Client.Get(
+		ctx,
+		types.NamespacedName{Name: appSetName, Namespace: ArgoCDNamespace},
+		appSet,
+	); err != nil {
+		return "", "", err
+	}
+	params := []argo.HelmParameter{}
+	if appSet.Spec.Template.Spec.Source.Chart != "" {
+		var err error
+		params, err = clusterTemplateInstance.GetHelmParameters(ctx, r.Client, appSet, isDay2)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	status, msg := argocd.GetApplicationHealth(app, isDay2)
+	if status == argocd.ApplicationHealthy && !argocd.IsSyncedWithParameters(app, params) {
+		return argocd.ApplicationSyncRunning, "Waiting for application to sync updated parameters", nil
+	}
+	return status, msg, nil
+}
+
 func StartCTIController(
 	mgr ctrl.Manager,
-	enableHypershift bool,
-	enableHive bool,
+	providers []clusterprovider.ProviderDefinition,
 	enableManagedCluster bool,
 	enableKlusterlet bool,
 ) context.CancelFunc {
 	ctiReconciller := &ClusterTemplateInstanceReconciler{
 		Client:               mgr.GetClient(),
 		Scheme:               mgr.GetScheme(),
-		EnableHypershift:     enableHypershift,
-		EnableHive:           enableHive,
+		Providers:            providers,
 		EnableManagedCluster: enableManagedCluster,
 		EnableKlusterlet:     enableKlusterlet,
+		APIReader:            mgr.GetAPIReader(),
+		Recorder:             mgr.GetEventRecorderFor("cti-controller"),
 	}
 	if ctiReconciller.Clock == nil {
 		ctiReconciller.Clock = realClock{}
@@ -1220,33 +2551,30 @@ func (r *ClusterTemplateInstanceReconciler) SetupWatches(ctrl controller.Control
 		&source.Kind{Type: &argo.Application{}},
 		handler.EnqueueRequestsFromMapFunc(MapObjToInstance),
 	)
+	// Status of the quota is written on every change of its instances, only the changes of the spec
+	// and of the queue are relevant for the instances
+	ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.ClusterTemplateQuota{}},
+		handler.EnqueueRequestsFromMapFunc(r.MapQuotaToInstances),
+		predicate.GenerationChangedPredicate{},
+	)
+	ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.ClusterTemplateQuota{}},
+		handler.EnqueueRequestsFromMapFunc(MapQuotaQueueToInstances),
+		quotaQueueChangedPredicate,
+	)
+	ctrl.Watch(
+		&source.Kind{Type: &v1alpha1.ClusterTemplateInstanceApproval{}},
+		handler.EnqueueRequestsFromMapFunc(MapApprovalToInstance),
+	)
 
-	if r.EnableHive {
-		ctrl.Watch(
-			&source.Kind{Type: &hivev1.ClusterClaim{}},
-			handler.EnqueueRequestsFromMapFunc(
-				r.MapArgoResourceToInstance(v1alpha1.ClusterClaimGVK),
-			),
-		)
-
-		ctrl.Watch(
-			&source.Kind{Type: &hivev1.ClusterDeployment{}},
-			handler.EnqueueRequestsFromMapFunc(
-				r.MapArgoResourceToInstance(v1alpha1.ClusterDeploymentGVK),
-			),
-		)
-	}
-
-	if r.EnableHypershift {
-		ctrl.Watch(
-			&source.Kind{Type: &hypershiftv1beta1.HostedCluster{}},
-			handler.EnqueueRequestsFromMapFunc(
-				r.MapArgoResourceToInstance(v1alpha1.HostedClusterGVK),
-			),
-		)
-		ctrl.Watch(
-			&source.Kind{Type: &hypershiftv1beta1.NodePool{}},
-			handler.EnqueueRequestsFromMapFunc(r.MapArgoResourceToInstance(v1alpha1.NodePoolGVK)))
+	for _, provider := range r.Providers {
+		for _, watch := range provider.Watches {
+			ctrl.Watch(
+				&source.Kind{Type: watch.Object},
+				handler.EnqueueRequestsFromMapFunc(r.MapArgoResourceToInstance(watch.ResourceType)),
+			)
+		}
 	}
 
 	if r.EnableManagedCluster {
@@ -1258,7 +2586,7 @@ func (r *ClusterTemplateInstanceReconciler) SetupWatches(ctrl controller.Control
 }
 
 func (r *ClusterTemplateInstanceReconciler) MapArgoResourceToInstance(
-	resourceGVK schema.GroupVersionResource,
+	resourceType clusterprovider.ResourceType,
 ) func(res client.Object) []reconcile.Request {
 	return func(res client.Object) []reconcile.Request {
 		reply := []reconcile.Request{}
@@ -1300,9 +2628,7 @@ func (r *ClusterTemplateInstanceReconciler) MapArgoResourceToInstance(
 			}
 			if name != "" && namespace != "" {
 				for _, argoRes := range app.Status.Resources {
-					if resourceGVK.Resource == argoRes.Kind &&
-						resourceGVK.Group == argoRes.Group &&
-						resourceGVK.Version == argoRes.Version &&
+					if resourceType.Matches(argoRes) &&
 						res.GetNamespace() == argoRes.Namespace &&
 						res.GetName() == argoRes.Name {
 						reply = append(
@@ -1320,6 +2646,84 @@ func (r *ClusterTemplateInstanceReconciler) MapArgoResourceToInstance(
 	}
 }
 
+// Instances are reconciled on quota spec changes to pick up the power schedule of the quota
+func (r *ClusterTemplateInstanceReconciler) MapQuotaToInstances(quota client.Object) []reconcile.Request {
+	reply := []reconcile.Request{}
+	ctq, ok := quota.(*v1alpha1.ClusterTemplateQuota)
+	if !ok {
+		return reply
+	}
+	ctis := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.Client.List(context.TODO(), ctis, client.InNamespace(ctq.Namespace)); err != nil {
+		return reply
+	}
+	for _, cti := range ctis.Items {
+		for _, allowedTemplate := range ctq.Spec.AllowedTemplates {
+			if allowedTemplate.Name == cti.Spec.ClusterTemplateRef {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: cti.Namespace,
+					Name:      cti.Name,
+				}})
+				break
+			}
+		}
+	}
+	return reply
+}
+
+// Passes the updates of quotas whose queue changed, so the started instances are provisioned
+var quotaQueueChangedPredicate = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldQuota, ok := e.ObjectOld.(*v1alpha1.ClusterTemplateQuota)
+		if !ok {
+			return false
+		}
+		newQuota, ok := e.ObjectNew.(*v1alpha1.ClusterTemplateQuota)
+		if !ok {
+			return false
+		}
+		return !equality.Semantic.DeepEqual(oldQuota.Status.Queue, newQuota.Status.Queue)
+	},
+	GenericFunc: func(e event.GenericEvent) bool {
+		return false
+	},
+}
+
+// Maps the quota to the instances in its queue. Updates are mapped from both the old and the new
+// quota, so the instances which were started by the quota are reconciled as well
+func MapQuotaQueueToInstances(quota client.Object) []reconcile.Request {
+	reply := []reconcile.Request{}
+	ctq, ok := quota.(*v1alpha1.ClusterTemplateQuota)
+	if !ok {
+		return reply
+	}
+	for _, queued := range ctq.Status.Queue {
+		reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: queued.Namespace,
+			Name:      queued.Name,
+		}})
+	}
+	return reply
+}
+
+// Instances which wait for approval are reconciled once they are approved
+func MapApprovalToInstance(approval client.Object) []reconcile.Request {
+	ctia, ok := approval.(*v1alpha1.ClusterTemplateInstanceApproval)
+	if !ok {
+		return []reconcile.Request{}
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Namespace: ctia.Namespace,
+		Name:      ctia.Spec.ClusterTemplateInstanceRef,
+	}}}
+}
+
 func MapObjToInstance(obj client.Object) []reconcile.Request {
 	reply := []reconcile.Request{}
 	name := ""
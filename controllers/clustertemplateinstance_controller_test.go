@@ -6,6 +6,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
 	. "github.com/onsi/ginkgo"
@@ -20,8 +21,11 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/hypershift/api/util/ipnet"
 	hypershift "github.com/openshift/hypershift/api/v1beta1"
 
@@ -66,6 +70,46 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 		})
 	})
 
+	Context("Power schedule of the template instance", func() {
+		ct := &v1alpha1.ClusterTemplate{}
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		ctq := &v1alpha1.ClusterTemplateQuota{}
+		appset := &argo.ApplicationSet{}
+
+		BeforeEach(func() {
+			appset = testutils.GetAppset()
+			Expect(k8sClient.Create(ctx, appset)).Should(Succeed())
+			ctq = testutils.GetCTQ()
+			ctq.Spec.AllowedTemplates[0].PowerSchedule = &v1alpha1.PowerSchedule{
+				Hibernate: "0 19 * * *",
+				Resume:    "0 7 * * *",
+			}
+			Expect(k8sClient.Create(ctx, ctq)).Should(Succeed())
+			ct = testutils.GetCT(false)
+			Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+			cti = testutils.GetCTI()
+			Expect(k8sClient.Create(ctx, cti)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			testutils.DeleteResource(ctx, cti, k8sClient)
+			testutils.DeleteResource(ctx, ct, k8sClient)
+			testutils.DeleteResource(ctx, ctq, k8sClient)
+			testutils.DeleteResource(ctx, appset, k8sClient)
+		})
+
+		It("Should schedule next power state transition", func() {
+			Eventually(func() *v1alpha1.PowerStateTransition {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(cti), cti)
+				if err != nil {
+					return nil
+				}
+				return cti.Status.NextPowerStateTransition
+			}, timeout, interval).ShouldNot(BeNil())
+			Expect(cti.Status.NextPowerStateTransition.Time.After(time.Now())).Should(BeTrue())
+		})
+	})
+
 	Context("Initial ClusterTemplateInstance Status", func() {
 		ct := &v1alpha1.ClusterTemplate{}
 		cti := &v1alpha1.ClusterTemplateInstance{}
@@ -1142,6 +1186,432 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 		})
 	})
 
+	Context("Parameters update", func() {
+		cti := &v1alpha1.ClusterTemplateInstance{}
+		BeforeEach(func() {
+			cti = testutils.GetCTI()
+			cti.Generation = 2
+			cti.Status = v1alpha1.ClusterTemplateInstanceStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(v1alpha1.ClusterDefinitionCreated),
+						Status: metav1.ConditionTrue,
+					},
+				},
+			}
+		})
+		It("Updates generator of day1 app", func() {
+			appset := testutils.GetAppset()
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, appset)
+			Expect(
+				cti.CreateDay1Application(ctx, client, defaultArgoCDNs, false, appset.Name),
+			).Should(Succeed())
+
+			cti.Spec.Parameters = []v1alpha1.Parameter{
+				{
+					Name:  "foo",
+					Value: "bar",
+				},
+			}
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+			err := reconciler.reconcileParametersUpdate(ctx, cti, appset.Name, []string{})
+			Expect(err).Should(BeNil())
+
+			updatedAppset := &argo.ApplicationSet{}
+			Expect(client.Get(
+				ctx,
+				types.NamespacedName{Name: appset.Name, Namespace: appset.Namespace},
+				updatedAppset,
+			)).Should(Succeed())
+			Expect(
+				updatedAppset.Spec.Generators[1].List.Template.Spec.Source.Helm.Parameters,
+			).Should(Equal([]argo.HelmParameter{{Name: "foo", Value: "bar"}}))
+
+			parametersUpdatedCondition := meta.FindStatusCondition(
+				cti.Status.Conditions,
+				string(v1alpha1.ParametersUpdated),
+			)
+			Expect(parametersUpdatedCondition.Status).Should(Equal(metav1.ConditionFalse))
+			Expect(
+				parametersUpdatedCondition.Reason,
+			).Should(Equal(string(v1alpha1.ParametersUpdating)))
+		})
+		It("Updates generator only when applied parameters change", func() {
+			appset := testutils.GetAppset()
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "foo-config", Namespace: cti.Namespace},
+				Data:       map[string]string{"foo": "bar"},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, appset, configMap)
+			cti.Spec.Parameters = []v1alpha1.Parameter{
+				{
+					Name: "foo",
+					ValueFrom: &v1alpha1.ParameterValueSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+							Key:                  "foo",
+						},
+					},
+				},
+			}
+			Expect(
+				cti.CreateDay1Application(ctx, client, defaultArgoCDNs, false, appset.Name),
+			).Should(Succeed())
+			parametersHash, err := cti.GetParametersHash(ctx, client)
+			Expect(err).ShouldNot(HaveOccurred())
+			cti.Status.ParametersHash = parametersHash
+			cti.SetParametersUpdatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ParametersApplied,
+				"Updated parameters applied",
+			)
+
+			// Other changes of the spec bump the generation as well
+			cti.Generation = 3
+			cti.Spec.PowerState = v1alpha1.HibernatingPowerState
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+			Expect(
+				reconciler.reconcileParametersUpdate(ctx, cti, appset.Name, []string{}),
+			).Should(Succeed())
+			Expect(meta.IsStatusConditionTrue(
+				cti.Status.Conditions,
+				string(v1alpha1.ParametersUpdated),
+			)).Should(BeTrue())
+
+			configMap.Data["foo"] = "baz"
+			Expect(client.Update(ctx, configMap)).Should(Succeed())
+			Expect(
+				reconciler.reconcileParametersUpdate(ctx, cti, appset.Name, []string{}),
+			).Should(Succeed())
+			parametersUpdatedCondition := meta.FindStatusCondition(
+				cti.Status.Conditions,
+				string(v1alpha1.ParametersUpdated),
+			)
+			Expect(
+				parametersUpdatedCondition.Reason,
+			).Should(Equal(string(v1alpha1.ParametersUpdating)))
+			Expect(cti.Status.ParametersHash).ShouldNot(Equal(parametersHash))
+
+			updatedAppset := &argo.ApplicationSet{}
+			Expect(client.Get(
+				ctx,
+				types.NamespacedName{Name: appset.Name, Namespace: appset.Namespace},
+				updatedAppset,
+			)).Should(Succeed())
+			Expect(
+				updatedAppset.Spec.Generators[1].List.Template.Spec.Source.Helm.Parameters,
+			).Should(Equal([]argo.HelmParameter{{Name: "foo", Value: "baz"}}))
+		})
+	})
+
+	Context("Template snapshot", func() {
+		It("Records template and rebases to current template", func() {
+			cost := 2
+			ct := testutils.GetCTWithCost(true, &cost, false)
+			cti := testutils.GetCTI()
+			appset := testutils.GetAppset()
+			appset2 := testutils.GetAppset2()
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, ct, appset, appset2)
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+
+			err := reconciler.snapshotTemplate(ctx, cti)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cti.Status.TemplateSnapshot.ClusterDefinition).Should(Equal(ct.Spec.ClusterDefinition))
+			Expect(cti.Status.TemplateSnapshot.ClusterSetup).Should(Equal(ct.Spec.ClusterSetup))
+			Expect(*cti.Status.TemplateSnapshot.Cost).Should(Equal(cost))
+			Expect(
+				cti.Status.TemplateSnapshot.Revisions[appset.Name],
+			).Should(Equal(appset.Spec.Template.Spec.Source.TargetRevision))
+			Expect(meta.FindStatusCondition(
+				cti.Status.Conditions,
+				string(v1alpha1.ParametersUpdated),
+			)).Should(BeNil())
+
+			ct.Spec.ClusterSetup = []string{}
+			Expect(client.Update(ctx, ct)).Should(Succeed())
+
+			err = reconciler.snapshotTemplate(ctx, cti)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cti.Status.TemplateSnapshot.ClusterSetup).Should(BeEmpty())
+			parametersUpdatedCondition := meta.FindStatusCondition(
+				cti.Status.Conditions,
+				string(v1alpha1.ParametersUpdated),
+			)
+			Expect(
+				parametersUpdatedCondition.Reason,
+			).Should(Equal(string(v1alpha1.TemplateRebased)))
+		})
+	})
+
+	Context("Template version", func() {
+		It("Records version of the template", func() {
+			ct := testutils.GetCT(true)
+			ct.Spec.Versions = []v1alpha1.ClusterTemplateVersion{
+				{
+					Name:     "v1",
+					Revision: "0.0.1",
+				},
+			}
+			cti := testutils.GetCTI()
+			cti.Spec.ClusterTemplateVersion = "v1"
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, ct, testutils.GetAppset())
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+
+			err := reconciler.snapshotTemplate(ctx, cti)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(cti.Status.TemplateSnapshot.Version).Should(Equal("v1"))
+			Expect(cti.Status.TemplateSnapshot.ClusterDefinition).Should(Equal(ct.Spec.ClusterDefinition))
+			Expect(cti.Status.TemplateSnapshot.ClusterSetup).Should(BeEmpty())
+			Expect(
+				cti.Status.TemplateSnapshot.Revisions[ct.Spec.ClusterDefinition],
+			).Should(Equal("0.0.1"))
+
+			cti.Spec.ClusterTemplateVersion = "v2"
+			Expect(reconciler.snapshotTemplate(ctx, cti)).ShouldNot(Succeed())
+		})
+	})
+
+	Context("Approval", func() {
+		It("Waits for approval of the instance", func() {
+			ct := testutils.GetCTWithCost(true, nil, false)
+			ct.Spec.RequiresApproval = true
+			cti := testutils.GetCTI()
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, ct)
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+
+			approved, err := reconciler.checkApproval(ctx, cti)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(approved).Should(BeFalse())
+			Expect(cti.Status.Approval).Should(BeNil())
+
+			approval := &v1alpha1.ClusterTemplateInstanceApproval{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "approval",
+					Namespace: cti.Namespace,
+				},
+				Spec: v1alpha1.ClusterTemplateInstanceApprovalSpec{
+					ClusterTemplateInstanceRef: cti.Name,
+					ClusterTemplateInstanceUID: cti.UID,
+					Approver:                   "admin",
+				},
+			}
+			Expect(client.Create(ctx, approval)).Should(Succeed())
+
+			approved, err = reconciler.checkApproval(ctx, cti)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(approved).Should(BeTrue())
+			Expect(cti.Status.Approval.ApprovedBy).Should(Equal("admin"))
+		})
+	})
+
+	Context("Events", func() {
+		It("Records events of changed conditions and phase", func() {
+			cti := testutils.GetCTI()
+			cti.SetDefaultConditions()
+			cti.Status.Phase = v1alpha1.ClusterInstallingPhase
+			previousStatus := cti.Status.DeepCopy()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Recorder: recorder,
+			}
+
+			cti.SetClusterInstallCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ApplicationError,
+				"Install failed",
+			)
+			cti.Status.Phase = v1alpha1.ClusterInstallFailedPhase
+			cti.Status.Message = "Install failed"
+			reconciler.recordStatusEvents(cti, previousStatus)
+
+			Expect(recorder.Events).Should(HaveLen(2))
+			Expect(<-recorder.Events).Should(Equal(
+				"Warning ApplicationError ClusterInstallSucceeded: Install failed",
+			))
+			Expect(<-recorder.Events).Should(Equal("Warning ClusterInstallFailed Install failed"))
+
+			reconciler.recordStatusEvents(cti, cti.Status.DeepCopy())
+			Expect(recorder.Events).Should(BeEmpty())
+		})
+
+		It("Records phase history", func() {
+			now := time.Now()
+			cti := testutils.GetCTI()
+			cti.SetDefaultConditions()
+			cti.Status.Phase = v1alpha1.ClusterDefinitionFailedPhase
+			previousStatus := cti.Status.DeepCopy()
+
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Clock: fixedClock{now: now},
+			}
+			cti.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ApplicationCreated,
+				"Created",
+			)
+			cti.SetClusterInstallCondition(
+				metav1.ConditionFalse,
+				v1alpha1.ClusterInstalling,
+				"Installing",
+			)
+			cti.Status.Phase = v1alpha1.ClusterInstallingPhase
+			reconciler.recordPhase(cti, previousStatus)
+
+			Expect(cti.Status.History).Should(HaveLen(1))
+			Expect(cti.Status.History[0].Phase).Should(Equal(v1alpha1.ClusterInstallingPhase))
+			Expect(cti.Status.History[0].Reason).Should(Equal(string(v1alpha1.ClusterInstalling)))
+			Expect(cti.Status.History[0].Timestamp.Time).Should(Equal(now))
+		})
+
+		It("Refreshes cluster info of ready instance", func() {
+			now := time.Now()
+			cti := testutils.GetCTI()
+			cti.Status.Phase = v1alpha1.ReadyPhase
+			app := testutils.GetApp()
+			app.Namespace = ArgoCDNamespace
+			app.Status.Resources = []argo.ResourceStatus{
+				{
+					Group:     "hypershift.openshift.io",
+					Version:   "v1beta1",
+					Kind:      "HostedCluster",
+					Name:      "foo",
+					Namespace: "default",
+				},
+			}
+			hc := &hypershift.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+				},
+				Spec: hypershift.HostedClusterSpec{
+					Platform: hypershift.PlatformSpec{
+						Type: hypershift.AWSPlatform,
+					},
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, app, hc)
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+				Clock:  fixedClock{now: now},
+			}
+
+			requeueAfter := reconciler.reconcileClusterInfo(ctx, cti)
+			Expect(*requeueAfter).Should(Equal(clusterInfoRefreshInterval))
+			Expect(cti.Status.ClusterInfo).ShouldNot(BeNil())
+			Expect(cti.Status.ClusterInfo.Platform).Should(Equal("AWS"))
+			Expect(cti.Status.ClusterInfo.LastUpdated.Time).Should(Equal(now))
+
+			hc.Spec.Platform.Type = hypershift.AgentPlatform
+			Expect(client.Update(ctx, hc)).Should(Succeed())
+			reconciler.Clock = fixedClock{now: now.Add(time.Minute)}
+			requeueAfter = reconciler.reconcileClusterInfo(ctx, cti)
+			Expect(*requeueAfter).Should(Equal(clusterInfoRefreshInterval - time.Minute))
+			Expect(cti.Status.ClusterInfo.Platform).Should(Equal("AWS"))
+
+			reconciler.Clock = fixedClock{now: now.Add(clusterInfoRefreshInterval)}
+			requeueAfter = reconciler.reconcileClusterInfo(ctx, cti)
+			Expect(*requeueAfter).Should(Equal(clusterInfoRefreshInterval))
+			Expect(cti.Status.ClusterInfo.Platform).Should(Equal("Agent"))
+
+			cti.Status.Phase = v1alpha1.HibernatedPhase
+			Expect(reconciler.reconcileClusterInfo(ctx, cti)).Should(BeNil())
+		})
+
+		It("Resolves upgrade and tracks its progress", func() {
+			releaseImage := "quay.io/openshift-release-dev/ocp-release:4.12.2-x86_64"
+			ct := testutils.GetCT(false)
+			ct.Spec.Upgrades = &v1alpha1.ClusterTemplateUpgrades{
+				ReleaseImageParameter: "releaseImage",
+				Versions: []v1alpha1.ClusterTemplateUpgradeVersion{
+					{Version: "4.12.2", ReleaseImage: releaseImage},
+				},
+			}
+			cti := testutils.GetCTI()
+			cti.Spec.Upgrade = &v1alpha1.ClusterUpgradeTarget{Version: "4.12.3"}
+			app := testutils.GetApp()
+			app.Namespace = ArgoCDNamespace
+			app.Status.Resources = []argo.ResourceStatus{
+				{
+					Group:     "hypershift.openshift.io",
+					Version:   "v1beta1",
+					Kind:      "HostedCluster",
+					Name:      "foo",
+					Namespace: "default",
+				},
+			}
+			hc := &hypershift.HostedCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "foo",
+					Namespace: "default",
+				},
+				Spec: hypershift.HostedClusterSpec{
+					Release: hypershift.Release{Image: releaseImage},
+				},
+				Status: hypershift.HostedClusterStatus{
+					Version: &hypershift.ClusterVersionStatus{
+						History: []configv1.UpdateHistory{
+							{State: configv1.PartialUpdate, Version: "4.12.2", Image: releaseImage},
+						},
+					},
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, ct, app, hc)
+			reconciler := &ClusterTemplateInstanceReconciler{
+				Client: client,
+			}
+
+			Expect(reconciler.reconcileUpgrade(ctx, cti)).Should(Succeed())
+			Expect(cti.Status.Upgrade).Should(BeNil())
+			condition := meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.ClusterUpgrade))
+			Expect(condition.Reason).Should(Equal(string(v1alpha1.UpgradeNotAllowed)))
+
+			cti.Spec.Upgrade.Version = "4.12.2"
+			cti.SetClusterDefinitionCreatedCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ApplicationCreated,
+				"Cluster definition created",
+			)
+			Expect(reconciler.reconcileUpgrade(ctx, cti)).Should(Succeed())
+			Expect(cti.ParametersUpdateRequired(cti.Status.ParametersHash)).Should(BeTrue())
+			Expect(*cti.Status.Upgrade).Should(Equal(v1alpha1.ClusterUpgradeStatus{
+				Version:      "4.12.2",
+				ReleaseImage: releaseImage,
+				Parameter:    "releaseImage",
+			}))
+			condition = meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.ClusterUpgrade))
+			Expect(condition.Reason).Should(Equal(string(v1alpha1.UpgradePending)))
+
+			cti.SetClusterInstallCondition(
+				metav1.ConditionTrue,
+				v1alpha1.ClusterInstalled,
+				"Cluster is installed",
+			)
+			Expect(reconciler.reconcileUpgrade(ctx, cti)).Should(Succeed())
+			condition = meta.FindStatusCondition(cti.Status.Conditions, string(v1alpha1.ClusterUpgrade))
+			Expect(condition.Reason).Should(Equal(string(v1alpha1.ClusterUpgrading)))
+			Expect(condition.Message).Should(Equal("Upgrading to 4.12.2"))
+
+			hc.Status.Version.History[0].State = configv1.CompletedUpdate
+			Expect(client.Update(ctx, hc)).Should(Succeed())
+			Expect(reconciler.reconcileUpgrade(ctx, cti)).Should(Succeed())
+			Expect(meta.IsStatusConditionTrue(
+				cti.Status.Conditions,
+				string(v1alpha1.ClusterUpgrade),
+			)).Should(BeTrue())
+		})
+	})
+
 	Context("CTI delete", func() {
 		cti := testutils.GetCTI()
 		It("Handles missing Kubelet", func() {
@@ -1168,4 +1638,34 @@ var _ = Describe("ClusterTemplateInstance controller", func() {
 			Expect(err).ShouldNot(HaveOccurred())
 		})
 	})
+
+	Context("Quota watch", func() {
+		It("Enqueues only the instances started from the queue", func() {
+			oldQuota := &v1alpha1.ClusterTemplateQuota{
+				ObjectMeta: metav1.ObjectMeta{Name: "quota", Namespace: "default"},
+				Status: v1alpha1.ClusterTemplateQuotaStatus{
+					Queue: []v1alpha1.QueuedInstance{
+						{Namespace: "default", Name: "foo", ClusterTemplate: "ct"},
+						{Namespace: "default", Name: "bar", ClusterTemplate: "ct"},
+					},
+				},
+			}
+			newQuota := oldQuota.DeepCopy()
+			newQuota.Status.BudgetSpent = 2
+			Expect(quotaQueueChangedPredicate.Update(
+				event.UpdateEvent{ObjectOld: oldQuota, ObjectNew: newQuota},
+			)).Should(BeFalse())
+
+			newQuota.Status.Queue = newQuota.Status.Queue[1:]
+			Expect(quotaQueueChangedPredicate.Update(
+				event.UpdateEvent{ObjectOld: oldQuota, ObjectNew: newQuota},
+			)).Should(BeTrue())
+			Expect(MapQuotaQueueToInstances(oldQuota)).Should(ContainElement(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "foo"},
+			}))
+			Expect(MapQuotaQueueToInstances(newQuota)).Should(Equal([]reconcile.Request{{
+				NamespacedName: types.NamespacedName{Namespace: "default", Name: "bar"},
+			}}))
+		})
+	})
 })
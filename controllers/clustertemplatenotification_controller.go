@@ -0,0 +1,140 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/notification"
+)
+
+var CTNlog = logf.Log.WithName("ctn-controller")
+
+// ClusterTemplateNotificationReconciler delivers the pending notifications
+type ClusterTemplateNotificationReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Clock
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatenotifications,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatenotifications/status,verbs=get;update;patch
+
+func (r *ClusterTemplateNotificationReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	ctn := &v1alpha1.ClusterTemplateNotification{}
+	if err := r.Get(ctx, req.NamespacedName, ctn); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	now := r.Now()
+	var requeueAfter *time.Duration
+	for i := range ctn.Status.Deliveries {
+		delivery := ctn.Status.Deliveries[i]
+		if delivery.State != v1alpha1.PendingDeliveryState {
+			continue
+		}
+		if nextAttempt := delivery.NextAttempt(); now.Before(nextAttempt) {
+			wait := nextAttempt.Sub(now)
+			if requeueAfter == nil || wait < *requeueAfter {
+				requeueAfter = &wait
+			}
+			continue
+		}
+
+		err := notification.Send(ctx, r.Client, ctn, &delivery)
+		delivery.Attempts++
+		delivery.LastAttempt = &metav1.Time{Time: now}
+		if err == nil {
+			CTNlog.Info("Notification delivered", "name", ctn.Name, "instance", delivery.Instance, "event", delivery.Event)
+			delivery.State = v1alpha1.DeliveredDeliveryState
+			delivery.Error = ""
+		} else {
+			CTNlog.Info("Notification delivery failed", "name", ctn.Name, "instance", delivery.Instance, "error", err.Error())
+			delivery.Error = err.Error()
+			if delivery.Attempts > ctn.Spec.MaxRetries {
+				delivery.State = v1alpha1.FailedDeliveryState
+			} else {
+				wait := delivery.NextAttempt().Sub(now)
+				if requeueAfter == nil || wait < *requeueAfter {
+					requeueAfter = &wait
+				}
+			}
+		}
+
+		// The result is stored right after the attempt, so the delivered notifications are not
+		// sent again when the instance controller adds deliveries concurrently
+		if err := r.updateDelivery(ctx, req.NamespacedName, delivery); err != nil {
+			return ctrl.Result{}, fmt.Errorf(
+				"failed to update status of clustertemplatenotification %q: %w",
+				req.NamespacedName,
+				err,
+			)
+		}
+	}
+
+	if requeueAfter != nil {
+		return ctrl.Result{RequeueAfter: *requeueAfter}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// Stores the result of the delivery attempt in the current notification
+func (r *ClusterTemplateNotificationReconciler) updateDelivery(
+	ctx context.Context,
+	key client.ObjectKey,
+	delivery v1alpha1.NotificationDelivery,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		ctn := &v1alpha1.ClusterTemplateNotification{}
+		if err := r.Get(ctx, key, ctn); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		existing := ctn.GetDelivery(delivery)
+		if existing == nil {
+			return nil
+		}
+		*existing = delivery
+		return r.Status().Update(ctx, ctn)
+	})
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplateNotificationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if r.Clock == nil {
+		r.Clock = realClock{}
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterTemplateNotification{}).
+		Complete(r)
+}
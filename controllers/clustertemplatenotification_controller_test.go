@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/notification"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time { return c.now }
+
+func getNotification(url string) *v1alpha1.ClusterTemplateNotification {
+	return &v1alpha1.ClusterTemplateNotification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "notification",
+			Namespace: "default",
+		},
+		Spec: v1alpha1.ClusterTemplateNotificationSpec{
+			Events: []v1alpha1.NotificationEvent{
+				v1alpha1.ReadyNotificationEvent,
+				v1alpha1.ExpiringNotificationEvent,
+			},
+			Webhook:    &v1alpha1.WebhookNotification{URL: url},
+			MaxRetries: 1,
+		},
+	}
+}
+
+var _ = Describe("ClusterTemplateNotification controller", func() {
+	BeforeEach(func() {
+		// The test servers listen on the loopback
+		Expect(notification.SetAllowedNetworks([]string{"127.0.0.0/8"})).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(notification.SetAllowedNetworks(nil)).Should(Succeed())
+	})
+
+	It("Delivers pending notifications", func() {
+		calls := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		ctn := getNotification(server.URL)
+		ctn.AddDelivery(v1alpha1.NotificationDelivery{
+			Instance:  "foo",
+			Event:     v1alpha1.ReadyNotificationEvent,
+			CreatedAt: metav1.NewTime(now),
+		})
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, ctn)
+		reconciler := &ClusterTemplateNotificationReconciler{
+			Client: client,
+			Clock:  fixedClock{now: now},
+		}
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: ctn.Name, Namespace: ctn.Namespace},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).Should(BeZero())
+		Expect(calls).Should(Equal(1))
+
+		Expect(client.Get(ctx, types.NamespacedName{Name: ctn.Name, Namespace: ctn.Namespace}, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries[0].State).Should(Equal(v1alpha1.DeliveredDeliveryState))
+		Expect(ctn.Status.Deliveries[0].Attempts).Should(Equal(1))
+	})
+
+	It("Keeps delivered state when deliveries are added concurrently", func() {
+		var k8sClient client.Client
+		key := types.NamespacedName{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current := &v1alpha1.ClusterTemplateNotification{}
+			Expect(k8sClient.Get(ctx, key, current)).Should(Succeed())
+			current.AddDelivery(v1alpha1.NotificationDelivery{
+				Instance:  "bar",
+				Event:     v1alpha1.FailedNotificationEvent,
+				CreatedAt: metav1.NewTime(time.Now().Add(time.Hour)),
+			})
+			Expect(k8sClient.Status().Update(ctx, current)).Should(Succeed())
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		ctn := getNotification(server.URL)
+		ctn.AddDelivery(v1alpha1.NotificationDelivery{
+			Instance:  "foo",
+			Event:     v1alpha1.ReadyNotificationEvent,
+			CreatedAt: metav1.NewTime(now),
+		})
+		key = client.ObjectKeyFromObject(ctn)
+		k8sClient = fake.NewFakeClientWithScheme(scheme.Scheme, ctn)
+		reconciler := &ClusterTemplateNotificationReconciler{
+			Client: k8sClient,
+			Clock:  fixedClock{now: now},
+		}
+
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(k8sClient.Get(ctx, key, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(2))
+		Expect(ctn.Status.Deliveries[0].State).Should(Equal(v1alpha1.DeliveredDeliveryState))
+		Expect(ctn.Status.Deliveries[1].State).Should(Equal(v1alpha1.PendingDeliveryState))
+	})
+
+	It("Retries failed deliveries", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		now := time.Now()
+		ctn := getNotification(server.URL)
+		ctn.AddDelivery(v1alpha1.NotificationDelivery{
+			Instance:  "foo",
+			Event:     v1alpha1.ReadyNotificationEvent,
+			CreatedAt: metav1.NewTime(now),
+		})
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, ctn)
+		reconciler := &ClusterTemplateNotificationReconciler{
+			Client: client,
+			Clock:  fixedClock{now: now},
+		}
+		req := ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: ctn.Name, Namespace: ctn.Namespace},
+		}
+
+		result, err := reconciler.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).Should(Equal(30 * time.Second))
+		Expect(client.Get(ctx, req.NamespacedName, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries[0].State).Should(Equal(v1alpha1.PendingDeliveryState))
+		Expect(ctn.Status.Deliveries[0].Error).Should(ContainSubstring("503"))
+
+		reconciler.Clock = fixedClock{now: now.Add(30 * time.Second)}
+		result, err = reconciler.Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.RequeueAfter).Should(BeZero())
+		Expect(client.Get(ctx, req.NamespacedName, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries[0].State).Should(Equal(v1alpha1.FailedDeliveryState))
+		Expect(ctn.Status.Deliveries[0].Attempts).Should(Equal(2))
+	})
+
+	It("Adds notifications of instances", func() {
+		now := time.Now()
+		ctn := getNotification("https://example.com")
+		ctn.Spec.ExpiringBefore = &metav1.Duration{Duration: time.Hour}
+		cti := testutils.GetCTI()
+		cti.Status.Phase = v1alpha1.ReadyPhase
+		ctn.Namespace = cti.Namespace
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, ctn)
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: k8sClient,
+			Clock:  fixedClock{now: now},
+		}
+		key := client.ObjectKeyFromObject(ctn)
+
+		Expect(reconciler.notifyPhaseChange(ctx, cti, v1alpha1.ClusterSetupRunningPhase)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, key, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(1))
+		Expect(ctn.Status.Deliveries[0].Event).Should(Equal(v1alpha1.ReadyNotificationEvent))
+		Expect(cti.Status.NotifiedPhase).Should(Equal(v1alpha1.ReadyPhase))
+
+		// The notified phase is not notified again
+		Expect(reconciler.notifyPhaseChange(ctx, cti, v1alpha1.ReadyPhase)).Should(Succeed())
+		Expect(k8sClient.Get(ctx, key, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(1))
+
+		expiresAt := metav1.NewTime(now.Add(2 * time.Hour))
+		notifyAfter, err := reconciler.notifyExpiration(ctx, cti, &expiresAt)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*notifyAfter).Should(Equal(time.Hour))
+
+		reconciler.Clock = fixedClock{now: now.Add(time.Hour)}
+		notifyAfter, err = reconciler.notifyExpiration(ctx, cti, &expiresAt)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(notifyAfter).Should(BeNil())
+		_, err = reconciler.notifyExpiration(ctx, cti, &expiresAt)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, key, ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(2))
+		Expect(ctn.Status.Deliveries[1].Event).Should(Equal(v1alpha1.ExpiringNotificationEvent))
+	})
+
+	It("Retries phase notifications which failed", func() {
+		ctn := getNotification("https://example.com")
+		cti := testutils.GetCTI()
+		cti.Status.Phase = v1alpha1.ClusterSetupRunningPhase
+		cti.Status.NotifiedPhase = v1alpha1.ClusterSetupRunningPhase
+		ctn.Namespace = cti.Namespace
+		k8sClient := &failingListClient{
+			Client: fake.NewFakeClientWithScheme(scheme.Scheme, ctn),
+			err:    errors.New("list failed"),
+		}
+		reconciler := &ClusterTemplateInstanceReconciler{
+			Client: k8sClient,
+			Clock:  fixedClock{now: time.Now()},
+		}
+
+		cti.Status.Phase = v1alpha1.ReadyPhase
+		Expect(reconciler.notifyPhaseChange(ctx, cti, v1alpha1.ClusterSetupRunningPhase)).ShouldNot(Succeed())
+		Expect(cti.Status.NotifiedPhase).Should(Equal(v1alpha1.ClusterSetupRunningPhase))
+
+		// The next reconcile doesn't see the phase change anymore
+		k8sClient.err = nil
+		Expect(reconciler.notifyPhaseChange(ctx, cti, v1alpha1.ReadyPhase)).Should(Succeed())
+		Expect(cti.Status.NotifiedPhase).Should(Equal(v1alpha1.ReadyPhase))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ctn), ctn)).Should(Succeed())
+		Expect(ctn.Status.Deliveries).Should(HaveLen(1))
+		Expect(ctn.Status.Deliveries[0].Event).Should(Equal(v1alpha1.ReadyNotificationEvent))
+	})
+})
+
+// failingListClient fails to list objects while the error is set
+type failingListClient struct {
+	client.Client
+	err error
+}
+
+func (c *failingListClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Client.List(ctx, list, opts...)
+}
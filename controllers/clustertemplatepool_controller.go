@@ -0,0 +1,209 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+var (
+	CTPlog = logf.Log.WithName("ctp-controller")
+
+	// Namespace of the pooled instances and their secrets, created by the operator
+	PoolNamespace = defaultPoolNs
+
+	poolClaimRequeueInterval = 10 * time.Second
+)
+
+// ClusterTemplatePoolReconciler reconciles a ClusterTemplatePool object
+type ClusterTemplatePoolReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatepools,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;create
+
+func (r *ClusterTemplatePoolReconciler) Reconcile(
+	ctx context.Context,
+	req ctrl.Request,
+) (ctrl.Result, error) {
+	pool := &v1alpha1.ClusterTemplatePool{}
+	if err := r.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	ctis := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := r.List(
+		ctx,
+		ctis,
+		client.InNamespace(PoolNamespace),
+		client.MatchingLabels{v1alpha1.CTIPoolLabel: pool.Name},
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	available := []v1alpha1.ClusterTemplateInstance{}
+	pendingClaims := false
+	for _, cti := range ctis.Items {
+		if cti.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if claimedBy, ok := cti.Annotations[v1alpha1.CTIPoolClaimAnnotation]; ok {
+			adopted, err := r.isAdopted(ctx, claimedBy)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !adopted {
+				pendingClaims = true
+				continue
+			}
+			CTPlog.Info("Remove claimed instance from pool", "name", pool.Name, "instance", cti.Name, "claimedBy", claimedBy)
+			if err := r.Delete(ctx, &cti); err != nil && !apierrors.IsNotFound(err) {
+				return ctrl.Result{}, err
+			}
+			continue
+		}
+		available = append(available, cti)
+	}
+
+	// Keep the clusters which are ready to be claimed, remove the installing ones first
+	sort.SliceStable(available, func(i, j int) bool {
+		return available[i].Status.Phase == v1alpha1.PooledPhase &&
+			available[j].Status.Phase != v1alpha1.PooledPhase
+	})
+	for len(available) > pool.Spec.Size {
+		cti := available[len(available)-1]
+		CTPlog.Info("Scale down pool", "name", pool.Name, "instance", cti.Name)
+		// Fails with conflict if the instance was claimed in the meantime
+		if err := r.Delete(
+			ctx,
+			&cti,
+			client.Preconditions{ResourceVersion: &cti.ResourceVersion},
+		); err != nil && !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		available = available[:len(available)-1]
+	}
+
+	ready := 0
+	for _, cti := range available {
+		if cti.Status.Phase == v1alpha1.PooledPhase {
+			ready++
+		}
+	}
+
+	if len(available) < pool.Spec.Size {
+		if err := r.ensurePoolNamespace(ctx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	for i := len(available); i < pool.Spec.Size; i++ {
+		cti := pool.GetPooledInstance(PoolNamespace)
+		CTPlog.Info("Scale up pool", "name", pool.Name)
+		if err := r.Create(ctx, cti); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to create pooled instance - %q", err)
+		}
+		available = append(available, *cti)
+	}
+
+	pool.Status.Size = len(available)
+	pool.Status.Ready = ready
+	if err := r.Status().Update(ctx, pool); err != nil {
+		return ctrl.Result{}, fmt.Errorf(
+			"failed to update status of clustertemplatepool %q: %w",
+			req.NamespacedName,
+			err,
+		)
+	}
+
+	// The claimed instance is removed once the claiming instance recorded the adopted cluster
+	if pendingClaims {
+		return ctrl.Result{RequeueAfter: poolClaimRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// Creates the namespace of the pooled instances if it does not exist yet
+func (r *ClusterTemplatePoolReconciler) ensurePoolNamespace(ctx context.Context) error {
+	ns := &corev1.Namespace{}
+	err := r.Get(ctx, types.NamespacedName{Name: PoolNamespace}, ns)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+	ns.Name = PoolNamespace
+	if err := r.Create(ctx, ns); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create pool namespace - %q", err)
+	}
+	return nil
+}
+
+// Returns true if the claiming instance took over the pooled cluster or if it does not exist anymore
+func (r *ClusterTemplatePoolReconciler) isAdopted(ctx context.Context, claimedBy string) (bool, error) {
+	nsName := strings.SplitN(claimedBy, "/", 2)
+	if len(nsName) != 2 {
+		return true, nil
+	}
+	cti := &v1alpha1.ClusterTemplateInstance{}
+	if err := r.Get(ctx, types.NamespacedName{Namespace: nsName[0], Name: nsName[1]}, cti); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return cti.Status.TemplateSnapshot != nil, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterTemplatePoolReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	mapInstanceToPool := func(instance client.Object) []reconcile.Request {
+		poolName, ok := instance.GetLabels()[v1alpha1.CTIPoolLabel]
+		if !ok || instance.GetNamespace() != PoolNamespace {
+			return []reconcile.Request{}
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: poolName}}}
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1alpha1.ClusterTemplatePool{}).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplateInstance{}},
+			handler.EnqueueRequestsFromMapFunc(mapInstanceToPool)).
+		Complete(r)
+}
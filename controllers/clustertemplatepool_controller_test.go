@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/testutils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClusterTemplatePool controller", func() {
+	Context("Pool size", func() {
+		ct := &v1alpha1.ClusterTemplate{}
+		pool := &v1alpha1.ClusterTemplatePool{}
+		appset := &argo.ApplicationSet{}
+
+		BeforeEach(func() {
+			appset = testutils.GetAppset()
+			Expect(k8sClient.Create(ctx, appset)).Should(Succeed())
+			ct = testutils.GetCT(false)
+			Expect(k8sClient.Create(ctx, ct)).Should(Succeed())
+			pool = &v1alpha1.ClusterTemplatePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool",
+				},
+				Spec: v1alpha1.ClusterTemplatePoolSpec{
+					ClusterTemplateRef: ct.Name,
+					Size:               2,
+				},
+			}
+			Expect(k8sClient.Create(ctx, pool)).Should(Succeed())
+		})
+
+		AfterEach(func() {
+			testutils.DeleteResource(ctx, pool, k8sClient)
+			testutils.DeleteResource(ctx, ct, k8sClient)
+			testutils.DeleteResource(ctx, appset, k8sClient)
+		})
+
+		getPooledInstances := func() int {
+			ctis := &v1alpha1.ClusterTemplateInstanceList{}
+			if err := k8sClient.List(
+				ctx,
+				ctis,
+				client.InNamespace(PoolNamespace),
+				client.MatchingLabels{v1alpha1.CTIPoolLabel: pool.Name},
+			); err != nil {
+				return -1
+			}
+			count := 0
+			for _, cti := range ctis.Items {
+				if cti.GetDeletionTimestamp() == nil {
+					count++
+				}
+			}
+			return count
+		}
+
+		It("Should create pooled instances", func() {
+			Eventually(getPooledInstances, timeout, interval).Should(Equal(2))
+			Eventually(func() int {
+				if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pool), pool); err != nil {
+					return 0
+				}
+				return pool.Status.Size
+			}, timeout, interval).Should(Equal(2))
+		})
+
+		It("Should scale down the pool", func() {
+			Eventually(getPooledInstances, timeout, interval).Should(Equal(2))
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pool), pool)).Should(Succeed())
+			pool.Spec.Size = 1
+			Expect(k8sClient.Update(ctx, pool)).Should(Succeed())
+			Eventually(getPooledInstances, timeout, interval).Should(Equal(1))
+		})
+	})
+
+	Context("Pool namespace", func() {
+		It("Creates pooled instances in the pool namespace", func() {
+			pool := &v1alpha1.ClusterTemplatePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "pool",
+				},
+				Spec: v1alpha1.ClusterTemplatePoolSpec{
+					ClusterTemplateRef: "foo",
+					Size:               1,
+				},
+			}
+			client := fake.NewFakeClientWithScheme(scheme.Scheme, pool)
+			reconciler := &ClusterTemplatePoolReconciler{
+				Client: client,
+			}
+			_, err := reconciler.Reconcile(
+				ctx,
+				ctrl.Request{NamespacedName: types.NamespacedName{Name: pool.Name}},
+			)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(PoolNamespace).ShouldNot(Equal(ArgoCDNamespace))
+			ns := &corev1.Namespace{}
+			Expect(client.Get(ctx, types.NamespacedName{Name: PoolNamespace}, ns)).Should(Succeed())
+			ctis := &v1alpha1.ClusterTemplateInstanceList{}
+			Expect(client.List(ctx, ctis)).Should(Succeed())
+			Expect(ctis.Items).Should(HaveLen(1))
+			Expect(ctis.Items[0].Namespace).Should(Equal(PoolNamespace))
+		})
+	})
+})
@@ -18,7 +18,11 @@ package controllers
 
 import (
 	"context"
+	"math"
+	"sort"
+	"time"
 
+	"golang.org/x/exp/slices"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -40,6 +44,9 @@ type ClusterTemplateQuotaReconciler struct {
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplatequotas/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateinstances,verbs=get;list;watch
 // +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas,verbs=get;list;watch
+// +kubebuilder:rbac:groups=clustertemplate.openshift.io,resources=clustertemplateclusterquotas/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 func (r *ClusterTemplateQuotaReconciler) Reconcile(
 	ctx context.Context,
@@ -63,25 +70,27 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 		return ctrl.Result{}, err
 	}
 
+	// Queued instances are counted once they are started
+	queue, requeueAfter := getPendingQueue(clusterTemplateQuota.Status.Queue, clusterTemplateInstanceList.Items)
+	clusterTemplateQuota.Status.Queue = queue
+
 	currentInstances := []v1alpha1.AllowedTemplateStatus{}
 	currentConst := 0
+	requesters := map[string]*v1alpha1.RequesterStatus{}
 	for _, template := range clusterTemplateQuota.Spec.AllowedTemplates {
 		count := 0
-
-		templateCost := -1
-		for _, cTemplate := range clusterTemplateList.Items {
-			if cTemplate.Name == template.Name && cTemplate.Spec.Cost != nil {
-				templateCost = *cTemplate.Spec.Cost
-				break
-			}
-		}
+		templateCost := getTemplateCost(clusterTemplateList.Items, template.Name)
 
 		for _, instance := range clusterTemplateInstanceList.Items {
-			if instance.Spec.ClusterTemplateRef == template.Name {
+			if instance.Spec.ClusterTemplateRef == template.Name &&
+				!clusterTemplateQuota.IsQueued(instance.Namespace, instance.Name) {
 				count++
-				if templateCost != -1 {
-					currentConst += templateCost
+				instanceCost := getInstanceCost(&instance, templateCost)
+				if percentage := clusterTemplateQuota.Spec.HibernatedCostPercentage; percentage != nil && instance.IsHibernated() {
+					instanceCost = instanceCost * *percentage / 100
 				}
+				currentConst += instanceCost
+				addRequesterInstance(requesters, &instance, instanceCost)
 			}
 		}
 
@@ -91,18 +100,284 @@ func (r *ClusterTemplateQuotaReconciler) Reconcile(
 		})
 	}
 
+	reservations, reservationsRequeueAfter := getPendingReservations(
+		clusterTemplateQuota.Status.Reservations,
+		clusterTemplateInstanceList.Items,
+	)
+	if reservationsRequeueAfter != nil && (requeueAfter == nil || *reservationsRequeueAfter < *requeueAfter) {
+		requeueAfter = reservationsRequeueAfter
+	}
+
+	var billing *v1alpha1.BillingStatus
+	if clusterTemplateQuota.Spec.Billing != nil {
+		var billingRequeueAfter time.Duration
+		billing, billingRequeueAfter = getBillingStatus(
+			clusterTemplateQuota,
+			clusterTemplateInstanceList.Items,
+			clusterTemplateList.Items,
+		)
+		if requeueAfter == nil || billingRequeueAfter < *requeueAfter {
+			requeueAfter = &billingRequeueAfter
+		}
+	}
+
 	clusterTemplateQuota.Status = v1alpha1.ClusterTemplateQuotaStatus{
 		BudgetSpent:       currentConst,
 		TemplateInstances: currentInstances,
+		Requesters:        getRequesterStatuses(requesters),
+		Reservations:      reservations,
+		Billing:           billing,
+		Queue:             queue,
 	}
+	startQueuedInstances(
+		ctx,
+		r.Client,
+		clusterTemplateQuota,
+		requesters,
+		clusterTemplateInstanceList.Items,
+		clusterTemplateList.Items,
+	)
 
 	if err := r.Status().Update(ctx, clusterTemplateQuota); err != nil {
 		return ctrl.Result{}, err
 	}
 
+	if requeueAfter != nil {
+		return ctrl.Result{RequeueAfter: *requeueAfter}, nil
+	}
 	return ctrl.Result{}, nil
 }
 
+// Returns the reservations of instances which are not counted yet and the time after which the
+// oldest of them expires. Reservations of counted instances and expired reservations are released.
+func getPendingReservations(
+	reservations []v1alpha1.QuotaReservation,
+	instances []v1alpha1.ClusterTemplateInstance,
+) ([]v1alpha1.QuotaReservation, *time.Duration) {
+	now := time.Now()
+	var requeueAfter *time.Duration
+	pending := []v1alpha1.QuotaReservation{}
+	for _, reservation := range reservations {
+		if reservation.IsExpired(now) {
+			continue
+		}
+		counted := false
+		for _, instance := range instances {
+			if instance.Namespace == reservation.Namespace && instance.Name == reservation.Name {
+				counted = true
+				break
+			}
+		}
+		if counted {
+			continue
+		}
+		pending = append(pending, reservation)
+		expiresIn := reservation.ReservedAt.Add(v1alpha1.QuotaReservationTimeout).Sub(now)
+		if requeueAfter == nil || expiresIn < *requeueAfter {
+			requeueAfter = &expiresIn
+		}
+	}
+	return pending, requeueAfter
+}
+
+// Returns the queued instances which were not started yet and the time after which the oldest
+// of the instances which were not created expires. Removed instances and instances which started
+// already are dropped from the queue.
+func getPendingQueue(
+	queue []v1alpha1.QueuedInstance,
+	instances []v1alpha1.ClusterTemplateInstance,
+) ([]v1alpha1.QueuedInstance, *time.Duration) {
+	now := time.Now()
+	var requeueAfter *time.Duration
+	pending := []v1alpha1.QueuedInstance{}
+	for _, queued := range queue {
+		instance := findInstance(instances, queued.Namespace, queued.Name)
+		if instance == nil {
+			if queued.IsExpired(now) {
+				continue
+			}
+			expiresIn := queued.QueuedAt.Add(v1alpha1.QuotaReservationTimeout).Sub(now)
+			if requeueAfter == nil || expiresIn < *requeueAfter {
+				requeueAfter = &expiresIn
+			}
+		} else if instance.DeletionTimestamp != nil || instance.Status.TemplateSnapshot != nil {
+			continue
+		}
+		pending = append(pending, queued)
+	}
+	return pending, requeueAfter
+}
+
+// Starts the queued instances in the order of the queue while they fit in the quota and in the
+// cluster quotas which select the namespace, the cost of started instances is reserved in the
+// cluster quotas. The instances behind an instance which does not fit keep waiting.
+func startQueuedInstances(
+	ctx context.Context,
+	k8sClient client.Client,
+	quota *v1alpha1.ClusterTemplateQuota,
+	requesters map[string]*v1alpha1.RequesterStatus,
+	instances []v1alpha1.ClusterTemplateInstance,
+	templates []v1alpha1.ClusterTemplate,
+) {
+	for len(quota.Status.Queue) > 0 {
+		queued := quota.Status.Queue[0]
+		instance := findInstance(instances, queued.Namespace, queued.Name)
+		// The instance is not created yet
+		if instance == nil {
+			return
+		}
+		cost := getInstanceCost(instance, getTemplateCost(templates, queued.ClusterTemplate))
+		if err := instance.CheckNamespaceQuota(quota, cost); err != nil {
+			return
+		}
+		// The instance waits until the cluster quotas have capacity for it
+		if err := instance.ReserveClusterQuotas(ctx, k8sClient, cost, false); err != nil {
+			return
+		}
+
+		quota.Status.Queue = quota.Status.Queue[1:]
+		quota.Status.BudgetSpent += cost
+		for i := range quota.Status.TemplateInstances {
+			if quota.Status.TemplateInstances[i].Name == queued.ClusterTemplate {
+				quota.Status.TemplateInstances[i].Count++
+			}
+		}
+		addRequesterInstance(requesters, instance, cost)
+		quota.Status.Requesters = getRequesterStatuses(requesters)
+	}
+}
+
+func findInstance(
+	instances []v1alpha1.ClusterTemplateInstance,
+	namespace string,
+	name string,
+) *v1alpha1.ClusterTemplateInstance {
+	for i := range instances {
+		if instances[i].Namespace == namespace && instances[i].Name == name {
+			return &instances[i]
+		}
+	}
+	return nil
+}
+
+// Returns the spend of the requesters sorted by their names
+func getRequesterStatuses(requesters map[string]*v1alpha1.RequesterStatus) []v1alpha1.RequesterStatus {
+	requesterStatuses := []v1alpha1.RequesterStatus{}
+	for _, requester := range requesters {
+		requesterStatuses = append(requesterStatuses, *requester)
+	}
+	sort.Slice(requesterStatuses, func(i, j int) bool {
+		return requesterStatuses[i].Name < requesterStatuses[j].Name
+	})
+	return requesterStatuses
+}
+
+// Counts the instance and its cost to the spend of its requester
+func addRequesterInstance(
+	requesters map[string]*v1alpha1.RequesterStatus,
+	instance *v1alpha1.ClusterTemplateInstance,
+	cost int,
+) {
+	name := instance.Annotations[v1alpha1.CTIRequesterAnnotation]
+	requester, ok := requesters[name]
+	if !ok {
+		requester = &v1alpha1.RequesterStatus{
+			Name:              name,
+			TemplateInstances: []v1alpha1.AllowedTemplateStatus{},
+		}
+		requesters[name] = requester
+	}
+	requester.BudgetSpent += cost
+	for i := range requester.TemplateInstances {
+		if requester.TemplateInstances[i].Name == instance.Spec.ClusterTemplateRef {
+			requester.TemplateInstances[i].Count++
+			return
+		}
+	}
+	requester.TemplateInstances = append(requester.TemplateInstances, v1alpha1.AllowedTemplateStatus{
+		Name:  instance.Spec.ClusterTemplateRef,
+		Count: 1,
+	})
+}
+
+// Returns the hourly cost accumulated in the current billing period and the time after which the
+// next hour of an instance starts or the period ends
+func getBillingStatus(
+	quota *v1alpha1.ClusterTemplateQuota,
+	instances []v1alpha1.ClusterTemplateInstance,
+	templates []v1alpha1.ClusterTemplate,
+) (*v1alpha1.BillingStatus, time.Duration) {
+	now := time.Now()
+	billing := quota.GetBillingStatus(now)
+	requeueAfter := quota.Spec.Billing.Period.End(now).Sub(now)
+
+	billing.Spent = billing.Settled
+	settledInstances := []types.UID{}
+	for _, instance := range instances {
+		if !quota.AllowsTemplate(instance.Spec.ClusterTemplateRef) {
+			continue
+		}
+		// Removed instances which were settled already, the others are settled once removed
+		if slices.Contains(billing.SettledInstances, instance.UID) {
+			settledInstances = append(settledInstances, instance.UID)
+			continue
+		}
+		// Instances are billed once their provisioning starts
+		provisioningStart := instance.GetProvisioningStart()
+		if provisioningStart == nil {
+			continue
+		}
+		end := now
+		if instance.DeletionTimestamp != nil {
+			end = instance.DeletionTimestamp.Time
+		}
+		costPerHour := instance.GetCostPerHour(templates)
+		billing.Spent += v1alpha1.BilledCost(
+			costPerHour,
+			provisioningStart.Time,
+			billing.PeriodStart.Time,
+			end,
+		)
+
+		if costPerHour > 0 && instance.DeletionTimestamp == nil {
+			start := provisioningStart.Time
+			if start.Before(billing.PeriodStart.Time) {
+				start = billing.PeriodStart.Time
+			}
+			nextHour := start.Add(time.Duration(math.Floor(now.Sub(start).Hours())+1) * time.Hour)
+			if nextHour.Sub(now) < requeueAfter {
+				requeueAfter = nextHour.Sub(now)
+			}
+		}
+	}
+	billing.SettledInstances = settledInstances
+	return billing, requeueAfter
+}
+
+// Returns the cost of the template, -1 if the template does not exist or has no cost
+func getTemplateCost(templates []v1alpha1.ClusterTemplate, name string) int {
+	for _, template := range templates {
+		if cost := template.GetEffectiveSpec().Cost; template.Name == name && cost != nil {
+			return *cost
+		}
+	}
+	return -1
+}
+
+// Instances keep the cost of the template they were created from
+func getInstanceCost(instance *v1alpha1.ClusterTemplateInstance, templateCost int) int {
+	if snapshot := instance.Status.TemplateSnapshot; snapshot != nil {
+		if snapshot.Cost != nil {
+			return *snapshot.Cost
+		}
+		return 0
+	}
+	if templateCost != -1 {
+		return templateCost
+	}
+	return 0
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ClusterTemplateQuotaReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
@@ -129,10 +404,32 @@ func (r *ClusterTemplateQuotaReconciler) SetupWithManager(mgr ctrl.Manager) erro
 		return reply
 	}
 
+	// Queued instances may start once the cluster quotas have capacity for them
+	mapClusterQuotaToQueuingQuotas := func(clusterQuota client.Object) []reconcile.Request {
+		quotas := &v1alpha1.ClusterTemplateQuotaList{}
+		if err := r.List(context.Background(), quotas); err != nil {
+			return []reconcile.Request{}
+		}
+
+		reply := []reconcile.Request{}
+		for _, quota := range quotas.Items {
+			if len(quota.Status.Queue) > 0 {
+				reply = append(reply, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: quota.Namespace,
+					Name:      quota.Name,
+				}})
+			}
+		}
+		return reply
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&v1alpha1.ClusterTemplateQuota{}).
 		Watches(
 			&source.Kind{Type: &v1alpha1.ClusterTemplateInstance{}},
 			handler.EnqueueRequestsFromMapFunc(mapInstanceToQuota)).
+		Watches(
+			&source.Kind{Type: &v1alpha1.ClusterTemplateClusterQuota{}},
+			handler.EnqueueRequestsFromMapFunc(mapClusterQuotaToQueuingQuotas)).
 		Complete(r)
 }
@@ -1,15 +1,20 @@
 package controllers
 
 import (
+	"time"
+
 	argo "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/testutils"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 var _ = Describe("ClusterTemplateQuota controller", func() {
@@ -63,6 +68,28 @@ var _ = Describe("ClusterTemplateQuota controller", func() {
 			Expect(ctq.Status.TemplateInstances[0].Name).Should(Equal("mytemplate"))
 		})
 
+		It("Should report spend of the requester", func() {
+			cti.Annotations = map[string]string{
+				v1alpha1.CTIRequesterAnnotation: "foo",
+			}
+			Expect(k8sClient.Create(ctx, cti)).Should(Succeed())
+			Eventually(func() bool {
+				err := k8sClient.Get(ctx, client.ObjectKeyFromObject(ctq), ctq)
+				if err != nil {
+					return false
+				}
+				return len(ctq.Status.Requesters) == 1
+			}, timeout, interval).Should(BeTrue())
+			Expect(ctq.Status.Requesters[0].Name).Should(Equal("foo"))
+			Expect(ctq.Status.Requesters[0].BudgetSpent).Should(Equal(1))
+			Expect(ctq.Status.Requesters[0].TemplateInstances).Should(Equal([]v1alpha1.AllowedTemplateStatus{
+				{
+					Name:  "mytemplate",
+					Count: 1,
+				},
+			}))
+		})
+
 	})
 	Context("Initial ClusterTemplateQuota Status no cost", func() {
 		ct := &v1alpha1.ClusterTemplate{}
@@ -110,4 +137,150 @@ var _ = Describe("ClusterTemplateQuota controller", func() {
 			Expect(len(ctq.Status.TemplateInstances)).Should(Equal(1))
 		})
 	})
+	It("Releases counted and expired reservations", func() {
+		now := time.Now()
+		reservations := []v1alpha1.QuotaReservation{
+			{
+				Namespace:  "foo",
+				Name:       "counted",
+				ReservedAt: metav1.NewTime(now),
+			},
+			{
+				Namespace:  "foo",
+				Name:       "expired",
+				ReservedAt: metav1.NewTime(now.Add(-2 * v1alpha1.QuotaReservationTimeout)),
+			},
+			{
+				Namespace:  "foo",
+				Name:       "pending",
+				ReservedAt: metav1.NewTime(now),
+			},
+		}
+		instances := []v1alpha1.ClusterTemplateInstance{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      "counted",
+				},
+			},
+		}
+		pending, requeueAfter := getPendingReservations(reservations, instances)
+		Expect(len(pending)).Should(Equal(1))
+		Expect(pending[0].Name).Should(Equal("pending"))
+		Expect(requeueAfter).ShouldNot(BeNil())
+		Expect(*requeueAfter <= v1alpha1.QuotaReservationTimeout).Should(BeTrue())
+	})
+
+	It("Starts queued instances which fit in the quota", func() {
+		cost := 2
+		templates := []v1alpha1.ClusterTemplate{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "foo-tmp",
+				},
+				Spec: v1alpha1.ClusterTemplateSpec{
+					Cost: &cost,
+				},
+			},
+		}
+		instances := []v1alpha1.ClusterTemplateInstance{}
+		for _, name := range []string{"first", "second"} {
+			instances = append(instances, v1alpha1.ClusterTemplateInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "foo",
+					Name:      name,
+				},
+				Spec: v1alpha1.ClusterTemplateInstanceSpec{
+					ClusterTemplateRef: "foo-tmp",
+				},
+			})
+		}
+		quota := &v1alpha1.ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "foo",
+				Name:      "quota",
+			},
+			Spec: v1alpha1.ClusterTemplateQuotaSpec{
+				Budget: 3,
+				AllowedTemplates: []v1alpha1.AllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+				QueueInstances: true,
+			},
+			Status: v1alpha1.ClusterTemplateQuotaStatus{
+				TemplateInstances: []v1alpha1.AllowedTemplateStatus{
+					{
+						Name:  "foo-tmp",
+						Count: 0,
+					},
+				},
+			},
+		}
+		now := time.Now()
+		queue := []v1alpha1.QueuedInstance{
+			{
+				Namespace:       "foo",
+				Name:            "first",
+				ClusterTemplate: "foo-tmp",
+				QueuedAt:        metav1.NewTime(now),
+			},
+			{
+				Namespace:       "foo",
+				Name:            "second",
+				ClusterTemplate: "foo-tmp",
+				QueuedAt:        metav1.NewTime(now),
+			},
+			{
+				Namespace:       "foo",
+				Name:            "expired",
+				ClusterTemplate: "foo-tmp",
+				QueuedAt:        metav1.NewTime(now.Add(-2 * v1alpha1.QuotaReservationTimeout)),
+			},
+		}
+		quota.Status.Queue, _ = getPendingQueue(queue, instances)
+		Expect(len(quota.Status.Queue)).Should(Equal(2))
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foo",
+			},
+		}
+		clusterQuota := &v1alpha1.ClusterTemplateClusterQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "team-quota",
+			},
+			Spec: v1alpha1.ClusterTemplateClusterQuotaSpec{
+				Budget: 3,
+				AllowedTemplates: []v1alpha1.ClusterQuotaAllowedTemplate{
+					{
+						Name: "foo-tmp",
+					},
+				},
+			},
+			Status: v1alpha1.ClusterTemplateClusterQuotaStatus{
+				BudgetSpent: 2,
+			},
+		}
+		k8sClient := fake.NewFakeClientWithScheme(scheme.Scheme, namespace, clusterQuota)
+
+		// The cluster quota has no capacity for the first instance
+		startQueuedInstances(ctx, k8sClient, quota, map[string]*v1alpha1.RequesterStatus{}, instances, templates)
+		Expect(quota.Status.BudgetSpent).Should(Equal(0))
+		Expect(len(quota.Status.Queue)).Should(Equal(2))
+
+		clusterQuota.Status.BudgetSpent = 0
+		Expect(k8sClient.Status().Update(ctx, clusterQuota)).Should(Succeed())
+		startQueuedInstances(ctx, k8sClient, quota, map[string]*v1alpha1.RequesterStatus{}, instances, templates)
+		Expect(quota.Status.BudgetSpent).Should(Equal(2))
+		Expect(quota.Status.TemplateInstances[0].Count).Should(Equal(1))
+		Expect(len(quota.Status.Queue)).Should(Equal(1))
+		Expect(quota.Status.Queue[0].Name).Should(Equal("second"))
+
+		// The started instance is reserved in the cluster quota
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(clusterQuota), clusterQuota)).Should(Succeed())
+		Expect(len(clusterQuota.Status.Reservations)).Should(Equal(1))
+		Expect(clusterQuota.Status.Reservations[0].Name).Should(Equal("first"))
+	})
 })
@@ -17,12 +17,14 @@ import (
 
 	argo "github.com/argoproj-labs/argocd-operator/api/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/notification"
 )
 
 const (
 	configName = "config"
 
 	defaultArgoCDNs         = "cluster-aas-operator"
+	defaultPoolNs           = "cluster-templates-pool"
 	defaultEnableUI         = true
 	defaultUIImage          = "quay.io/stolostron/cluster-templates-console-plugin:2.8.1-5ad79eb6b4d9533754364d19c6ef2b91e11807a7"
 	argosyncNamePlaceholder = "~~argosync~~"
@@ -50,6 +52,7 @@ func getDefaultConfig() *v1alpha1.Config {
 		},
 		Spec: v1alpha1.ConfigSpec{
 			ArgoCDNamespace: defaultArgoCDNs,
+			PoolNamespace:   defaultPoolNs,
 			UIImage:         defaultUIImage,
 			UIEnabled:       defaultEnableUI,
 		},
@@ -80,6 +83,12 @@ func (r *ConfigReconciler) Reconcile(
 		EnableArgoconfigSync <- event.GenericEvent{Object: &argo.ArgoCD{ObjectMeta: metav1.ObjectMeta{Name: argosyncNamePlaceholder, Namespace: prevNs}}}
 	}
 
+	if config.Spec.PoolNamespace != "" {
+		PoolNamespace = config.Spec.PoolNamespace
+	} else {
+		PoolNamespace = defaultPoolNs
+	}
+
 	if EnableUI != config.Spec.UIEnabled || UIImage != config.Spec.UIImage {
 		EnableUI = config.Spec.UIEnabled
 		UIImage = config.Spec.UIImage
@@ -92,6 +101,10 @@ func (r *ConfigReconciler) Reconcile(
 		LoginAttemptTimeout = &metav1.Duration{Duration: time.Minute * 10}
 	}
 
+	if err := notification.SetAllowedNetworks(config.Spec.NotificationAllowedNetworks); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -44,6 +44,7 @@ import (
 	hypershiftv1beta1 "github.com/openshift/hypershift/api/v1beta1"
 	operators "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+	"github.com/stolostron/cluster-templates-operator/clusterprovider"
 	testutils "github.com/stolostron/cluster-templates-operator/testutils"
 	agent "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -129,7 +130,14 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).ToNot(HaveOccurred())
 
-	controllerCancel = StartCTIController(k8sManager, true, false, false, false)
+	controllerCancel = StartCTIController(
+		k8sManager,
+		[]clusterprovider.ProviderDefinition{
+			*clusterprovider.GetProvider(clusterprovider.HostedClusterProviderName),
+		},
+		false,
+		false,
+	)
 
 	err = (&ConfigReconciler{
 		Client: k8sManager.GetClient(),
@@ -149,6 +157,18 @@ var _ = BeforeSuite(func() {
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&ClusterTemplateClusterQuotaReconciler{
+		Client: k8sManager.GetClient(),
+		Scheme: k8sManager.GetScheme(),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&ClusterTemplatePoolReconciler{
+		Client: k8sManager.GetClient(),
+		Scheme: k8sManager.GetScheme(),
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	err = (&ClusterTemplateReconciler{
 		Client: k8sManager.GetClient(),
 		Scheme: k8sManager.GetScheme(),
@@ -42,9 +42,11 @@ import (
 	v1alpha1 "github.com/stolostron/cluster-templates-operator/api/v1alpha1"
 	"github.com/stolostron/cluster-templates-operator/bridge"
 	"github.com/stolostron/cluster-templates-operator/controllers"
+	"github.com/stolostron/cluster-templates-operator/metrics"
 	agent "github.com/stolostron/klusterlet-addon-controller/pkg/apis"
 	apiextensions "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	ocmv1 "open-cluster-management.io/api/cluster/v1"
+	capiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -59,6 +61,7 @@ func init() {
 	utilruntime.Must(hypershiftv1beta1.AddToScheme(scheme))
 	utilruntime.Must(argo.AddToScheme(scheme))
 	utilruntime.Must(hivev1.AddToScheme(scheme))
+	utilruntime.Must(capiv1beta1.AddToScheme(scheme))
 	utilruntime.Must(openshiftAPI.AddToScheme(scheme))
 	utilruntime.Must(apiextensions.AddToScheme(scheme))
 	utilruntime.Must(console.AddToScheme(scheme))
@@ -134,6 +137,27 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterTemplateQuota")
 		os.Exit(1)
 	}
+	if err = (&controllers.ClusterTemplateClusterQuotaReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterTemplateClusterQuota")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterTemplatePoolReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterTemplatePool")
+		os.Exit(1)
+	}
+	if err = (&controllers.ClusterTemplateNotificationReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterTemplateNotification")
+		os.Exit(1)
+	}
 
 	if err = (&controllers.ClusterTemplateReconciler{
 		Client: mgr.GetClient(),
@@ -184,6 +208,19 @@ func main() {
 			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateInstance")
 			os.Exit(1)
 		}
+		if err = (&v1alpha1.ClusterTemplateInstanceApproval{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateInstanceApproval")
+			os.Exit(1)
+		}
+		if err = (&v1alpha1.ClusterTemplateNotification{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "ClusterTemplateNotification")
+			os.Exit(1)
+		}
+	}
+
+	if err = metrics.RegisterStateCollector(mgr.GetClient()); err != nil {
+		setupLog.Error(err, "unable to register metrics collector")
+		os.Exit(1)
 	}
 
 	//+kubebuilder:scaffold:builder
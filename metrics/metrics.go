@@ -0,0 +1,301 @@
+/*
+Copyright 2022.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+var (
+	metricsLog = logf.Log.WithName("metrics")
+
+	// Timeout of listing the instances and quotas when the metrics are scraped
+	collectTimeout = 10 * time.Second
+
+	ProvisioningDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "clustertemplate_instance_provisioning_duration_seconds",
+			Help:    "Time from the creation of the cluster template instance until its cluster is ready",
+			Buckets: []float64{60, 300, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200, 10800},
+		},
+		[]string{"template"},
+	)
+	PhaseDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "clustertemplate_instance_phase_duration_seconds",
+			Help:    "Time which the cluster template instance spent in a phase",
+			Buckets: []float64{1, 10, 30, 60, 300, 600, 1200, 1800, 3600, 7200},
+		},
+		[]string{"template", "phase"},
+	)
+	ClusterSetupFailures = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clustertemplate_cluster_setup_failures_total",
+			Help: "Number of cluster setups which failed",
+		},
+		[]string{"appset"},
+	)
+	ChartFetchDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "clustertemplate_chart_fetch_duration_seconds",
+			Help:    "Time of fetching a helm chart from a repository",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"repository"},
+	)
+	ChartFetchErrors = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "clustertemplate_chart_fetch_errors_total",
+			Help: "Number of failed fetches of a helm chart from a repository",
+		},
+		[]string{"repository"},
+	)
+
+	instancesDesc = prometheus.NewDesc(
+		"clustertemplate_instances",
+		"Number of cluster template instances",
+		[]string{"namespace", "template", "phase"},
+		nil,
+	)
+	quotaBudgetDesc = prometheus.NewDesc(
+		"clustertemplate_quota_budget",
+		"Budget allowed by the cluster template quota",
+		[]string{"namespace", "quota"},
+		nil,
+	)
+	quotaBudgetSpentDesc = prometheus.NewDesc(
+		"clustertemplate_quota_budget_spent",
+		"Budget spent in the cluster template quota",
+		[]string{"namespace", "quota"},
+		nil,
+	)
+	clusterQuotaBudgetDesc = prometheus.NewDesc(
+		"clustertemplate_cluster_quota_budget",
+		"Budget allowed by the cluster template cluster quota",
+		[]string{"quota"},
+		nil,
+	)
+	clusterQuotaBudgetSpentDesc = prometheus.NewDesc(
+		"clustertemplate_cluster_quota_budget_spent",
+		"Budget spent in the cluster template cluster quota",
+		[]string{"quota"},
+		nil,
+	)
+
+	phases     = map[types.UID]phaseStart{}
+	phasesLock sync.Mutex
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ProvisioningDuration,
+		PhaseDuration,
+		ClusterSetupFailures,
+		ChartFetchDuration,
+		ChartFetchErrors,
+	)
+}
+
+type phaseStart struct {
+	phase v1alpha1.Phase
+	since time.Time
+}
+
+// ObservePhase records the current phase of the instance. Once the phase changes, the time spent
+// in the previous phase is observed. Phases which started before the operator started are not
+// observed.
+func ObservePhase(instance *v1alpha1.ClusterTemplateInstance, now time.Time) {
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+
+	start, ok := phases[instance.UID]
+	if ok && start.phase == instance.Status.Phase {
+		return
+	}
+	if ok {
+		PhaseDuration.WithLabelValues(
+			instance.Spec.ClusterTemplateRef,
+			string(start.phase),
+		).Observe(now.Sub(start.since).Seconds())
+	}
+	phases[instance.UID] = phaseStart{phase: instance.Status.Phase, since: now}
+}
+
+// ObserveProvisioning observes the provisioning duration once the cluster of the instance becomes
+// ready for the first time. Instances which return to the ready phase, ie after a power state
+// change, a parameters update, an upgrade or a failure, were already provisioned.
+func ObserveProvisioning(
+	instance *v1alpha1.ClusterTemplateInstance,
+	previousPhase v1alpha1.Phase,
+	now time.Time,
+) {
+	if instance.Status.Phase != v1alpha1.ReadyPhase {
+		return
+	}
+	switch previousPhase {
+	case v1alpha1.ReadyPhase,
+		v1alpha1.HibernatingPhase,
+		v1alpha1.HibernatedPhase,
+		v1alpha1.ResumingPhase,
+		v1alpha1.PowerStateFailedPhase,
+		v1alpha1.ParametersUpdateFailedPhase:
+		return
+	}
+	if !isFirstReady(instance) {
+		return
+	}
+	ProvisioningDuration.WithLabelValues(instance.Spec.ClusterTemplateRef).Observe(
+		now.Sub(instance.CreationTimestamp.Time).Seconds(),
+	)
+}
+
+// Returns true if the history doesn't contain an earlier Ready transition. The oldest transitions
+// of a full history are removed, so the instance could have been ready before.
+func isFirstReady(instance *v1alpha1.ClusterTemplateInstance) bool {
+	history := instance.Status.History
+	if len(history) >= v1alpha1.MaxPhaseHistory {
+		return false
+	}
+	readyTransitions := 0
+	for _, transition := range history {
+		if transition.Phase == v1alpha1.ReadyPhase {
+			readyTransitions++
+		}
+	}
+	// The last transition is the current one
+	if len(history) > 0 && history[len(history)-1].Phase == v1alpha1.ReadyPhase {
+		readyTransitions--
+	}
+	return readyTransitions == 0
+}
+
+// ForgetInstance stops tracking the phase of the removed instance
+func ForgetInstance(instance *v1alpha1.ClusterTemplateInstance) {
+	phasesLock.Lock()
+	defer phasesLock.Unlock()
+	delete(phases, instance.UID)
+}
+
+// Reports the number of instances and the budget of the quotas when the metrics are scraped, so
+// removed instances and quotas are not reported
+type stateCollector struct {
+	reader client.Reader
+}
+
+// RegisterStateCollector registers the collector of the instance and quota metrics
+func RegisterStateCollector(reader client.Reader) error {
+	return metrics.Registry.Register(&stateCollector{reader: reader})
+}
+
+func (c *stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- instancesDesc
+	ch <- quotaBudgetDesc
+	ch <- quotaBudgetSpentDesc
+	ch <- clusterQuotaBudgetDesc
+	ch <- clusterQuotaBudgetSpentDesc
+}
+
+func (c *stateCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), collectTimeout)
+	defer cancel()
+
+	instances := &v1alpha1.ClusterTemplateInstanceList{}
+	if err := c.reader.List(ctx, instances); err != nil {
+		metricsLog.Error(err, "failed to list cluster template instances")
+	} else {
+		type instanceKey struct {
+			namespace string
+			template  string
+			phase     v1alpha1.Phase
+		}
+		counts := map[instanceKey]int{}
+		for _, instance := range instances.Items {
+			counts[instanceKey{
+				namespace: instance.Namespace,
+				template:  instance.Spec.ClusterTemplateRef,
+				phase:     instance.Status.Phase,
+			}]++
+		}
+		for key, count := range counts {
+			ch <- prometheus.MustNewConstMetric(
+				instancesDesc,
+				prometheus.GaugeValue,
+				float64(count),
+				key.namespace,
+				key.template,
+				string(key.phase),
+			)
+		}
+	}
+
+	quotas := &v1alpha1.ClusterTemplateQuotaList{}
+	if err := c.reader.List(ctx, quotas); err != nil {
+		metricsLog.Error(err, "failed to list cluster template quotas")
+	} else {
+		for _, quota := range quotas.Items {
+			// Quotas without budget do not limit the spent budget
+			if quota.Spec.Budget > 0 {
+				ch <- prometheus.MustNewConstMetric(
+					quotaBudgetDesc,
+					prometheus.GaugeValue,
+					float64(quota.Spec.Budget),
+					quota.Namespace,
+					quota.Name,
+				)
+			}
+			ch <- prometheus.MustNewConstMetric(
+				quotaBudgetSpentDesc,
+				prometheus.GaugeValue,
+				float64(quota.Status.BudgetSpent),
+				quota.Namespace,
+				quota.Name,
+			)
+		}
+	}
+
+	clusterQuotas := &v1alpha1.ClusterTemplateClusterQuotaList{}
+	if err := c.reader.List(ctx, clusterQuotas); err != nil {
+		metricsLog.Error(err, "failed to list cluster template cluster quotas")
+	} else {
+		for _, quota := range clusterQuotas.Items {
+			if quota.Spec.Budget > 0 {
+				ch <- prometheus.MustNewConstMetric(
+					clusterQuotaBudgetDesc,
+					prometheus.GaugeValue,
+					float64(quota.Spec.Budget),
+					quota.Name,
+				)
+			}
+			ch <- prometheus.MustNewConstMetric(
+				clusterQuotaBudgetSpentDesc,
+				prometheus.GaugeValue,
+				float64(quota.Status.BudgetSpent),
+				quota.Name,
+			)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package metrics
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+func getSampleCount(histogram *prometheus.HistogramVec, labels ...string) uint64 {
+	metric := &dto.Metric{}
+	err := histogram.WithLabelValues(labels...).(prometheus.Histogram).Write(metric)
+	Expect(err).ToNot(HaveOccurred())
+	return metric.GetHistogram().GetSampleCount()
+}
+
+func getInstance(name string, phase v1alpha1.Phase, created time.Time) *v1alpha1.ClusterTemplateInstance {
+	return &v1alpha1.ClusterTemplateInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: v1alpha1.ClusterTemplateInstanceSpec{
+			ClusterTemplateRef: "metrics-tmp",
+		},
+		Status: v1alpha1.ClusterTemplateInstanceStatus{
+			Phase: phase,
+		},
+	}
+}
+
+var _ = Describe("Metrics", func() {
+	It("Observes time spent in phases", func() {
+		now := time.Now()
+		instance := getInstance("phases", v1alpha1.ClusterInstallingPhase, now)
+		before := getSampleCount(PhaseDuration, "metrics-tmp", string(v1alpha1.ClusterInstallingPhase))
+
+		ObservePhase(instance, now)
+		ObservePhase(instance, now.Add(time.Minute))
+		Expect(
+			getSampleCount(PhaseDuration, "metrics-tmp", string(v1alpha1.ClusterInstallingPhase)),
+		).To(Equal(before))
+
+		instance.Status.Phase = v1alpha1.ReadyPhase
+		ObservePhase(instance, now.Add(2*time.Minute))
+		Expect(
+			getSampleCount(PhaseDuration, "metrics-tmp", string(v1alpha1.ClusterInstallingPhase)),
+		).To(Equal(before + 1))
+
+		ForgetInstance(instance)
+		Expect(phases).ToNot(HaveKey(instance.UID))
+	})
+
+	It("Observes provisioning duration once", func() {
+		now := time.Now()
+		instance := getInstance("provisioning", v1alpha1.ReadyPhase, now.Add(-time.Hour))
+		before := getSampleCount(ProvisioningDuration, "metrics-tmp")
+
+		ObserveProvisioning(instance, v1alpha1.ClusterSetupRunningPhase, now)
+		Expect(getSampleCount(ProvisioningDuration, "metrics-tmp")).To(Equal(before + 1))
+
+		ObserveProvisioning(instance, v1alpha1.ReadyPhase, now)
+		ObserveProvisioning(instance, v1alpha1.ResumingPhase, now)
+		Expect(getSampleCount(ProvisioningDuration, "metrics-tmp")).To(Equal(before + 1))
+	})
+
+	It("Observes provisioning only on the first ready", func() {
+		now := time.Now()
+		instance := getInstance("reprovisioning", v1alpha1.ReadyPhase, now.Add(-24*time.Hour))
+		for _, phase := range []v1alpha1.Phase{
+			v1alpha1.ClusterInstallingPhase,
+			v1alpha1.ReadyPhase,
+			v1alpha1.ClusterInstallingPhase,
+			v1alpha1.ReadyPhase,
+		} {
+			instance.Status.History = append(instance.Status.History, v1alpha1.PhaseTransition{
+				Phase: phase,
+			})
+		}
+		before := getSampleCount(ProvisioningDuration, "metrics-tmp")
+
+		// Day-1 application resyncs after a parameters update or an upgrade
+		ObserveProvisioning(instance, v1alpha1.ClusterInstallingPhase, now)
+		ObserveProvisioning(instance, v1alpha1.ClusterSetupDegradedPhase, now)
+		Expect(getSampleCount(ProvisioningDuration, "metrics-tmp")).To(Equal(before))
+
+		instance.Status.History = instance.Status.History[:2]
+		ObserveProvisioning(instance, v1alpha1.ClusterInstallingPhase, now)
+		Expect(getSampleCount(ProvisioningDuration, "metrics-tmp")).To(Equal(before + 1))
+	})
+
+	It("Collects instances and quotas", func() {
+		now := time.Now()
+		quota := &v1alpha1.ClusterTemplateQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "quota",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateQuotaSpec{
+				Budget: 10,
+			},
+			Status: v1alpha1.ClusterTemplateQuotaStatus{
+				BudgetSpent: 4,
+			},
+		}
+		clusterQuota := &v1alpha1.ClusterTemplateClusterQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-quota",
+			},
+			Status: v1alpha1.ClusterTemplateClusterQuotaStatus{
+				BudgetSpent: 6,
+			},
+		}
+		client := fake.NewFakeClientWithScheme(
+			scheme.Scheme,
+			getInstance("ready1", v1alpha1.ReadyPhase, now),
+			getInstance("ready2", v1alpha1.ReadyPhase, now),
+			getInstance("installing", v1alpha1.ClusterInstallingPhase, now),
+			quota,
+			clusterQuota,
+		)
+
+		expected := `
+# HELP clustertemplate_instances Number of cluster template instances
+# TYPE clustertemplate_instances gauge
+clustertemplate_instances{namespace="default",phase="ClusterInstalling",template="metrics-tmp"} 1
+clustertemplate_instances{namespace="default",phase="Ready",template="metrics-tmp"} 2
+# HELP clustertemplate_quota_budget Budget allowed by the cluster template quota
+# TYPE clustertemplate_quota_budget gauge
+clustertemplate_quota_budget{namespace="default",quota="quota"} 10
+# HELP clustertemplate_quota_budget_spent Budget spent in the cluster template quota
+# TYPE clustertemplate_quota_budget_spent gauge
+clustertemplate_quota_budget_spent{namespace="default",quota="quota"} 4
+# HELP clustertemplate_cluster_quota_budget_spent Budget spent in the cluster template cluster quota
+# TYPE clustertemplate_cluster_quota_budget_spent gauge
+clustertemplate_cluster_quota_budget_spent{quota="cluster-quota"} 6
+`
+		err := testutil.CollectAndCompare(&stateCollector{reader: client}, strings.NewReader(expected))
+		Expect(err).ToNot(HaveOccurred())
+	})
+})
@@ -0,0 +1,319 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"syscall"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+const (
+	SignatureHeader = "X-Signature-256"
+
+	defaultBody = `{
+  "namespace": {{ json .Namespace }},
+  "instance": {{ json .Instance }},
+  "template": {{ json .Template }},
+  "event": {{ json .Event }},
+  "phase": {{ json .Phase }},
+  "message": {{ json .Message }},
+  "expiresAt": {{ json .ExpiresAt }}
+}`
+)
+
+var (
+	// Notifications are not delivered to cluster-internal, loopback and link-local addresses unless
+	// the addresses are in the networks allowed by the configuration
+	dialer = &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			return checkAddress(address)
+		},
+	}
+	httpClient = &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+	}
+	// Replaced by the tests
+	sendMail = dialAndSendMail
+
+	allowedNetworks []*net.IPNet
+)
+
+// SetAllowedNetworks sets the networks in CIDR notation which notifications can be delivered to
+// even though they are cluster-internal
+func SetAllowedNetworks(cidrs []string) error {
+	networks := []*net.IPNet{}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("invalid allowed network %q - %q", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	allowedNetworks = networks
+	return nil
+}
+
+// Checks the resolved address which is dialed
+func checkAddress(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("invalid address %q", host)
+	}
+	for _, network := range allowedNetworks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("address %s is not allowed", ip)
+	}
+	return nil
+}
+
+// Data of a delivery which are available in the body template
+type Data struct {
+	Namespace string
+	Instance  string
+	Template  string
+	Event     string
+	Phase     string
+	Message   string
+	// Expiration of the instance in RFC 3339 format, empty if the instance does not expire
+	ExpiresAt string
+}
+
+func GetData(namespace string, delivery *v1alpha1.NotificationDelivery) Data {
+	data := Data{
+		Namespace: namespace,
+		Instance:  delivery.Instance,
+		Template:  delivery.Template,
+		Event:     string(delivery.Event),
+		Phase:     string(delivery.Phase),
+		Message:   delivery.Message,
+	}
+	if delivery.ExpiresAt != nil {
+		data.ExpiresAt = delivery.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return data
+}
+
+// RenderBody renders the body template of the webhook, the default body is used if the template
+// is empty
+func RenderBody(body string, data Data) ([]byte, error) {
+	if body == "" {
+		body = defaultBody
+	}
+	tmpl, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			quoted, err := json.Marshal(value)
+			return string(quoted), err
+		},
+	}).Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse body template - %q", err)
+	}
+	rendered := &bytes.Buffer{}
+	if err := tmpl.Execute(rendered, data); err != nil {
+		return nil, fmt.Errorf("failed to render body template - %q", err)
+	}
+	return rendered.Bytes(), nil
+}
+
+// Sign returns the HMAC-SHA256 signature of the body
+func Sign(body []byte, key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers the notification via all configured channels
+func Send(
+	ctx context.Context,
+	k8sClient client.Client,
+	notification *v1alpha1.ClusterTemplateNotification,
+	delivery *v1alpha1.NotificationDelivery,
+) error {
+	data := GetData(notification.Namespace, delivery)
+	errs := []string{}
+	if notification.Spec.Webhook != nil {
+		if err := sendWebhook(ctx, k8sClient, notification.Namespace, notification.Spec.Webhook, data); err != nil {
+			errs = append(errs, fmt.Sprintf("webhook: %s", err))
+		}
+	}
+	if notification.Spec.Email != nil {
+		if err := sendEmail(ctx, k8sClient, notification.Namespace, notification.Spec.Email, data); err != nil {
+			errs = append(errs, fmt.Sprintf("email: %s", err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+func sendWebhook(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace string,
+	webhook *v1alpha1.WebhookNotification,
+	data Data,
+) error {
+	body, err := RenderBody(webhook.Body, data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.HMACSecretRef != nil {
+		key, err := getSecretValue(ctx, k8sClient, namespace, webhook.HMACSecretRef.Name, webhook.HMACSecretRef.Key)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(SignatureHeader, Sign(body, key))
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected response status %q", resp.Status)
+	}
+	return nil
+}
+
+func sendEmail(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace string,
+	email *v1alpha1.EmailNotification,
+	data Data,
+) error {
+	var auth smtp.Auth
+	if email.CredentialsSecretRef != nil {
+		username, err := getSecretValue(ctx, k8sClient, namespace, email.CredentialsSecretRef.Name, "username")
+		if err != nil {
+			return err
+		}
+		password, err := getSecretValue(ctx, k8sClient, namespace, email.CredentialsSecretRef.Name, "password")
+		if err != nil {
+			return err
+		}
+		host := strings.Split(email.SMTPServer, ":")[0]
+		auth = smtp.PlainAuth("", string(username), string(password), host)
+	}
+	return sendMail(email.SMTPServer, auth, email.From, email.To, GetEmailMessage(email, data))
+}
+
+// Sends the email like smtp.SendMail, but dials the server with the dialer which checks the address
+func dialAndSendMail(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return err
+	}
+	conn, err := dialer.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := c.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// GetEmailMessage returns the email with the headers
+func GetEmailMessage(email *v1alpha1.EmailNotification, data Data) []byte {
+	message := &bytes.Buffer{}
+	fmt.Fprintf(message, "From: %s\r\n", email.From)
+	fmt.Fprintf(message, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(message, "Subject: Cluster %s/%s: %s\r\n", data.Namespace, data.Instance, data.Event)
+	fmt.Fprintf(message, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(message, "Cluster template instance: %s/%s\r\n", data.Namespace, data.Instance)
+	fmt.Fprintf(message, "Template: %s\r\n", data.Template)
+	fmt.Fprintf(message, "Phase: %s\r\n", data.Phase)
+	fmt.Fprintf(message, "Message: %s\r\n", data.Message)
+	if data.ExpiresAt != "" {
+		fmt.Fprintf(message, "Expires at: %s\r\n", data.ExpiresAt)
+	}
+	return message.Bytes()
+}
+
+func getSecretValue(
+	ctx context.Context,
+	k8sClient client.Client,
+	namespace string,
+	name string,
+	key string,
+) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get secret %q - %q", name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %q does not contain key %q", name, key)
+	}
+	return value, nil
+}
@@ -0,0 +1,198 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/cluster-templates-operator/api/v1alpha1"
+)
+
+var _ = Describe("Notification", func() {
+	expiresAt := metav1.NewTime(time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC))
+	delivery := &v1alpha1.NotificationDelivery{
+		Instance:  "foo",
+		Template:  "foo-tmp",
+		Event:     v1alpha1.ExpiringNotificationEvent,
+		Phase:     v1alpha1.ReadyPhase,
+		Message:   "Cluster is \"ready\"",
+		ExpiresAt: &expiresAt,
+	}
+
+	BeforeEach(func() {
+		// The test servers listen on the loopback
+		Expect(SetAllowedNetworks([]string{"127.0.0.0/8"})).Should(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(SetAllowedNetworks(nil)).Should(Succeed())
+	})
+
+	It("Renders default body", func() {
+		body, err := RenderBody("", GetData("default", delivery))
+		Expect(err).ToNot(HaveOccurred())
+		data := map[string]string{}
+		Expect(json.Unmarshal(body, &data)).Should(Succeed())
+		Expect(data).Should(Equal(map[string]string{
+			"namespace": "default",
+			"instance":  "foo",
+			"template":  "foo-tmp",
+			"event":     "Expiring",
+			"phase":     "Ready",
+			"message":   "Cluster is \"ready\"",
+			"expiresAt": "2022-10-01T12:00:00Z",
+		}))
+	})
+
+	It("Renders custom body", func() {
+		body, err := RenderBody(`{"text": {{ json (printf "%s is %s" .Instance .Event) }}}`, GetData("default", delivery))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).Should(Equal(`{"text": "foo is Expiring"}`))
+
+		_, err = RenderBody(`{{ .Foo`, GetData("default", delivery))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Sends signed webhook", func() {
+		var received []byte
+		var signature string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received, _ = io.ReadAll(r.Body)
+			signature = r.Header.Get(SignatureHeader)
+		}))
+		defer server.Close()
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hmac",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"key": []byte("secret"),
+			},
+		}
+		ctn := &v1alpha1.ClusterTemplateNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "notification",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateNotificationSpec{
+				Webhook: &v1alpha1.WebhookNotification{
+					URL: server.URL,
+					HMACSecretRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "hmac"},
+						Key:                  "key",
+					},
+				},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, secret)
+		Expect(Send(ctx, client, ctn, delivery)).Should(Succeed())
+		Expect(received).ToNot(BeEmpty())
+		Expect(signature).Should(Equal(Sign(received, []byte("secret"))))
+
+		ctn.Spec.Webhook.HMACSecretRef.Key = "missing"
+		Expect(Send(ctx, client, ctn, delivery)).ShouldNot(Succeed())
+	})
+
+	It("Fails on error response", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctn := &v1alpha1.ClusterTemplateNotification{
+			Spec: v1alpha1.ClusterTemplateNotificationSpec{
+				Webhook: &v1alpha1.WebhookNotification{URL: server.URL},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme)
+		err := Send(ctx, client, ctn, delivery)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("500"))
+	})
+
+	It("Blocks internal addresses", func() {
+		received := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = true
+		}))
+		defer server.Close()
+		Expect(SetAllowedNetworks(nil)).Should(Succeed())
+
+		ctn := &v1alpha1.ClusterTemplateNotification{
+			Spec: v1alpha1.ClusterTemplateNotificationSpec{
+				Webhook: &v1alpha1.WebhookNotification{URL: server.URL},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme)
+		err := Send(ctx, client, ctn, delivery)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).Should(ContainSubstring("address 127.0.0.1 is not allowed"))
+		Expect(received).To(BeFalse())
+
+		for _, address := range []string{"10.0.0.1:443", "169.254.169.254:80", "[::1]:25", "0.0.0.0:80"} {
+			Expect(checkAddress(address)).ShouldNot(Succeed())
+		}
+		Expect(checkAddress("8.8.8.8:443")).Should(Succeed())
+
+		Expect(SetAllowedNetworks([]string{"10.0.0.0/24"})).Should(Succeed())
+		Expect(checkAddress("10.0.0.1:443")).Should(Succeed())
+		Expect(checkAddress("10.0.1.1:443")).ShouldNot(Succeed())
+		Expect(SetAllowedNetworks([]string{"foo"})).ShouldNot(Succeed())
+	})
+
+	It("Sends email", func() {
+		defer func() { sendMail = dialAndSendMail }()
+		var addr string
+		var to []string
+		var msg []byte
+		var auth smtp.Auth
+		sendMail = func(a string, au smtp.Auth, from string, t []string, m []byte) error {
+			addr, auth, to, msg = a, au, t, m
+			return nil
+		}
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "smtp",
+				Namespace: "default",
+			},
+			Data: map[string][]byte{
+				"username": []byte("user"),
+				"password": []byte("pass"),
+			},
+		}
+		ctn := &v1alpha1.ClusterTemplateNotification{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "notification",
+				Namespace: "default",
+			},
+			Spec: v1alpha1.ClusterTemplateNotificationSpec{
+				Email: &v1alpha1.EmailNotification{
+					SMTPServer:           "smtp.example.com:587",
+					From:                 "caas@example.com",
+					To:                   []string{"dev@example.com"},
+					CredentialsSecretRef: &corev1.LocalObjectReference{Name: "smtp"},
+				},
+			},
+		}
+		client := fake.NewFakeClientWithScheme(scheme.Scheme, secret)
+		Expect(Send(ctx, client, ctn, delivery)).Should(Succeed())
+		Expect(addr).Should(Equal("smtp.example.com:587"))
+		Expect(auth).ToNot(BeNil())
+		Expect(to).Should(Equal([]string{"dev@example.com"}))
+		Expect(string(msg)).Should(ContainSubstring("Subject: Cluster default/foo: Expiring\r\n"))
+		Expect(string(msg)).Should(ContainSubstring("Expires at: 2022-10-01T12:00:00Z\r\n"))
+	})
+})
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"time"
 
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/repo"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/cluster-templates-operator/metrics"
 )
 
 type HelmRepositoryIndex struct {
@@ -19,6 +22,8 @@ type HelmRepositoryIndex struct {
 	Name  string          `json:"name,omitempty"`
 }
 
+// GetChart fetches the chart from the helm repository, the latency and the errors are recorded
+// in the metrics of the repository
 func GetChart(
 	ctx context.Context,
 	k8sClient client.Client,
@@ -27,6 +32,23 @@ func GetChart(
 	version string,
 	argoCDNamespace string,
 ) (*chart.Chart, error) {
+	start := time.Now()
+	helmChart, err := fetchChart(ctx, k8sClient, repoURL, chartName, version, argoCDNamespace)
+	metrics.ChartFetchDuration.WithLabelValues(repoURL).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.ChartFetchErrors.WithLabelValues(repoURL).Inc()
+	}
+	return helmChart, err
+}
+
+func fetchChart(
+	ctx context.Context,
+	k8sClient client.Client,
+	repoURL string,
+	chartName string,
+	version string,
+	argoCDNamespace string,
+) (*chart.Chart, error) {
 
 	secret, err := GetRepoSecret(ctx, k8sClient, argoCDNamespace, repoURL)
 	if err != nil {
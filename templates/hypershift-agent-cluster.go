@@ -49,8 +49,10 @@ var HypershiftAgentClusterAppSet = &argo.ApplicationSet{
 					TargetRevision: "0.0.1",
 					Chart:          "hypershift-agent-template",
 				},
+				IgnoreDifferences: nodePoolScalingDifferences,
 				SyncPolicy: &argo.SyncPolicy{
-					Automated: &argo.SyncPolicyAutomated{},
+					Automated:   &argo.SyncPolicyAutomated{},
+					SyncOptions: argo.SyncOptions{}.AddOption("RespectIgnoreDifferences=true"),
 				},
 			},
 		},
@@ -11,6 +11,15 @@ import (
 // default cost of default templates
 var cost int = 1
 
+// NodePools are scaled by hibernation, ArgoCD must not revert their scaling
+var nodePoolScalingDifferences = []argo.ResourceIgnoreDifferences{
+	{
+		Group:        "hypershift.openshift.io",
+		Kind:         "NodePool",
+		JSONPointers: []string{"/spec/replicas", "/spec/autoScaling"},
+	},
+}
+
 //go:embed hypershift-cluster-description.md
 var hypershiftClusterDescription string
 
@@ -52,8 +61,10 @@ var HypershiftClusterAppSet = &argo.ApplicationSet{
 					TargetRevision: "0.0.2",
 					Chart:          "hypershift-template",
 				},
+				IgnoreDifferences: nodePoolScalingDifferences,
 				SyncPolicy: &argo.SyncPolicy{
-					Automated: &argo.SyncPolicyAutomated{},
+					Automated:   &argo.SyncPolicyAutomated{},
+					SyncOptions: argo.SyncOptions{}.AddOption("RespectIgnoreDifferences=true"),
 				},
 			},
 		},
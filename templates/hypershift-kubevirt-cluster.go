@@ -48,8 +48,10 @@ var HypershiftKubevirtClusterAppSet = &argo.ApplicationSet{
 					TargetRevision: "0.0.3",
 					Chart:          "hypershift-kubevirt-template",
 				},
+				IgnoreDifferences: nodePoolScalingDifferences,
 				SyncPolicy: &argo.SyncPolicy{
-					Automated: &argo.SyncPolicyAutomated{},
+					Automated:   &argo.SyncPolicyAutomated{},
+					SyncOptions: argo.SyncOptions{}.AddOption("RespectIgnoreDifferences=true"),
 				},
 			},
 		},